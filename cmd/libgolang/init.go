@@ -22,6 +22,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/outlier"
+	"github.com/aigw-project/aigw/pkg/metrics_stats"
+	"github.com/aigw-project/aigw/pkg/runtimeconfig"
 )
 
 const (
@@ -60,6 +64,8 @@ func startProm() {
 			prometheus.DefaultGatherer,
 			promhttp.HandlerOpts{},
 		))
+		mux.Handle(outlier.DebugPath, outlier.DebugHandler(outlier.GetDetector()))
+		mux.Handle(runtimeconfig.DebugPath, runtimeconfig.DebugHandler())
 
 		address := os.Getenv(AIGW_PROMETHEUS_ADDRESS)
 		if address != "" {
@@ -71,7 +77,24 @@ func startProm() {
 	}()
 }
 
+func loadTTFTModels() {
+	if err := metrics_stats.LoadTTFTModels(); err != nil {
+		// fall back to the default model
+		api.LogErrorf("failed to load ttft models: %v", err)
+	}
+}
+
+func watchRuntimeConfig() {
+	if err := runtimeconfig.WatchFileFromEnv(); err != nil {
+		// fall back to the env variables
+		api.LogErrorf("failed to watch runtime config: %v", err)
+	}
+}
+
 func init() {
+	watchRuntimeConfig()
 	startPprof()
 	startProm()
+	loadTTFTModels()
+	handleShutdown()
 }
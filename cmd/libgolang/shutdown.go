@@ -0,0 +1,62 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/async_log"
+	"github.com/aigw-project/aigw/pkg/common"
+	"github.com/aigw-project/aigw/pkg/metrics_stats"
+	"github.com/aigw-project/aigw/pkg/trace"
+)
+
+const AIGW_LLM_LOG_FLUSH_TIMEOUT = "AIGW_LLM_LOG_FLUSH_TIMEOUT"
+
+// handleShutdown flushes the LLM logs and the spans, and saves the TTFT models when Envoy is asked to shut down.
+// The Go filter has no shutdown callback, so we intercept the termination signals, flush the logs,
+// then restore the original handler of Envoy and re-raise the signal to let Envoy shut down as usual.
+func handleShutdown() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+
+	go func() {
+		sig := <-sigs
+		timeout := common.GetDurationFromEnv(AIGW_LLM_LOG_FLUSH_TIMEOUT, 5*time.Second)
+		api.LogInfof("received signal %v, flushing llm logs", sig)
+		if err := async_log.Close(timeout); err != nil {
+			api.LogErrorf("failed to flush llm logs: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		if err := trace.Shutdown(ctx); err != nil {
+			api.LogErrorf("failed to flush spans: %v", err)
+		}
+		cancel()
+		if err := metrics_stats.SaveTTFTModels(); err != nil {
+			api.LogErrorf("failed to save ttft models: %v", err)
+		}
+
+		signal.Reset(sig)
+		if err := syscall.Kill(os.Getpid(), sig.(syscall.Signal)); err != nil {
+			api.LogErrorf("failed to re-raise signal %v: %v", sig, err)
+		}
+	}()
+}
@@ -0,0 +1,189 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// ttft-fit fits the TTFT prediction models from the LLM log, and writes them in the snapshot format
+// which can be imported by the gateway via AIGW_TTFT_MODEL_IMPORT_PATH.
+//
+//	go run ./cmd/ttft-fit -log /home/admin/logs/llm.log -output ttft-models.json
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aigw-project/aigw/pkg/prediction"
+	llmlog "github.com/aigw-project/aigw/plugins/llmproxy/log"
+)
+
+type options struct {
+	logPath    string
+	outputPath string
+	lambda     float64
+	minSamples int64
+	models     map[string]struct{}
+}
+
+func parseFlags() (*options, error) {
+	opts := &options{}
+	var models string
+	flag.StringVar(&opts.logPath, "log", "-", "path of the LLM log in JSONL, - for stdin")
+	flag.StringVar(&opts.outputPath, "output", "", "path of the fitted models, stdout if empty")
+	flag.Float64Var(&opts.lambda, "lambda", 1, "forgetting factor of the RLS models, in (0, 1]")
+	flag.Int64Var(&opts.minSamples, "min-samples", 20, "models with fewer samples are skipped")
+	flag.StringVar(&models, "models", "", "comma separated models to fit, all models if empty")
+	flag.Parse()
+
+	if opts.lambda <= 0 || opts.lambda > 1 {
+		return nil, fmt.Errorf("invalid lambda %v, should be in (0, 1]", opts.lambda)
+	}
+	if models != "" {
+		opts.models = map[string]struct{}{}
+		for _, m := range strings.Split(models, ",") {
+			opts.models[strings.TrimSpace(m)] = struct{}{}
+		}
+	}
+	return opts, nil
+}
+
+// usable returns whether the record is a valid TTFT sample. Only the successful streaming requests are used,
+// since the first chunk of the non-streaming response arrives after the whole completion is decoded.
+func usable(record *llmlog.LLMLogRecord) bool {
+	return record.Model != "" &&
+		record.Stream &&
+		record.Status >= http.StatusOK && record.Status < http.StatusMultipleChoices &&
+		record.ErrorMessage == "" &&
+		record.TTFTMs > 0 &&
+		record.Usage.PromptTokens > 0
+}
+
+type fitter struct {
+	opts    *options
+	models  map[string]*prediction.RLS
+	samples map[string]int64
+	skipped int64
+}
+
+func (f *fitter) fit(r io.Reader) error {
+	reader := bufio.NewReader(r)
+	for {
+		// the records may carry the whole request, so don't limit the line length like bufio.Scanner
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			f.add(line)
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (f *fitter) add(line []byte) {
+	var record llmlog.LLMLogRecord
+	if err := json.Unmarshal(line, &record); err != nil || !usable(&record) {
+		f.skipped++
+		return
+	}
+	if f.opts.models != nil {
+		if _, ok := f.opts.models[record.Model]; !ok {
+			f.skipped++
+			return
+		}
+	}
+
+	model, ok := f.models[record.Model]
+	if !ok {
+		model = prediction.NewRLS(f.opts.lambda)
+		f.models[record.Model] = model
+	}
+	model.Train(int(record.Usage.PromptTokens), int(record.Usage.CachedTokens), float64(record.TTFTMs))
+	f.samples[record.Model]++
+}
+
+func (f *fitter) snapshot() *prediction.Snapshot {
+	snapshot := &prediction.Snapshot{
+		Version: prediction.SnapshotVersion,
+		Models:  map[string]*prediction.RLSState{},
+	}
+
+	names := make([]string, 0, len(f.models))
+	for name := range f.models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		samples := f.samples[name]
+		if samples < f.opts.minSamples {
+			fmt.Fprintf(os.Stderr, "skip model %s: %d samples\n", name, samples)
+			continue
+		}
+		state := f.models[name].State()
+		state.Samples = samples
+		snapshot.Models[name] = state
+		fmt.Fprintf(os.Stderr, "fitted model %s: %d samples\n", name, samples)
+	}
+	return snapshot
+}
+
+func run() error {
+	opts, err := parseFlags()
+	if err != nil {
+		return err
+	}
+
+	input := os.Stdin
+	if opts.logPath != "-" {
+		input, err = os.Open(opts.logPath)
+		if err != nil {
+			return err
+		}
+		defer input.Close()
+	}
+
+	f := &fitter{
+		opts:    opts,
+		models:  map[string]*prediction.RLS{},
+		samples: map[string]int64{},
+	}
+	if err := f.fit(input); err != nil {
+		return fmt.Errorf("failed to read %s: %w", opts.logPath, err)
+	}
+	fmt.Fprintf(os.Stderr, "skipped %d records\n", f.skipped)
+
+	snapshot := f.snapshot()
+	if len(snapshot.Models) == 0 {
+		return errors.New("no model fitted")
+	}
+	if opts.outputPath != "" {
+		return prediction.SaveSnapshot(opts.outputPath, snapshot)
+	}
+	return json.NewEncoder(os.Stdout).Encode(snapshot)
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "ttft-fit: %v\n", err)
+		os.Exit(1)
+	}
+}
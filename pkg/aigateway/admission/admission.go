@@ -0,0 +1,288 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admission holds the requests in a bounded priority queue when the cluster is saturated,
+// and rejects them early when the queue is full or they wait too long.
+package admission
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+var (
+	ErrOverloaded   = errors.New("cluster is overloaded")
+	ErrQueueFull    = errors.New("admission queue is full")
+	ErrQueueTimeout = errors.New("timeout waiting in admission queue")
+	ErrEvicted      = errors.New("evicted from admission queue by higher priority request")
+)
+
+// dispatchInterval is the interval to check whether the cluster is still saturated when there are waiting requests
+var dispatchInterval = 100 * time.Millisecond
+
+// Limits of the admission, passed in each Admit since they come from the plugin config which could be updated
+type Limits struct {
+	// max waiting requests, requests are rejected immediately when saturated if it's 0
+	MaxQueueSize int
+	MaxWait      time.Duration
+	// Saturated returns whether the cluster is saturated, known is false when the load is stale or missing
+	Saturated func() (saturated bool, known bool)
+	// FailClosed treats the cluster whose load is unknown as saturated. By default, the request is admitted
+	// (fail open) so that the admitted requests refresh the load
+	FailClosed bool
+}
+
+// saturated applies the policy of the unknown load
+func (l Limits) saturated() bool {
+	saturated, known := l.Saturated()
+	if !known {
+		return l.FailClosed
+	}
+	return saturated
+}
+
+type waiter struct {
+	priority uint32
+	seq      uint64
+	index    int
+	ready    chan error
+}
+
+// waiterHeap pops the waiter with the highest priority, and FIFO for the same priority
+type waiterHeap []*waiter
+
+func (h waiterHeap) Len() int {
+	return len(h)
+}
+
+func (h waiterHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h waiterHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waiterHeap) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waiterHeap) Pop() any {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*h = old[:n-1]
+	return w
+}
+
+// lowest returns the waiter which will be dispatched last
+func (h waiterHeap) lowest() *waiter {
+	var res *waiter
+	for _, w := range h {
+		if res == nil || w.priority < res.priority || (w.priority == res.priority && w.seq > res.seq) {
+			res = w
+		}
+	}
+	return res
+}
+
+// Controller is the admission controller of a cluster
+type Controller struct {
+	cluster string
+
+	lock        sync.Mutex
+	queue       waiterHeap
+	seq         uint64
+	limits      Limits
+	dispatching bool
+}
+
+func NewController(cluster string) *Controller {
+	return &Controller{
+		cluster: cluster,
+	}
+}
+
+var controllers sync.Map
+
+// GetController returns the admission controller of the cluster
+func GetController(cluster string) *Controller {
+	if v, ok := controllers.Load(cluster); ok {
+		return v.(*Controller)
+	}
+	v, _ := controllers.LoadOrStore(cluster, NewController(cluster))
+	return v.(*Controller)
+}
+
+// Admit returns nil when the request is allowed to be forwarded to the cluster, it may block up to limits.MaxWait.
+// Release must be called when the admitted request is finished.
+func (c *Controller) Admit(ctx context.Context, priority uint32, limits Limits) error {
+	c.lock.Lock()
+	c.limits = limits
+	if len(c.queue) == 0 && !limits.saturated() {
+		c.lock.Unlock()
+		return nil
+	}
+
+	if limits.MaxQueueSize <= 0 {
+		c.lock.Unlock()
+		c.reject(ErrOverloaded)
+		return ErrOverloaded
+	}
+	if len(c.queue) >= limits.MaxQueueSize {
+		lowest := c.queue.lowest()
+		if lowest.priority >= priority {
+			c.lock.Unlock()
+			c.reject(ErrQueueFull)
+			return ErrQueueFull
+		}
+		heap.Remove(&c.queue, lowest.index)
+		lowest.ready <- ErrEvicted
+	}
+
+	c.seq++
+	w := &waiter{
+		priority: priority,
+		seq:      c.seq,
+		ready:    make(chan error, 1),
+	}
+	heap.Push(&c.queue, w)
+	prom.AdmissionQueueSize.WithLabelValues(c.cluster).Set(float64(len(c.queue)))
+	if !c.dispatching {
+		c.dispatching = true
+		go c.dispatch()
+	}
+	c.lock.Unlock()
+
+	start := time.Now()
+	timer := time.NewTimer(limits.MaxWait)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-w.ready:
+	case <-timer.C:
+		err = c.cancel(w, ErrQueueTimeout)
+	case <-ctx.Done():
+		err = c.cancel(w, ctx.Err())
+	}
+
+	prom.AdmissionWaitDuration.WithLabelValues(c.cluster).Observe(float64(time.Since(start).Milliseconds()))
+	if err != nil {
+		c.reject(err)
+	}
+	return err
+}
+
+// cancel removes the waiter from the queue, unless it's already dispatched
+func (c *Controller) cancel(w *waiter, reason error) error {
+	c.lock.Lock()
+	if w.index >= 0 {
+		heap.Remove(&c.queue, w.index)
+		prom.AdmissionQueueSize.WithLabelValues(c.cluster).Set(float64(len(c.queue)))
+		c.lock.Unlock()
+		return reason
+	}
+	c.lock.Unlock()
+	return <-w.ready
+}
+
+func (c *Controller) reject(err error) {
+	reason := "overloaded"
+	switch {
+	case errors.Is(err, ErrQueueFull):
+		reason = "queue_full"
+	case errors.Is(err, ErrQueueTimeout):
+		reason = "timeout"
+	case errors.Is(err, ErrEvicted):
+		reason = "evicted"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		reason = "canceled"
+	}
+	prom.AdmissionRejectedTotal.WithLabelValues(c.cluster, reason).Inc()
+}
+
+// Release is called when an admitted request is finished, which frees a slot for the waiting requests.
+// The waiting request is left to the dispatcher when the cluster is still saturated.
+func (c *Controller) Release() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(c.queue) == 0 || c.limits.saturated() {
+		return
+	}
+	c.dispatchOne()
+}
+
+// dispatchOne must be called with lock held
+func (c *Controller) dispatchOne() bool {
+	if len(c.queue) == 0 {
+		return false
+	}
+	w := heap.Pop(&c.queue).(*waiter)
+	w.ready <- nil
+	prom.AdmissionQueueSize.WithLabelValues(c.cluster).Set(float64(len(c.queue)))
+	return true
+}
+
+// dispatch admits the waiting requests once the cluster is not saturated
+func (c *Controller) dispatch() {
+	defer func() {
+		if r := recover(); r != nil {
+			api.LogErrorf("admission dispatcher of cluster %s panic: %v", c.cluster, r)
+			c.lock.Lock()
+			c.dispatching = false
+			c.lock.Unlock()
+		}
+	}()
+
+	ticker := time.NewTicker(dispatchInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.lock.Lock()
+		if len(c.queue) == 0 {
+			c.dispatching = false
+			c.lock.Unlock()
+			return
+		}
+		// admit one request per tick, the load will be refreshed by the admitted request
+		if !c.limits.saturated() {
+			c.dispatchOne()
+		}
+		c.lock.Unlock()
+	}
+}
+
+// QueueSize returns the number of waiting requests
+func (c *Controller) QueueSize() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.queue)
+}
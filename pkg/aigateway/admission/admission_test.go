@@ -0,0 +1,201 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+)
+
+func limits(saturated *atomic.Bool, queueSize int, maxWait time.Duration) Limits {
+	return Limits{
+		MaxQueueSize: queueSize,
+		MaxWait:      maxWait,
+		Saturated: func() (bool, bool) {
+			return saturated.Load(), true
+		},
+	}
+}
+
+func waitQueueSize(t *testing.T, c *Controller, n int) {
+	assert.Eventually(t, func() bool {
+		return c.QueueSize() == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestAdmitNotSaturated(t *testing.T) {
+	var saturated atomic.Bool
+	c := NewController("test")
+	assert.NoError(t, c.Admit(context.Background(), 0, limits(&saturated, 0, time.Second)))
+}
+
+func TestRejectWithoutQueue(t *testing.T) {
+	var saturated atomic.Bool
+	saturated.Store(true)
+	c := NewController("test")
+	assert.ErrorIs(t, c.Admit(context.Background(), 0, limits(&saturated, 0, time.Second)), ErrOverloaded)
+}
+
+func TestQueueTimeout(t *testing.T) {
+	var saturated atomic.Bool
+	saturated.Store(true)
+	c := NewController("test")
+	assert.ErrorIs(t, c.Admit(context.Background(), 0, limits(&saturated, 1, 10*time.Millisecond)), ErrQueueTimeout)
+	assert.Equal(t, 0, c.QueueSize())
+}
+
+func TestPriority(t *testing.T) {
+	var saturated atomic.Bool
+	saturated.Store(true)
+	c := NewController("test")
+	l := limits(&saturated, 2, time.Second)
+
+	var lock sync.Mutex
+	var order []uint32
+	var wg sync.WaitGroup
+	errs := make(map[uint32]error)
+	admit := func(p uint32) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := c.Admit(context.Background(), p, l)
+			lock.Lock()
+			defer lock.Unlock()
+			errs[p] = err
+			if err == nil {
+				order = append(order, p)
+			}
+		}()
+	}
+
+	admit(1)
+	waitQueueSize(t, c, 1)
+	admit(2)
+	waitQueueSize(t, c, 2)
+
+	// queue is full, lower priority is rejected
+	assert.ErrorIs(t, c.Admit(context.Background(), 0, l), ErrQueueFull)
+
+	// higher priority evicts the lowest one
+	admit(3)
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return errs[1] != nil
+	}, time.Second, time.Millisecond)
+	assert.ErrorIs(t, errs[1], ErrEvicted)
+
+	// released slots are given to the highest priority first
+	saturated.Store(false)
+	c.Release()
+	waitQueueSize(t, c, 1)
+	c.Release()
+	wg.Wait()
+	assert.Equal(t, []uint32{3, 2}, order)
+}
+
+func TestDispatchWhenNotSaturated(t *testing.T) {
+	var saturated atomic.Bool
+	saturated.Store(true)
+	c := NewController("test")
+
+	done := make(chan error)
+	go func() {
+		done <- c.Admit(context.Background(), 0, limits(&saturated, 10, time.Second))
+	}()
+	waitQueueSize(t, c, 1)
+
+	saturated.Store(false)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("request is not admitted")
+	}
+}
+
+func TestReleaseWhenSaturated(t *testing.T) {
+	interval := dispatchInterval
+	dispatchInterval = time.Hour
+	defer func() { dispatchInterval = interval }()
+
+	var saturated atomic.Bool
+	saturated.Store(true)
+	c := NewController("test")
+
+	done := make(chan error)
+	go func() {
+		done <- c.Admit(context.Background(), 0, limits(&saturated, 10, time.Second))
+	}()
+	waitQueueSize(t, c, 1)
+
+	// the released slot is taken by the other clients of the cluster
+	c.Release()
+	assert.Equal(t, 1, c.QueueSize())
+
+	saturated.Store(false)
+	c.Release()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("request is not admitted")
+	}
+}
+
+func TestUnknownLoad(t *testing.T) {
+	unknown := func() (bool, bool) {
+		return false, false
+	}
+
+	// fail open by default
+	c := NewController("test")
+	assert.NoError(t, c.Admit(context.Background(), 0, Limits{MaxWait: time.Second, Saturated: unknown}))
+
+	l := Limits{MaxWait: time.Second, Saturated: unknown, FailClosed: true}
+	assert.ErrorIs(t, c.Admit(context.Background(), 0, l), ErrOverloaded)
+	l.MaxQueueSize = 1
+	l.MaxWait = 10 * time.Millisecond
+	assert.ErrorIs(t, c.Admit(context.Background(), 0, l), ErrQueueTimeout)
+}
+
+func TestAdmitCanceled(t *testing.T) {
+	var saturated atomic.Bool
+	saturated.Store(true)
+	c := NewController("test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- c.Admit(ctx, 0, limits(&saturated, 10, time.Minute))
+	}()
+	waitQueueSize(t, c, 1)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("waiting request is not canceled")
+	}
+	assert.Equal(t, 0, c.QueueSize())
+}
@@ -33,7 +33,7 @@ func newCluster(info types.ClusterInfo) *Cluster {
 		name: info.Name,
 	}
 
-	cl.manager = cluster.NewClusterManager(info.Name, info.Endpoints)
+	cl.manager = cluster.NewClusterManager(info.Name, info.Endpoints, info.HealthCheck)
 
 	return cl
 }
@@ -55,5 +55,5 @@ func (c *Cluster) NextServer(ctx context.Context, lbType loadbalancertypes.LoadB
 }
 
 func (c *Cluster) updateServers(info *types.ClusterInfo) {
-	c.manager.UpdateCluster(info.Endpoints)
+	c.manager.UpdateCluster(*info)
 }
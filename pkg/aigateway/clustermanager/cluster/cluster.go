@@ -17,44 +17,95 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
 
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/host"
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/manager"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/outlier"
 
+	"github.com/aigw-project/aigw/pkg/aigateway/clustermanager/healthcheck"
 	"github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
 	loadbalancertypes "github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+	pkgcommon "github.com/aigw-project/aigw/pkg/common"
 )
 
+const (
+	// AIGW_HOST_DRAIN_TIMEOUT limits how long a removed host waits for its requests in flight,
+	// before its state, e.g. the outlier detection, is forgotten
+	AIGW_HOST_DRAIN_TIMEOUT = "AIGW_HOST_DRAIN_TIMEOUT"
+
+	defaultDrainTimeout = 10 * time.Minute
+)
+
+var (
+	drainCheckInterval = time.Second
+)
+
+// lbState is the load balancer with its hosts, swapped together
+type lbState struct {
+	lb    loadbalancertypes.LoadBalancer
+	hosts []loadbalancertypes.Host
+}
+
 type Cluster struct {
-	lb        loadbalancertypes.LoadBalancer
-	hosts     []loadbalancertypes.Host
+	state     atomic.Pointer[lbState]
 	lbType    loadbalancertypes.LoadBalancerType
 	lbContext context.Context
 }
 
+// hostSet is an immutable snapshot of the hosts, it's replaced as a whole when the cluster is updated
+type hostSet struct {
+	// all the hosts by address, including the unhealthy ones
+	all map[string]*host.Host
+	// the hosts to choose, in the order of the endpoints
+	hosts []loadbalancertypes.Host
+}
+
+// drainingHost is a host removed from the cluster, which waits for its requests in flight
+type drainingHost struct {
+	host     *host.Host
+	deadline time.Time
+}
+
 type Manager struct {
 	name     string
 	clusters sync.Map
-	mux      sync.Mutex
-	hosts    []loadbalancertypes.Host
-}
+	// mux serializes the updates, the readers use the snapshots without lock
+	mux     sync.Mutex
+	hostSet atomic.Pointer[hostSet]
 
-func createClusterHosts(name string, endpoints []types.Endpoint) []loadbalancertypes.Host {
-	hosts := make([]loadbalancertypes.Host, 0, len(endpoints))
-	for _, server := range endpoints {
-		host := host.BuildHost(name, server.Address, server.Port, 0)
-		host.SetLabels(server.Labels) // set labels for lora and multi version
-		hosts = append(hosts, host)
-	}
-	return hosts
+	// all the endpoints, including the unhealthy ones
+	endpoints []types.Endpoint
+	// active health checker, nil when health check is not configured
+	checker     *healthcheck.Checker
+	healthCheck *types.HealthCheckConfig
+
+	draining     map[string]*drainingHost
+	drainTimeout time.Duration
 }
 
-func NewClusterManager(name string, endpoints []types.Endpoint) *Manager {
+func NewClusterManager(name string, endpoints []types.Endpoint, hc *types.HealthCheckConfig) *Manager {
 	manager := &Manager{
-		name:  name,
-		hosts: createClusterHosts(name, endpoints),
+		name:         name,
+		endpoints:    endpoints,
+		draining:     map[string]*drainingHost{},
+		drainTimeout: pkgcommon.GetDurationFromEnv(AIGW_HOST_DRAIN_TIMEOUT, defaultDrainTimeout),
 	}
+	manager.hostSet.Store(&hostSet{all: map[string]*host.Host{}})
+	manager.mux.Lock()
+	defer manager.mux.Unlock()
+	manager.updateHosts(endpoints)
+	manager.updateHealthCheck(hc)
+	if manager.checker != nil {
+		manager.checker.UpdateEndpoints(endpoints)
+	}
+	manager.rebuild()
 	return manager
 }
 
@@ -75,38 +126,192 @@ func (cm *Manager) GetCluster(ctx context.Context, lbType loadbalancertypes.Load
 	}
 
 	// create a new cluster
-	lb := manager.CreateLbByType(lbType, ctx, cm.hosts)
+	hosts := cm.hostSet.Load().hosts
+	lb := manager.CreateLbByType(lbType, ctx, hosts)
 	if lb == nil {
 		return nil, fmt.Errorf("fail CreateLbByType with type %+v", lbType)
 	}
 	cluster := &Cluster{
-		lb:        lb,
 		lbType:    lbType,
 		lbContext: ctx,
-		hosts:     cm.hosts,
 	}
+	cluster.state.Store(&lbState{lb: lb, hosts: hosts})
 
 	cm.clusters.Store(lbType, cluster)
 	return cluster, nil
 }
 
-func (cm *Manager) UpdateCluster(endpoints []types.Endpoint) {
-	lbHosts := createClusterHosts(cm.name, endpoints)
+// UpdateCluster applies the new endpoints and health check of the cluster
+func (cm *Manager) UpdateCluster(info types.ClusterInfo) {
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+
+	endpoints := info.Endpoints
+	cm.endpoints = endpoints
+	cm.updateHosts(endpoints)
+	cm.updateHealthCheck(info.HealthCheck)
+	if cm.checker != nil {
+		cm.checker.UpdateEndpoints(endpoints)
+	}
+	cm.rebuild()
+}
+
+func sameHealthCheck(a, b *types.HealthCheckConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// updateHealthCheck replaces the health checker when its config changes, the new checker considers all the
+// endpoints healthy until they are probed. Must be called with mux held, the endpoints of the checker need
+// to be updated after it.
+func (cm *Manager) updateHealthCheck(hc *types.HealthCheckConfig) {
+	if sameHealthCheck(cm.healthCheck, hc) {
+		return
+	}
+
+	if cm.checker != nil {
+		cm.checker.Stop()
+		cm.checker = nil
+	}
+	cm.healthCheck = nil
+	if hc == nil {
+		api.LogInfof("disable active health check for cluster %s", cm.name)
+		return
+	}
+
+	config := *hc
+	cm.healthCheck = &config
+	api.LogInfof("enable active health check for cluster %s: %+v", cm.name, config)
+	cm.checker = healthcheck.NewChecker(cm.name, config, cm.onHealthChanged)
+}
+
+func (cm *Manager) onHealthChanged() {
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+
+	cm.rebuild()
+}
+
+// endpointAddress is the same as the host address
+func endpointAddress(ep types.Endpoint) string {
+	return ep.Address + ":" + strconv.Itoa(int(ep.Port))
+}
+
+func sameAttributes(h *host.Host, ep types.Endpoint) bool {
+	return h.Weight() == ep.Weight && maps.Equal(h.Labels(), ep.Labels)
+}
+
+// updateHosts diffs the endpoints with the current hosts: the unchanged hosts are reused, the changed ones keep
+// their state, and the removed ones are drained. Must be called with mux held, rebuild is required after it.
+func (cm *Manager) updateHosts(endpoints []types.Endpoint) {
+	prev := cm.hostSet.Load().all
+	all := make(map[string]*host.Host, len(endpoints))
+	added := 0
+	for _, ep := range endpoints {
+		addr := endpointAddress(ep)
+		var h *host.Host
+		if old, ok := prev[addr]; ok {
+			h = old
+			if !sameAttributes(old, ep) {
+				h = old.WithAttributes(ep.Weight, ep.Labels)
+			}
+		} else if d, ok := cm.draining[addr]; ok {
+			// added back before it's drained
+			delete(cm.draining, addr)
+			d.host.State().SetDraining(false)
+			h = d.host.WithAttributes(ep.Weight, ep.Labels)
+		} else {
+			h = host.BuildHost(cm.name, ep.Address, ep.Port, ep.Weight)
+			h.SetLabels(ep.Labels) // set labels for lora and multi version
+			added++
+		}
+		all[addr] = h
+	}
+
+	removed := 0
+	for addr, h := range prev {
+		if _, ok := all[addr]; ok {
+			continue
+		}
+		removed++
+		h.State().SetDraining(true)
+		d := &drainingHost{host: h, deadline: time.Now().Add(cm.drainTimeout)}
+		cm.draining[addr] = d
+		time.AfterFunc(drainCheckInterval, func() { cm.drain(addr, d) })
+	}
+	if added > 0 || removed > 0 {
+		api.LogInfof("cluster %s updated, hosts: %d, added: %d, removed: %d", cm.name, len(all), added, removed)
+	}
+
+	cm.hostSet.Store(&hostSet{all: all, hosts: cm.hostSet.Load().hosts})
+}
+
+// drain forgets the removed host after its requests in flight finish, or the drain timeout
+func (cm *Manager) drain(addr string, d *drainingHost) {
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+
+	if cm.draining[addr] != d {
+		// added back, or removed again with a new drain
+		return
+	}
+	if inflight := d.host.State().Inflight(); inflight > 0 {
+		if time.Now().Before(d.deadline) {
+			time.AfterFunc(drainCheckInterval, func() { cm.drain(addr, d) })
+			return
+		}
+		api.LogWarnf("host %s in cluster %s is drained with %d requests in flight", addr, cm.name, inflight)
+	}
+	delete(cm.draining, addr)
+	outlier.GetDetector().RemoveHost(cm.name, addr)
+	api.LogInfof("host %s in cluster %s is drained", addr, cm.name)
+}
+
+// rebuild replaces the hosts of the load balancers with the healthy hosts, must be called with mux held.
+// The load balancers which implement HostUpdater are updated in place, the others are recreated.
+func (cm *Manager) rebuild() {
+	endpoints := cm.endpoints
+	if cm.checker != nil {
+		endpoints = cm.checker.HealthyEndpoints(cm.endpoints)
+		if len(endpoints) == 0 && len(cm.endpoints) > 0 {
+			// better than no host to choose
+			api.LogWarnf("all %d endpoints in cluster %s are unhealthy, use all of them", len(cm.endpoints), cm.name)
+			endpoints = cm.endpoints
+		}
+	}
+
+	set := cm.hostSet.Load()
+	lbHosts := make([]loadbalancertypes.Host, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if h, ok := set.all[endpointAddress(ep)]; ok {
+			lbHosts = append(lbHosts, h)
+		}
+	}
+	cm.hostSet.Store(&hostSet{all: set.all, hosts: lbHosts})
+
 	cm.clusters.Range(func(key, value any) bool {
 		cl, ok := value.(*Cluster)
-		if ok {
-			cl.hosts = lbHosts
-			cl.lb = manager.CreateLbByType(cl.lbType, cl.lbContext, lbHosts)
+		if !ok {
+			return true
+		}
+		lb := cl.state.Load().lb
+		if updater, ok := lb.(loadbalancertypes.HostUpdater); ok {
+			updater.UpdateHosts(lbHosts)
+		} else {
+			lb = manager.CreateLbByType(cl.lbType, cl.lbContext, lbHosts)
 		}
+		cl.state.Store(&lbState{lb: lb, hosts: lbHosts})
 		return true
 	})
 }
 
 func (c *Cluster) ChooseHost(ctx context.Context) loadbalancertypes.Host {
-	host := c.lb.ChooseHost(ctx)
+	host := c.state.Load().lb.ChooseHost(ctx)
 	return host
 }
 
 func (c *Cluster) GetHostsNumber() int {
-	return len(c.hosts)
+	return len(c.state.Load().hosts)
 }
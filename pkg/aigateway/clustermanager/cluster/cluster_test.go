@@ -0,0 +1,302 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/host"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/manager"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/outlier"
+	loadbalancertypes "github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+)
+
+const (
+	updaterLB loadbalancertypes.LoadBalancerType = "test_updater"
+	simpleLB  loadbalancertypes.LoadBalancerType = "test_simple"
+)
+
+// updaterBalancer updates the hosts in place
+type updaterBalancer struct {
+	hosts atomic.Pointer[[]loadbalancertypes.Host]
+}
+
+func (lb *updaterBalancer) UpdateHosts(hosts []loadbalancertypes.Host) {
+	lb.hosts.Store(&hosts)
+}
+
+func (lb *updaterBalancer) ChooseHost(ctx context.Context) loadbalancertypes.Host {
+	hosts := *lb.hosts.Load()
+	if len(hosts) == 0 {
+		return nil
+	}
+	return hosts[rand.Intn(len(hosts))]
+}
+
+// simpleBalancer is recreated on update
+type simpleBalancer struct {
+	hosts []loadbalancertypes.Host
+}
+
+func (lb *simpleBalancer) ChooseHost(ctx context.Context) loadbalancertypes.Host {
+	if len(lb.hosts) == 0 {
+		return nil
+	}
+	return lb.hosts[rand.Intn(len(lb.hosts))]
+}
+
+var (
+	updaterCreated atomic.Int32
+	simpleCreated  atomic.Int32
+)
+
+func init() {
+	manager.RegisterLbType(updaterLB, func(ctx context.Context, hosts []loadbalancertypes.Host) loadbalancertypes.LoadBalancer {
+		updaterCreated.Add(1)
+		lb := &updaterBalancer{}
+		lb.UpdateHosts(hosts)
+		return lb
+	})
+	manager.RegisterLbType(simpleLB, func(ctx context.Context, hosts []loadbalancertypes.Host) loadbalancertypes.LoadBalancer {
+		simpleCreated.Add(1)
+		return &simpleBalancer{hosts: hosts}
+	})
+}
+
+func endpoints(ips ...string) []types.Endpoint {
+	eps := make([]types.Endpoint, 0, len(ips))
+	for _, ip := range ips {
+		eps = append(eps, types.Endpoint{Address: ip, Port: 8000})
+	}
+	return eps
+}
+
+func hostOf(cm *Manager, addr string) *host.Host {
+	return cm.hostSet.Load().all[addr]
+}
+
+func isDraining(cm *Manager, addr string) bool {
+	cm.mux.Lock()
+	defer cm.mux.Unlock()
+	_, ok := cm.draining[addr]
+	return ok
+}
+
+func TestUpdateClusterKeepsHostState(t *testing.T) {
+	cm := NewClusterManager("keep", endpoints("10.0.0.1", "10.0.0.2"), nil)
+	ctx := context.Background()
+	updater, err := cm.GetCluster(ctx, updaterLB)
+	assert.NoError(t, err)
+	simple, err := cm.GetCluster(ctx, simpleLB)
+	assert.NoError(t, err)
+	updaterBefore, simpleBefore := updaterCreated.Load(), simpleCreated.Load()
+	lb := updater.state.Load().lb
+
+	h1 := hostOf(cm, "10.0.0.1:8000")
+	h2 := hostOf(cm, "10.0.0.2:8000")
+	h1.RequestStarted()
+
+	// 10.0.0.1 is relabeled, 10.0.0.2 is removed, 10.0.0.3 is added
+	eps := endpoints("10.0.0.1", "10.0.0.3")
+	eps[0].Labels = map[string]string{"lora": "a"}
+	eps[0].Weight = 2
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: eps})
+
+	relabeled := hostOf(cm, "10.0.0.1:8000")
+	assert.NotSame(t, h1, relabeled)
+	assert.Same(t, h1.State(), relabeled.State())
+	assert.Equal(t, int64(1), relabeled.State().Inflight())
+	assert.Equal(t, map[string]string{"lora": "a"}, relabeled.Labels())
+	assert.Equal(t, uint32(2), relabeled.Weight())
+	assert.True(t, h2.State().Draining())
+	assert.True(t, isDraining(cm, "10.0.0.2:8000"))
+
+	// the load balancer with HostUpdater is updated in place, the other one is recreated
+	assert.Same(t, lb, updater.state.Load().lb)
+	assert.Equal(t, updaterBefore, updaterCreated.Load())
+	assert.Equal(t, simpleBefore+1, simpleCreated.Load())
+	assert.Equal(t, 2, updater.GetHostsNumber())
+	assert.Equal(t, 2, simple.GetHostsNumber())
+	for i := 0; i < 20; i++ {
+		assert.NotEqual(t, "10.0.0.2:8000", updater.ChooseHost(ctx).Address())
+	}
+
+	// unchanged hosts are reused
+	h3 := hostOf(cm, "10.0.0.3:8000")
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: eps})
+	assert.Same(t, h3, hostOf(cm, "10.0.0.3:8000"))
+
+	// added back before it's drained
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: endpoints("10.0.0.1", "10.0.0.2", "10.0.0.3")})
+	assert.Same(t, h2.State(), hostOf(cm, "10.0.0.2:8000").State())
+	assert.False(t, h2.State().Draining())
+	assert.False(t, isDraining(cm, "10.0.0.2:8000"))
+}
+
+func TestDrainHost(t *testing.T) {
+	interval := drainCheckInterval
+	drainCheckInterval = 10 * time.Millisecond
+	defer func() { drainCheckInterval = interval }()
+
+	cluster := "drain"
+	cm := NewClusterManager(cluster, endpoints("10.0.0.1", "10.0.0.2"), nil)
+	detector := outlier.GetDetector()
+	tracked := func(addr string) bool {
+		for _, s := range detector.Status() {
+			if s.Cluster == cluster && s.Address == addr {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, addr := range []string{"10.0.0.1:8000", "10.0.0.2:8000"} {
+		detector.RecordError(cluster, addr, outlier.ErrorKindStatus)
+	}
+	h1 := hostOf(cm, "10.0.0.1:8000")
+	h1.RequestStarted()
+	cm.UpdateCluster(types.ClusterInfo{})
+
+	// the idle host is drained at once
+	assert.Eventually(t, func() bool { return !isDraining(cm, "10.0.0.2:8000") }, time.Second, 10*time.Millisecond)
+	assert.False(t, tracked("10.0.0.2:8000"))
+
+	// the busy host waits for the requests in flight
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, isDraining(cm, "10.0.0.1:8000"))
+	assert.True(t, tracked("10.0.0.1:8000"))
+	h1.RequestFinished()
+	assert.Eventually(t, func() bool { return !isDraining(cm, "10.0.0.1:8000") }, time.Second, 10*time.Millisecond)
+	assert.False(t, tracked("10.0.0.1:8000"))
+
+	// the drain timeout
+	cm.drainTimeout = 30 * time.Millisecond
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: endpoints("10.0.0.3")})
+	hostOf(cm, "10.0.0.3:8000").RequestStarted()
+	cm.UpdateCluster(types.ClusterInfo{})
+	assert.Eventually(t, func() bool { return !isDraining(cm, "10.0.0.3:8000") }, time.Second, 10*time.Millisecond)
+}
+
+func TestConcurrentUpdateAndChoose(t *testing.T) {
+	cm := NewClusterManager("race", endpoints("10.0.0.1"), nil)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for _, lbType := range []loadbalancertypes.LoadBalancerType{updaterLB, simpleLB} {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					cl, err := cm.GetCluster(ctx, lbType)
+					assert.NoError(t, err)
+					// there is always at least one host
+					h := cl.ChooseHost(ctx)
+					if assert.NotNil(t, h) {
+						_ = h.Labels()
+					}
+					_ = cl.GetHostsNumber()
+				}
+			}()
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		ips := []string{"10.0.0.1"}
+		for j := 0; j < i%5; j++ {
+			ips = append(ips, fmt.Sprintf("10.0.1.%d", j))
+		}
+		eps := endpoints(ips...)
+		eps[0].Labels = map[string]string{"version": fmt.Sprint(i % 3)}
+		cm.UpdateCluster(types.ClusterInfo{Endpoints: eps})
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestUpdateHealthCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	addr, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	assert.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	assert.NoError(t, err)
+	eps := []types.Endpoint{
+		{Address: addr, Port: uint32(p)},
+		{Address: "127.0.0.1", Port: 1},
+	}
+	hc := &types.HealthCheckConfig{
+		Path:               "/v1/models",
+		Interval:           10 * time.Millisecond,
+		UnhealthyThreshold: 1,
+	}
+
+	cm := NewClusterManager("health_check", eps, nil)
+	cl, err := cm.GetCluster(context.Background(), updaterLB)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cl.GetHostsNumber())
+
+	// health check added
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: eps, HealthCheck: hc})
+	assert.Eventually(t, func() bool { return cl.GetHostsNumber() == 1 }, time.Second, 10*time.Millisecond)
+	checker := cm.checker
+
+	// unchanged health check keeps the checker
+	same := *hc
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: eps, HealthCheck: &same})
+	assert.Same(t, checker, cm.checker)
+	assert.Equal(t, 1, cl.GetHostsNumber())
+
+	// health check changed, all the hosts are unhealthy with the new path, which falls back to use all of them
+	changed := *hc
+	changed.Path = "/unknown"
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: eps, HealthCheck: &changed})
+	assert.NotSame(t, checker, cm.checker)
+	assert.Equal(t, 2, cl.GetHostsNumber())
+
+	changed.Path = "/v1/models"
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: eps, HealthCheck: &changed})
+	assert.Eventually(t, func() bool { return cl.GetHostsNumber() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, addr+":"+port, cl.ChooseHost(context.Background()).Address())
+
+	// health check removed
+	cm.UpdateCluster(types.ClusterInfo{Endpoints: eps})
+	assert.Nil(t, cm.checker)
+	assert.Equal(t, 2, cl.GetHostsNumber())
+}
@@ -0,0 +1,276 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package healthcheck actively probes the endpoints of a cluster, and reports the health transitions.
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+const (
+	DefaultPath               = "/health"
+	DefaultInterval           = 5 * time.Second
+	DefaultTimeout            = time.Second
+	DefaultHealthyThreshold   = 1
+	DefaultUnhealthyThreshold = 3
+)
+
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConnsPerHost: 1,
+		IdleConnTimeout:     time.Minute,
+	},
+}
+
+func withDefaults(config types.HealthCheckConfig) types.HealthCheckConfig {
+	if config.Path == "" {
+		config.Path = DefaultPath
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.HealthyThreshold == 0 {
+		config.HealthyThreshold = DefaultHealthyThreshold
+	}
+	if config.UnhealthyThreshold == 0 {
+		config.UnhealthyThreshold = DefaultUnhealthyThreshold
+	}
+	return config
+}
+
+func endpointAddress(ep types.Endpoint) string {
+	return ep.Address + ":" + strconv.Itoa(int(ep.Port))
+}
+
+type hostChecker struct {
+	address string
+	stop    chan struct{}
+
+	// protected by Checker.lock
+	healthy   bool
+	successes uint32
+	failures  uint32
+}
+
+// Checker probes the endpoints of a cluster periodically.
+// The endpoints are considered healthy until they fail UnhealthyThreshold probes in a row,
+// so that a newly added endpoint could serve the requests immediately.
+type Checker struct {
+	cluster  string
+	config   types.HealthCheckConfig
+	onChange func()
+
+	lock    sync.Mutex
+	hosts   map[string]*hostChecker
+	stopped bool
+}
+
+// NewChecker creates a health checker for the cluster, onChange is called in a separate goroutine
+// when any endpoint's health changes.
+func NewChecker(cluster string, config types.HealthCheckConfig, onChange func()) *Checker {
+	return &Checker{
+		cluster:  cluster,
+		config:   withDefaults(config),
+		onChange: onChange,
+		hosts:    make(map[string]*hostChecker),
+	}
+}
+
+// UpdateEndpoints starts probing the new endpoints and stops probing the removed ones
+func (c *Checker) UpdateEndpoints(endpoints []types.Endpoint) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.stopped {
+		return
+	}
+
+	current := make(map[string]struct{}, len(endpoints))
+	for _, ep := range endpoints {
+		addr := endpointAddress(ep)
+		current[addr] = struct{}{}
+		if _, ok := c.hosts[addr]; ok {
+			continue
+		}
+		h := &hostChecker{
+			address: addr,
+			stop:    make(chan struct{}),
+			healthy: true,
+		}
+		c.hosts[addr] = h
+		prom.HealthCheckHostHealthy.WithLabelValues(c.cluster, addr).Set(1)
+		go c.run(h)
+	}
+
+	for addr, h := range c.hosts {
+		if _, ok := current[addr]; !ok {
+			close(h.stop)
+			delete(c.hosts, addr)
+			prom.HealthCheckHostHealthy.DeleteLabelValues(c.cluster, addr)
+		}
+	}
+}
+
+// Stop stops all the probes
+func (c *Checker) Stop() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.stopped = true
+	for addr, h := range c.hosts {
+		close(h.stop)
+		delete(c.hosts, addr)
+		prom.HealthCheckHostHealthy.DeleteLabelValues(c.cluster, addr)
+	}
+}
+
+// IsHealthy returns whether the endpoint is healthy, unknown endpoints are considered healthy
+func (c *Checker) IsHealthy(ep types.Endpoint) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if h, ok := c.hosts[endpointAddress(ep)]; ok {
+		return h.healthy
+	}
+	return true
+}
+
+// HealthyEndpoints filters out the unhealthy endpoints
+func (c *Checker) HealthyEndpoints(endpoints []types.Endpoint) []types.Endpoint {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	res := make([]types.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if h, ok := c.hosts[endpointAddress(ep)]; ok && !h.healthy {
+			continue
+		}
+		res = append(res, ep)
+	}
+	return res
+}
+
+func (c *Checker) nextInterval() time.Duration {
+	interval := c.config.Interval
+	if c.config.Jitter > 0 {
+		interval += time.Duration(rand.Int63n(int64(c.config.Jitter)))
+	}
+	return interval
+}
+
+func (c *Checker) run(h *hostChecker) {
+	defer func() {
+		if r := recover(); r != nil {
+			api.LogErrorf("health checker for host %s in cluster %s panic: %v", h.address, c.cluster, r)
+		}
+	}()
+
+	timer := time.NewTimer(c.nextInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-timer.C:
+			c.handleResult(h, c.probe(h.address))
+			timer.Reset(c.nextInterval())
+		}
+	}
+}
+
+func (c *Checker) probe(address string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+c.config.Path, nil)
+	if err != nil {
+		api.LogErrorf("failed to create health check request for host %s in cluster %s: %v", address, c.cluster, err)
+		return false
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		api.LogDebugf("health check for host %s in cluster %s failed: %v", address, c.cluster, err)
+		return false
+	}
+	defer resp.Body.Close()
+	// drain the body to reuse the connection
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		api.LogDebugf("health check for host %s in cluster %s failed, status: %d", address, c.cluster, resp.StatusCode)
+		return false
+	}
+	return true
+}
+
+func (c *Checker) handleResult(h *hostChecker, success bool) {
+	c.lock.Lock()
+	if _, ok := c.hosts[h.address]; !ok {
+		// removed during probing
+		c.lock.Unlock()
+		return
+	}
+
+	changed := false
+	if success {
+		h.failures = 0
+		h.successes++
+		if !h.healthy && h.successes >= c.config.HealthyThreshold {
+			h.healthy = true
+			changed = true
+		}
+	} else {
+		h.successes = 0
+		h.failures++
+		if h.healthy && h.failures >= c.config.UnhealthyThreshold {
+			h.healthy = false
+			changed = true
+		}
+	}
+	healthy := h.healthy
+	c.lock.Unlock()
+
+	if !changed {
+		return
+	}
+
+	state := "unhealthy"
+	value := 0.0
+	if healthy {
+		state = "healthy"
+		value = 1
+	}
+	api.LogWarnf("host %s in cluster %s becomes %s", h.address, c.cluster, state)
+	prom.HealthCheckHostHealthy.WithLabelValues(c.cluster, h.address).Set(value)
+	prom.HealthCheckTransitionsTotal.WithLabelValues(c.cluster, state).Inc()
+
+	if c.onChange != nil {
+		c.onChange()
+	}
+}
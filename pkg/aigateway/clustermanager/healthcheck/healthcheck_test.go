@@ -0,0 +1,106 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+)
+
+func serverEndpoint(t *testing.T, srv *httptest.Server) types.Endpoint {
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	assert.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	assert.NoError(t, err)
+	return types.Endpoint{Address: host, Port: uint32(p)}
+}
+
+func TestWithDefaults(t *testing.T) {
+	config := withDefaults(types.HealthCheckConfig{Path: "/v1/models"})
+	assert.Equal(t, "/v1/models", config.Path)
+	assert.Equal(t, DefaultInterval, config.Interval)
+	assert.Equal(t, DefaultTimeout, config.Timeout)
+	assert.Equal(t, uint32(DefaultHealthyThreshold), config.HealthyThreshold)
+	assert.Equal(t, uint32(DefaultUnhealthyThreshold), config.UnhealthyThreshold)
+}
+
+func TestChecker(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	var probes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		probes.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	var changes atomic.Int32
+	c := NewChecker("test", types.HealthCheckConfig{
+		Interval:           10 * time.Millisecond,
+		Jitter:             5 * time.Millisecond,
+		HealthyThreshold:   2,
+		UnhealthyThreshold: 2,
+	}, func() {
+		changes.Add(1)
+	})
+	defer c.Stop()
+
+	ep := serverEndpoint(t, srv)
+	down := types.Endpoint{Address: "127.0.0.1", Port: 1}
+	c.UpdateEndpoints([]types.Endpoint{ep, down})
+
+	// healthy before the first probe
+	assert.True(t, c.IsHealthy(down))
+
+	assert.Eventually(t, func() bool {
+		return !c.IsHealthy(down)
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []types.Endpoint{ep}, c.HealthyEndpoints([]types.Endpoint{ep, down}))
+
+	healthy.Store(false)
+	assert.Eventually(t, func() bool {
+		return !c.IsHealthy(ep)
+	}, time.Second, 5*time.Millisecond)
+
+	healthy.Store(true)
+	assert.Eventually(t, func() bool {
+		return c.IsHealthy(ep)
+	}, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return changes.Load() == 3
+	}, time.Second, 5*time.Millisecond)
+
+	// stop probing the removed endpoint
+	c.UpdateEndpoints([]types.Endpoint{down})
+	assert.True(t, c.IsHealthy(ep))
+	time.Sleep(20 * time.Millisecond)
+	n := probes.Load()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, n, probes.Load())
+}
@@ -15,14 +15,64 @@
 package clustermanager
 
 import (
+	"os"
+
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/file"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/kubernetes"
 	"github.com/aigw-project/aigw/pkg/aigateway/discovery/staticdemo"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/xds"
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer"
 )
 
+const (
+	AIGW_CLUSTER_PROVIDER = "AIGW_CLUSTER_PROVIDER"
+
+	// ProviderStatic loads the clusters in the static file once
+	ProviderStatic = "static"
+	// ProviderFile reloads the clusters when the file in AIGW_CLUSTER_FILE changes
+	ProviderFile = "file"
+	// ProviderKubernetes maps the cluster names to the Kubernetes services, see package kubernetes
+	ProviderKubernetes = "kubernetes"
+	// ProviderXDS subscribes the EDS of the xDS server in AIGW_XDS_SERVER, see package xds
+	ProviderXDS = "xds"
+)
+
+func newClusterInfoProvider() managertypes.ClusterInfoProvider {
+	switch name := os.Getenv(AIGW_CLUSTER_PROVIDER); name {
+	case ProviderFile:
+		p, err := file.NewProviderFromEnv()
+		if err == nil {
+			return p
+		}
+		api.LogErrorf("failed to create file cluster provider, fall back to static: %v", err)
+	case ProviderKubernetes:
+		p, err := kubernetes.NewProviderFromEnv()
+		if err == nil {
+			return p
+		}
+		api.LogErrorf("failed to create kubernetes cluster provider, fall back to static: %v", err)
+	case ProviderXDS:
+		p, err := xds.NewProviderFromEnv()
+		if err == nil {
+			return p
+		}
+		api.LogErrorf("failed to create xds cluster provider, fall back to static: %v", err)
+	case "", ProviderStatic:
+	default:
+		api.LogErrorf("unknown cluster provider %s, fall back to static", name)
+	}
+	return staticdemo.NewStaticClusterProvider()
+}
+
 func init() {
-	clusterProvider := staticdemo.NewStaticClusterProvider()
+	clusterProvider := newClusterInfoProvider()
+	if lister, ok := clusterProvider.(managertypes.ClusterLister); ok {
+		// keep the upstream clusters of Envoy in sync with the cluster manager
+		staticdemo.StartCDSServer(lister)
+	}
 	lb := NewClusterManager(clusterProvider)
 
 	api.LogInfof("registering cluster manager as global load balancer")
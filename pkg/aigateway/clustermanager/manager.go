@@ -64,10 +64,11 @@ func (m *Manager) ChooseHost(ctx context.Context, clusterName string, lbType loa
 	cl := newCluster(*clusterInfo)
 	api.LogDebugf("aiProxy new cluster, cluster info(%+v), cluster(%+v)", clusterInfo, cl)
 
+	// store before watching, so the updates in between are not lost
+	m.clusters.Store(clusterName, cl)
+
 	// watch cluster info
 	m.clusterInfoProvider.WatchCluster(clusterName, m.updateServers)
-
-	m.clusters.Store(clusterName, cl)
 	return cl.NextServer(ctx, lbType)
 }
 
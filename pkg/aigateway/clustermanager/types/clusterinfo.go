@@ -18,11 +18,15 @@ type Endpoint struct {
 	Address string
 	Port    uint32
 	Labels  map[string]string
+	// Weight is the load balancing weight, 0 means the default weight 1
+	Weight uint32
 }
 
 type ClusterInfo struct {
 	Name      string
 	Endpoints []Endpoint
+	// HealthCheck is optional, no active health check when it's nil
+	HealthCheck *HealthCheckConfig
 }
 
 type ClusterInfoNotifier func(*ClusterInfo)
@@ -18,3 +18,11 @@ type ClusterInfoProvider interface {
 	GetClusterInfo(name string) (*ClusterInfo, error)
 	WatchCluster(name string, notifier ClusterInfoNotifier)
 }
+
+// ClusterLister is implemented by the providers which know all the clusters, so the clusters can be served to
+// Envoy via CDS
+type ClusterLister interface {
+	GetAllClusters() []*ClusterInfo
+	// WatchAllClusters registers a notifier which is called after any cluster is added, changed or removed
+	WatchAllClusters(notifier func())
+}
@@ -0,0 +1,32 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "time"
+
+// HealthCheckConfig configures the active health check of the cluster endpoints,
+// the zero values are replaced by the defaults.
+type HealthCheckConfig struct {
+	// HTTP path to probe, e.g. "/health" or "/v1/models", the endpoint is healthy when 2xx is returned
+	Path     string
+	Interval time.Duration
+	Timeout  time.Duration
+	// random duration in [0, Jitter) added to each interval, to avoid probing all the hosts at the same time
+	Jitter time.Duration
+	// number of consecutive successful probes before an unhealthy host is marked healthy
+	HealthyThreshold uint32
+	// number of consecutive failed probes before a healthy host is marked unhealthy
+	UnhealthyThreshold uint32
+}
@@ -0,0 +1,324 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultClusterTemplate is the name of the template applied to the clusters without their own template
+	DefaultClusterTemplate = "*"
+
+	defaultConnectTimeout = 2 * time.Second
+	defaultConcurrency    = uint32(1024 * 1024)
+	// subsetKeyAddress is the subset key which selects a single host, used by the load balancer
+	subsetKeyAddress = "address"
+)
+
+// ClusterTemplate customizes the Envoy cluster generated by GenerateClusterWithTemplate.
+// The zero value generates the same cluster as before the templates are introduced.
+type ClusterTemplate struct {
+	// ConnectTimeout defaults to 2s
+	ConnectTimeout time.Duration
+	// HTTP2 uses HTTP/2 to the upstream, e.g. for the gRPC backends
+	HTTP2 bool
+	// HTTP2Keepalive sends HTTP/2 PINGs on the idle connections, HTTP2 is required
+	HTTP2Keepalive *HTTP2Keepalive
+	// TLS is the upstream TLS, plaintext when it's nil
+	TLS *UpstreamTLS
+
+	// circuit breaker thresholds, 0 means the default: 1M connections and requests, and Envoy's defaults of the others
+	MaxConnections     uint32
+	MaxPendingRequests uint32
+	MaxRequests        uint32
+	MaxRetries         uint32
+	// IdleTimeout closes the upstream connections without active requests, 0 means Envoy's default
+	IdleTimeout time.Duration
+	// PerTryTimeout limits the duration of each upstream stream, so every attempt of a request is bounded
+	PerTryTimeout time.Duration
+	// RetryBudget limits the concurrent retries of the cluster, the MaxRetries is used when it's nil
+	RetryBudget *RetryBudget
+
+	// OutlierDetection ejects the failing hosts, disabled when it's nil
+	OutlierDetection *OutlierDetection
+	// SubsetKeys are the extra subset selectors, each is a set of endpoint label keys, e.g. [["lora"], ["version", "zone"]]
+	SubsetKeys [][]string
+	// SlowStart ramps up the traffic to the newly added hosts in the gateway load balancer, disabled when it's nil
+	SlowStart *SlowStart
+}
+
+type HTTP2Keepalive struct {
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+type UpstreamTLS struct {
+	// SNI sent to the upstream, usually the domain of the backend
+	SNI string
+	// CAFile verifies the upstream certificate, the certificate is not verified when it's empty
+	CAFile string
+	// ALPN protocols, default to "h2" when HTTP2 is enabled
+	ALPN []string
+}
+
+// RetryBudget limits the concurrent retries to a percentage of the active requests,
+// so that the retries won't amplify the load when the whole cluster is in trouble
+type RetryBudget struct {
+	// Percent of the active requests which are allowed to be retries, 0 means Envoy's default 20
+	Percent uint32
+	// MinConcurrency is the concurrent retries which are always allowed, 0 means Envoy's default 3
+	MinConcurrency uint32
+}
+
+type OutlierDetection struct {
+	// Consecutive5xx ejects the host after the number of consecutive 5xx responses, 0 means Envoy's default 5
+	Consecutive5xx uint32
+	// ConsecutiveGatewayFailure ejects the host after the number of consecutive 502, 503 and 504, 0 means disabled
+	ConsecutiveGatewayFailure uint32
+	// Interval between the ejection sweeps, 0 means Envoy's default 10s
+	Interval time.Duration
+	// BaseEjectionTime is multiplied by the number of times the host is ejected, 0 means Envoy's default 30s
+	BaseEjectionTime time.Duration
+	// MaxEjectionPercent of the hosts can be ejected, 0 means Envoy's default 10
+	MaxEjectionPercent uint32
+}
+
+// SlowStart follows the slow start of Envoy: the weight of a new host grows from MinWeightPercent to 1 in Window,
+// as (age / Window) ^ (1 / Aggression)
+type SlowStart struct {
+	Window time.Duration
+	// Aggression > 1 ramps up faster at the beginning, < 1 slower, 0 means 1 which ramps up linearly
+	Aggression float64
+	// MinWeightPercent is the weight of a host just added, in percent
+	MinWeightPercent uint32
+}
+
+func (s *SlowStart) Validate() error {
+	if s.Window <= 0 {
+		return errors.New("slow start window should be positive")
+	}
+	if s.Aggression < 0 || math.IsNaN(s.Aggression) || math.IsInf(s.Aggression, 0) {
+		return fmt.Errorf("invalid slow start aggression %v", s.Aggression)
+	}
+	if s.MinWeightPercent > 100 {
+		return fmt.Errorf("slow start min weight percent %d is greater than 100", s.MinWeightPercent)
+	}
+	return nil
+}
+
+// Weight returns the weight in [0, 1] of the host which is added age ago
+func (s *SlowStart) Weight(age time.Duration) float64 {
+	if s == nil || age >= s.Window {
+		return 1
+	}
+	minWeight := float64(s.MinWeightPercent) / 100
+	if age <= 0 {
+		return minWeight
+	}
+	aggression := s.Aggression
+	if aggression == 0 {
+		aggression = 1
+	}
+	return max(minWeight, math.Pow(float64(age)/float64(s.Window), 1/aggression))
+}
+
+func (t *ClusterTemplate) Validate() error {
+	if t.ConnectTimeout < 0 || t.IdleTimeout < 0 || t.PerTryTimeout < 0 {
+		return errors.New("timeouts should not be negative")
+	}
+	if k := t.HTTP2Keepalive; k != nil {
+		if !t.HTTP2 {
+			return errors.New("http2 keepalive requires http2")
+		}
+		if k.Interval <= 0 || k.Timeout <= 0 {
+			return errors.New("http2 keepalive interval and timeout should be positive")
+		}
+	}
+	if tls := t.TLS; tls != nil {
+		if len(tls.SNI) > 255 {
+			return fmt.Errorf("sni %s is too long", tls.SNI)
+		}
+		for _, p := range tls.ALPN {
+			if p == "" {
+				return errors.New("empty alpn protocol")
+			}
+		}
+	}
+	if o := t.OutlierDetection; o != nil {
+		if o.Interval < 0 || o.BaseEjectionTime < 0 {
+			return errors.New("outlier detection durations should not be negative")
+		}
+		if o.MaxEjectionPercent > 100 {
+			return fmt.Errorf("max ejection percent %d is greater than 100", o.MaxEjectionPercent)
+		}
+	}
+	if b := t.RetryBudget; b != nil && b.Percent > 100 {
+		return fmt.Errorf("retry budget percent %d is greater than 100", b.Percent)
+	}
+	if t.SlowStart != nil {
+		if err := t.SlowStart.Validate(); err != nil {
+			return err
+		}
+	}
+
+	seen := map[string]struct{}{subsetKeyAddress: {}}
+	for _, keys := range t.SubsetKeys {
+		if len(keys) == 0 {
+			return errors.New("empty subset selector")
+		}
+		dedup := map[string]struct{}{}
+		for _, k := range keys {
+			if k == "" {
+				return errors.New("empty subset key")
+			}
+			if _, ok := dedup[k]; ok {
+				return fmt.Errorf("duplicate subset key %s", k)
+			}
+			dedup[k] = struct{}{}
+		}
+		sorted := append([]string(nil), keys...)
+		sort.Strings(sorted)
+		id := strings.Join(sorted, "\x00")
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("duplicate subset selector %v", keys)
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+// ClusterTemplates are the templates by cluster name, DefaultClusterTemplate is applied to the other clusters
+type ClusterTemplates struct {
+	lock      sync.RWMutex
+	templates map[string]*ClusterTemplate
+	watchers  []func()
+}
+
+var clusterTemplates = &ClusterTemplates{}
+
+// SetClusterTemplates validates and replaces all the templates, the watchers are notified when they are changed
+func SetClusterTemplates(templates map[string]*ClusterTemplate) error {
+	return clusterTemplates.Set(templates)
+}
+
+// UpdateClusterTemplates validates and merges the templates by cluster name, a nil template removes the one
+// of the cluster. The templates of the other clusters are kept, so that the configs owning different clusters
+// don't override each other. The watchers are notified when they are changed
+func UpdateClusterTemplates(templates map[string]*ClusterTemplate) error {
+	return clusterTemplates.Update(templates)
+}
+
+// GetClusterTemplate returns the template of the cluster, nil if there is neither its own nor the default one
+func GetClusterTemplate(cluster string) *ClusterTemplate {
+	return clusterTemplates.Get(cluster)
+}
+
+// WatchClusterTemplates registers a notifier called after the templates are changed
+func WatchClusterTemplates(notifier func()) {
+	clusterTemplates.Watch(notifier)
+}
+
+func (c *ClusterTemplates) Set(templates map[string]*ClusterTemplate) error {
+	for name, t := range templates {
+		if t == nil {
+			return fmt.Errorf("cluster template %s is nil", name)
+		}
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("invalid cluster template %s: %w", name, err)
+		}
+	}
+
+	c.lock.Lock()
+	if reflect.DeepEqual(c.templates, templates) || (len(c.templates) == 0 && len(templates) == 0) {
+		c.lock.Unlock()
+		return nil
+	}
+	c.templates = templates
+	watchers := c.watchers
+	c.lock.Unlock()
+
+	for _, w := range watchers {
+		w()
+	}
+	return nil
+}
+
+func (c *ClusterTemplates) Update(templates map[string]*ClusterTemplate) error {
+	for name, t := range templates {
+		if t == nil {
+			continue
+		}
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("invalid cluster template %s: %w", name, err)
+		}
+	}
+
+	c.lock.Lock()
+	// copy on write, since the map passed to Set is kept
+	merged := make(map[string]*ClusterTemplate, len(c.templates)+len(templates))
+	for name, t := range c.templates {
+		merged[name] = t
+	}
+	changed := false
+	for name, t := range templates {
+		old, ok := merged[name]
+		if t == nil {
+			if ok {
+				delete(merged, name)
+				changed = true
+			}
+			continue
+		}
+		if !ok || !reflect.DeepEqual(old, t) {
+			merged[name] = t
+			changed = true
+		}
+	}
+	if !changed {
+		c.lock.Unlock()
+		return nil
+	}
+	c.templates = merged
+	watchers := c.watchers
+	c.lock.Unlock()
+
+	for _, w := range watchers {
+		w()
+	}
+	return nil
+}
+
+func (c *ClusterTemplates) Get(cluster string) *ClusterTemplate {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if t, ok := c.templates[cluster]; ok {
+		return t
+	}
+	return c.templates[DefaultClusterTemplate]
+}
+
+func (c *ClusterTemplates) Watch(notifier func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.watchers = append(c.watchers, notifier)
+}
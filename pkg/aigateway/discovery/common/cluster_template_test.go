@@ -0,0 +1,200 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	clustercfg "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	tlscfg "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	upstreamhttp "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+)
+
+func TestClusterTemplateValidate(t *testing.T) {
+	valid := &ClusterTemplate{
+		HTTP2:          true,
+		HTTP2Keepalive: &HTTP2Keepalive{Interval: time.Second, Timeout: time.Second},
+		SubsetKeys:     [][]string{{"lora"}, {"version", "zone"}},
+	}
+	assert.NoError(t, valid.Validate())
+
+	for name, tmpl := range map[string]*ClusterTemplate{
+		"keepalive without http2": {HTTP2Keepalive: &HTTP2Keepalive{Interval: time.Second, Timeout: time.Second}},
+		"keepalive without timeout": {
+			HTTP2:          true,
+			HTTP2Keepalive: &HTTP2Keepalive{Interval: time.Second},
+		},
+		"negative timeout":    {PerTryTimeout: -time.Second},
+		"retry budget":        {RetryBudget: &RetryBudget{Percent: 101}},
+		"ejection percent":    {OutlierDetection: &OutlierDetection{MaxEjectionPercent: 101}},
+		"empty selector":      {SubsetKeys: [][]string{{}}},
+		"duplicate key":       {SubsetKeys: [][]string{{"lora", "lora"}}},
+		"duplicate selector":  {SubsetKeys: [][]string{{"version", "zone"}, {"zone", "version"}}},
+		"duplicate address":   {SubsetKeys: [][]string{{"address"}}},
+		"empty alpn protocol": {TLS: &UpstreamTLS{ALPN: []string{""}}},
+		"slow start window":   {SlowStart: &SlowStart{}},
+		"slow start weight":   {SlowStart: &SlowStart{Window: time.Minute, MinWeightPercent: 101}},
+		"slow start aggression": {
+			SlowStart: &SlowStart{Window: time.Minute, Aggression: -1},
+		},
+	} {
+		assert.Error(t, tmpl.Validate(), name)
+	}
+}
+
+func TestGenerateClusterWithTemplate(t *testing.T) {
+	endpoints := []types.Endpoint{{Address: "10.0.0.1", Port: 8000, Labels: map[string]string{"lora": "a"}}}
+
+	// the default template is the same as before
+	c := GenerateClusterWithTemplate("qwen", endpoints, nil)
+	assert.Equal(t, GenerateCluster("qwen", endpoints, false), c)
+	assert.Equal(t, 2*time.Second, c.ConnectTimeout.AsDuration())
+	assert.Nil(t, c.Http2ProtocolOptions)
+	assert.Nil(t, c.TransportSocket)
+	assert.Nil(t, c.OutlierDetection)
+	md := c.LoadAssignment.Endpoints[0].LbEndpoints[0].Metadata.FilterMetadata["envoy.lb"]
+	assert.Equal(t, "10.0.0.1", md.Fields["address"].GetStringValue())
+	assert.Equal(t, "a", md.Fields["lora"].GetStringValue())
+	assert.NotNil(t, GenerateCluster("qwen", endpoints, true).Http2ProtocolOptions)
+
+	c = GenerateClusterWithTemplate("qwen", endpoints, &ClusterTemplate{
+		ConnectTimeout:     time.Second,
+		HTTP2:              true,
+		HTTP2Keepalive:     &HTTP2Keepalive{Interval: 10 * time.Second, Timeout: 3 * time.Second},
+		TLS:                &UpstreamTLS{SNI: "qwen.example.com", CAFile: "/etc/ssl/ca.pem"},
+		MaxConnections:     100,
+		MaxPendingRequests: 10,
+		PerTryTimeout:      time.Minute,
+		RetryBudget:        &RetryBudget{Percent: 30},
+		OutlierDetection:   &OutlierDetection{ConsecutiveGatewayFailure: 3, MaxEjectionPercent: 50},
+		SubsetKeys:         [][]string{{"lora"}},
+	})
+	assert.NoError(t, c.Validate())
+	assert.Equal(t, time.Second, c.ConnectTimeout.AsDuration())
+	assert.Nil(t, c.Http2ProtocolOptions)
+
+	opts := &upstreamhttp.HttpProtocolOptions{}
+	assert.NoError(t, c.TypedExtensionProtocolOptions["envoy.extensions.upstreams.http.v3.HttpProtocolOptions"].UnmarshalTo(opts))
+	assert.Equal(t, time.Minute, opts.CommonHttpProtocolOptions.MaxStreamDuration.AsDuration())
+	h2 := opts.GetExplicitHttpConfig().GetHttp2ProtocolOptions()
+	assert.Equal(t, 10*time.Second, h2.ConnectionKeepalive.Interval.AsDuration())
+
+	tlsCtx := &tlscfg.UpstreamTlsContext{}
+	assert.NoError(t, c.TransportSocket.GetTypedConfig().UnmarshalTo(tlsCtx))
+	assert.Equal(t, "qwen.example.com", tlsCtx.Sni)
+	assert.Equal(t, []string{"h2"}, tlsCtx.CommonTlsContext.AlpnProtocols)
+	assert.Equal(t, "/etc/ssl/ca.pem", tlsCtx.CommonTlsContext.GetValidationContext().TrustedCa.GetFilename())
+
+	thresholds := c.CircuitBreakers.Thresholds[0]
+	assert.Equal(t, uint32(100), thresholds.MaxConnections.Value)
+	assert.Equal(t, uint32(10), thresholds.MaxPendingRequests.Value)
+	assert.Equal(t, defaultConcurrency, thresholds.MaxRequests.Value)
+	assert.Nil(t, thresholds.MaxRetries)
+	assert.Equal(t, 30.0, thresholds.RetryBudget.BudgetPercent.Value)
+	// Envoy's default
+	assert.Nil(t, thresholds.RetryBudget.MinRetryConcurrency)
+
+	assert.Equal(t, uint32(3), c.OutlierDetection.ConsecutiveGatewayFailure.Value)
+	assert.Equal(t, uint32(100), c.OutlierDetection.EnforcingConsecutiveGatewayFailure.Value)
+	assert.Equal(t, []*clustercfg.Cluster_LbSubsetConfig_LbSubsetSelector{
+		{Keys: []string{"address"}},
+		{Keys: []string{"lora"}},
+	}, c.LbSubsetConfig.SubsetSelectors)
+}
+
+func TestSlowStartWeight(t *testing.T) {
+	var disabled *SlowStart
+	assert.Equal(t, 1.0, disabled.Weight(0))
+
+	linear := &SlowStart{Window: 100 * time.Second}
+	assert.Equal(t, 0.0, linear.Weight(0))
+	assert.InDelta(t, 0.25, linear.Weight(25*time.Second), 1e-9)
+	assert.Equal(t, 1.0, linear.Weight(100*time.Second))
+	assert.Equal(t, 1.0, linear.Weight(time.Hour))
+
+	aggressive := &SlowStart{Window: 100 * time.Second, Aggression: 2, MinWeightPercent: 10}
+	assert.Equal(t, 0.1, aggressive.Weight(0))
+	assert.InDelta(t, 0.5, aggressive.Weight(25*time.Second), 1e-9)
+	// the min weight
+	assert.Equal(t, 0.1, aggressive.Weight(time.Second/2))
+}
+
+func TestClusterTemplates(t *testing.T) {
+	templates := &ClusterTemplates{}
+	notified := 0
+	templates.Watch(func() { notified++ })
+
+	assert.Nil(t, templates.Get("qwen"))
+	assert.NoError(t, templates.Set(nil))
+	assert.Equal(t, 0, notified)
+
+	def := &ClusterTemplate{HTTP2: true}
+	qwen := &ClusterTemplate{ConnectTimeout: time.Second}
+	assert.NoError(t, templates.Set(map[string]*ClusterTemplate{DefaultClusterTemplate: def, "qwen": qwen}))
+	assert.Equal(t, 1, notified)
+	assert.Equal(t, qwen, templates.Get("qwen"))
+	assert.Equal(t, def, templates.Get("deepseek"))
+
+	// unchanged
+	assert.NoError(t, templates.Set(map[string]*ClusterTemplate{
+		DefaultClusterTemplate: {HTTP2: true},
+		"qwen":                 {ConnectTimeout: time.Second},
+	}))
+	assert.Equal(t, 1, notified)
+
+	// invalid templates are not applied
+	assert.Error(t, templates.Set(map[string]*ClusterTemplate{"qwen": {PerTryTimeout: -1}}))
+	assert.Equal(t, qwen, templates.Get("qwen"))
+	assert.Equal(t, 1, notified)
+}
+
+func TestUpdateClusterTemplates(t *testing.T) {
+	templates := &ClusterTemplates{}
+	notified := 0
+	templates.Watch(func() { notified++ })
+
+	def := &ClusterTemplate{HTTP2: true}
+	assert.NoError(t, templates.Set(map[string]*ClusterTemplate{DefaultClusterTemplate: def}))
+	assert.Equal(t, 1, notified)
+
+	// merged with the templates of the other clusters
+	qwen := &ClusterTemplate{ConnectTimeout: time.Second}
+	assert.NoError(t, templates.Update(map[string]*ClusterTemplate{"qwen": qwen}))
+	deepseek := &ClusterTemplate{IdleTimeout: time.Second}
+	assert.NoError(t, templates.Update(map[string]*ClusterTemplate{"deepseek": deepseek}))
+	assert.Equal(t, 3, notified)
+	assert.Equal(t, qwen, templates.Get("qwen"))
+	assert.Equal(t, deepseek, templates.Get("deepseek"))
+	assert.Equal(t, def, templates.Get("glm"))
+
+	// unchanged
+	assert.NoError(t, templates.Update(map[string]*ClusterTemplate{"qwen": {ConnectTimeout: time.Second}, "glm": nil}))
+	assert.Equal(t, 3, notified)
+
+	// invalid templates are not applied
+	assert.Error(t, templates.Update(map[string]*ClusterTemplate{"qwen": nil, "deepseek": {PerTryTimeout: -1}}))
+	assert.Equal(t, qwen, templates.Get("qwen"))
+	assert.Equal(t, 3, notified)
+
+	// nil removes the template of the cluster only
+	assert.NoError(t, templates.Update(map[string]*ClusterTemplate{"qwen": nil}))
+	assert.Equal(t, 4, notified)
+	assert.Equal(t, def, templates.Get("qwen"))
+	assert.Equal(t, deepseek, templates.Get("deepseek"))
+}
@@ -20,7 +20,10 @@ import (
 	clustercfg "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	corecfg "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	endpointcfg "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	tlscfg "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	upstreamhttp "github.com/envoyproxy/go-control-plane/envoy/extensions/upstreams/http/v3"
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -32,9 +35,24 @@ import (
 
 // GenerateCluster create cds cluster from endpoints
 func GenerateCluster(name string, endpoints []types.Endpoint, grpc bool) *clustercfg.Cluster {
+	return GenerateClusterWithTemplate(name, endpoints, &ClusterTemplate{HTTP2: grpc})
+}
+
+// GenerateClusterWithTemplate creates the cds cluster from the endpoints, customized by the template.
+// The endpoint labels are put into the "envoy.lb" metadata, so they can be used by the subset selectors.
+func GenerateClusterWithTemplate(name string, endpoints []types.Endpoint, tmpl *ClusterTemplate) *clustercfg.Cluster {
+	if tmpl == nil {
+		tmpl = &ClusterTemplate{}
+	}
+
 	lbEndpoints := make([]*endpointcfg.LbEndpoint, 0, len(endpoints))
 	for _, e := range endpoints {
+		var weight *wrapperspb.UInt32Value
+		if e.Weight > 0 {
+			weight = wrapperspb.UInt32(e.Weight)
+		}
 		lbEndpoints = append(lbEndpoints, &endpointcfg.LbEndpoint{
+			LoadBalancingWeight: weight,
 			HostIdentifier: &endpointcfg.LbEndpoint_Endpoint{
 				Endpoint: &endpointcfg.Endpoint{
 					Address: &corecfg.Address{
@@ -52,33 +70,39 @@ func GenerateCluster(name string, endpoints []types.Endpoint, grpc bool) *cluste
 			},
 			Metadata: &corecfg.Metadata{
 				FilterMetadata: map[string]*structpb.Struct{
-					"envoy.lb": {
-						Fields: map[string]*structpb.Value{
-							"address": structpb.NewStringValue(e.Address),
-						},
-					},
+					"envoy.lb": endpointMetadata(e),
 				},
 			},
 		})
 	}
 
-	defaultConcurrency := uint32(1024 * 1024)
-
-	http2Config := &corecfg.Http2ProtocolOptions{}
-	if !grpc {
-		http2Config = nil
+	connectTimeout := tmpl.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = defaultConnectTimeout
+	}
+	subsetSelectors := []*clustercfg.Cluster_LbSubsetConfig_LbSubsetSelector{
+		{
+			Keys: []string{subsetKeyAddress},
+		},
+	}
+	for _, keys := range tmpl.SubsetKeys {
+		subsetSelectors = append(subsetSelectors, &clustercfg.Cluster_LbSubsetConfig_LbSubsetSelector{
+			Keys: keys,
+		})
 	}
 
-	return &clustercfg.Cluster{
-		Name:                 name,
-		ConnectTimeout:       durationpb.New(2 * time.Second),
-		Http2ProtocolOptions: http2Config,
+	c := &clustercfg.Cluster{
+		Name:           name,
+		ConnectTimeout: durationpb.New(connectTimeout),
 		CircuitBreakers: &clustercfg.CircuitBreakers{
 			Thresholds: []*clustercfg.CircuitBreakers_Thresholds{
 				{
-					Priority:       corecfg.RoutingPriority_DEFAULT,
-					MaxConnections: wrapperspb.UInt32(defaultConcurrency),
-					MaxRequests:    wrapperspb.UInt32(defaultConcurrency),
+					Priority:           corecfg.RoutingPriority_DEFAULT,
+					MaxConnections:     wrapperspb.UInt32(orDefault(tmpl.MaxConnections, defaultConcurrency)),
+					MaxRequests:        wrapperspb.UInt32(orDefault(tmpl.MaxRequests, defaultConcurrency)),
+					MaxPendingRequests: optionalUInt32(tmpl.MaxPendingRequests),
+					MaxRetries:         optionalUInt32(tmpl.MaxRetries),
+					RetryBudget:        retryBudget(tmpl.RetryBudget),
 				},
 			},
 		},
@@ -91,18 +115,159 @@ func GenerateCluster(name string, endpoints []types.Endpoint, grpc bool) *cluste
 			},
 		},
 		LbSubsetConfig: &clustercfg.Cluster_LbSubsetConfig{
-			FallbackPolicy: clustercfg.Cluster_LbSubsetConfig_ANY_ENDPOINT,
-			SubsetSelectors: []*clustercfg.Cluster_LbSubsetConfig_LbSubsetSelector{
-				{
-					Keys: []string{"address"},
+			FallbackPolicy:  clustercfg.Cluster_LbSubsetConfig_ANY_ENDPOINT,
+			SubsetSelectors: subsetSelectors,
+		},
+		OutlierDetection: outlierDetection(tmpl.OutlierDetection),
+	}
+	setProtocolOptions(c, tmpl)
+	if tmpl.TLS != nil {
+		c.TransportSocket = upstreamTLS(tmpl.TLS, tmpl.HTTP2)
+	}
+	return c
+}
+
+func endpointMetadata(e types.Endpoint) *structpb.Struct {
+	fields := make(map[string]*structpb.Value, len(e.Labels)+1)
+	for k, v := range e.Labels {
+		fields[k] = structpb.NewStringValue(v)
+	}
+	// the address is always the host's own address, which is used to choose the host
+	fields[subsetKeyAddress] = structpb.NewStringValue(e.Address)
+	return &structpb.Struct{Fields: fields}
+}
+
+func retryBudget(b *RetryBudget) *clustercfg.CircuitBreakers_Thresholds_RetryBudget {
+	if b == nil {
+		return nil
+	}
+	budget := &clustercfg.CircuitBreakers_Thresholds_RetryBudget{
+		MinRetryConcurrency: optionalUInt32(b.MinConcurrency),
+	}
+	if b.Percent != 0 {
+		budget.BudgetPercent = &typev3.Percent{Value: float64(b.Percent)}
+	}
+	return budget
+}
+
+func orDefault(v, def uint32) uint32 {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+func optionalUInt32(v uint32) *wrapperspb.UInt32Value {
+	if v == 0 {
+		return nil
+	}
+	return wrapperspb.UInt32(v)
+}
+
+func optionalDuration(d time.Duration) *durationpb.Duration {
+	if d == 0 {
+		return nil
+	}
+	return durationpb.New(d)
+}
+
+// setProtocolOptions keeps the legacy http2_protocol_options when only HTTP/2 is required, otherwise uses the
+// typed HttpProtocolOptions, since the two can't be used together
+func setProtocolOptions(c *clustercfg.Cluster, tmpl *ClusterTemplate) {
+	if tmpl.HTTP2Keepalive == nil && tmpl.IdleTimeout == 0 && tmpl.PerTryTimeout == 0 {
+		if tmpl.HTTP2 {
+			c.Http2ProtocolOptions = &corecfg.Http2ProtocolOptions{}
+		}
+		return
+	}
+
+	opts := &upstreamhttp.HttpProtocolOptions{
+		CommonHttpProtocolOptions: &corecfg.HttpProtocolOptions{
+			IdleTimeout:       optionalDuration(tmpl.IdleTimeout),
+			MaxStreamDuration: optionalDuration(tmpl.PerTryTimeout),
+		},
+	}
+	if tmpl.HTTP2 {
+		h2 := &corecfg.Http2ProtocolOptions{}
+		if k := tmpl.HTTP2Keepalive; k != nil {
+			h2.ConnectionKeepalive = &corecfg.KeepaliveSettings{
+				Interval: durationpb.New(k.Interval),
+				Timeout:  durationpb.New(k.Timeout),
+			}
+		}
+		opts.UpstreamProtocolOptions = &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig_Http2ProtocolOptions{
+					Http2ProtocolOptions: h2,
+				},
+			},
+		}
+	} else {
+		opts.UpstreamProtocolOptions = &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig_{
+			ExplicitHttpConfig: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig{
+				ProtocolConfig: &upstreamhttp.HttpProtocolOptions_ExplicitHttpConfig_HttpProtocolOptions{
+					HttpProtocolOptions: &corecfg.Http1ProtocolOptions{},
 				},
 			},
+		}
+	}
+	typed, err := anypb.New(opts)
+	if err != nil {
+		return
+	}
+	c.TypedExtensionProtocolOptions = map[string]*anypb.Any{
+		"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": typed,
+	}
+}
+
+func upstreamTLS(cfg *UpstreamTLS, http2 bool) *corecfg.TransportSocket {
+	alpn := cfg.ALPN
+	if len(alpn) == 0 && http2 {
+		alpn = []string{"h2"}
+	}
+	tlsCtx := &tlscfg.UpstreamTlsContext{
+		Sni: cfg.SNI,
+		CommonTlsContext: &tlscfg.CommonTlsContext{
+			AlpnProtocols: alpn,
 		},
 	}
+	if cfg.CAFile != "" {
+		tlsCtx.CommonTlsContext.ValidationContextType = &tlscfg.CommonTlsContext_ValidationContext{
+			ValidationContext: &tlscfg.CertificateValidationContext{
+				TrustedCa: &corecfg.DataSource{
+					Specifier: &corecfg.DataSource_Filename{Filename: cfg.CAFile},
+				},
+			},
+		}
+	}
+	typed, err := anypb.New(tlsCtx)
+	if err != nil {
+		return nil
+	}
+	return &corecfg.TransportSocket{
+		Name:       "envoy.transport_sockets.tls",
+		ConfigType: &corecfg.TransportSocket_TypedConfig{TypedConfig: typed},
+	}
+}
+
+func outlierDetection(o *OutlierDetection) *clustercfg.OutlierDetection {
+	if o == nil {
+		return nil
+	}
+	od := &clustercfg.OutlierDetection{
+		Consecutive_5Xx:    optionalUInt32(o.Consecutive5xx),
+		Interval:           optionalDuration(o.Interval),
+		BaseEjectionTime:   optionalDuration(o.BaseEjectionTime),
+		MaxEjectionPercent: optionalUInt32(o.MaxEjectionPercent),
+	}
+	if o.ConsecutiveGatewayFailure > 0 {
+		od.ConsecutiveGatewayFailure = wrapperspb.UInt32(o.ConsecutiveGatewayFailure)
+		// the gateway failure ejection is not enforced by default
+		od.EnforcingConsecutiveGatewayFailure = wrapperspb.UInt32(100)
+	}
+	return od
 }
 
-// GenerateDeltaDiscoveryResponse is a helper function that creates a DeltaDiscoveryResponse
-// from either a single discovery.Resource or a slice of them.
 func GenerateDeltaDiscoveryResponse(typeURL string, nonce string, input ...*discovery.Resource) *discovery.DeltaDiscoveryResponse {
 	resources := make([]*discovery.Resource, 0, len(input))
 	resources = append(resources, input...)
@@ -26,3 +26,10 @@ const (
 func IsHTTP1Backend(backend string) bool {
 	return backend != TritonBackend
 }
+
+const (
+	// the well-known labels of the endpoint locality, same as the Kubernetes topology labels
+	LabelTopologyRegion  = "topology.kubernetes.io/region"
+	LabelTopologyZone    = "topology.kubernetes.io/zone"
+	LabelTopologySubZone = "topology.istio.io/subzone"
+)
@@ -0,0 +1,96 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+)
+
+// ClusterWatchers keeps the notifiers registered by ClusterInfoProvider.WatchCluster
+type ClusterWatchers struct {
+	lock      sync.RWMutex
+	notifiers map[string][]types.ClusterInfoNotifier
+	// all is notified once after a batch of changes, see ClusterLister.WatchAllClusters
+	all []func()
+}
+
+func (w *ClusterWatchers) Add(name string, notifier types.ClusterInfoNotifier) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.notifiers == nil {
+		w.notifiers = make(map[string][]types.ClusterInfoNotifier)
+	}
+	w.notifiers[name] = append(w.notifiers[name], notifier)
+}
+
+// Notify calls the notifiers of the cluster, outside the lock, so the notifiers can call Add
+func (w *ClusterWatchers) Notify(info *types.ClusterInfo) {
+	w.lock.RLock()
+	notifiers := w.notifiers[info.Name]
+	w.lock.RUnlock()
+
+	for _, n := range notifiers {
+		n(info)
+	}
+}
+
+func (w *ClusterWatchers) AddAll(notifier func()) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.all = append(w.all, notifier)
+}
+
+// NotifyAll calls the notifiers registered by AddAll, outside the lock
+func (w *ClusterWatchers) NotifyAll() {
+	w.lock.RLock()
+	notifiers := w.all
+	w.lock.RUnlock()
+
+	for _, n := range notifiers {
+		n()
+	}
+}
+
+// SortEndpoints sorts the endpoints by address and port, so the same endpoints from different sources are equal
+func SortEndpoints(endpoints []types.Endpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Address != endpoints[j].Address {
+			return endpoints[i].Address < endpoints[j].Address
+		}
+		return endpoints[i].Port < endpoints[j].Port
+	})
+}
+
+// DiffClusters returns the clusters which are added or changed in cur, and the names of the clusters removed from prev.
+// The endpoints should be sorted by SortEndpoints.
+func DiffClusters(prev, cur map[string]*types.ClusterInfo) (changed []*types.ClusterInfo, removed []string) {
+	for name, info := range cur {
+		if old, ok := prev[name]; !ok || !reflect.DeepEqual(old, info) {
+			changed = append(changed, info)
+		}
+	}
+	for name := range prev {
+		if _, ok := cur[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Name < changed[j].Name })
+	sort.Strings(removed)
+	return changed, removed
+}
@@ -0,0 +1,167 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+type Endpoint struct {
+	Address string            `json:"address"`
+	Port    uint32            `json:"port"`
+	Weight  uint32            `json:"weight,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type HealthCheck struct {
+	Path string `json:"path"`
+	// durations in Go format, e.g. "5s"
+	Interval           string `json:"interval"`
+	Timeout            string `json:"timeout"`
+	Jitter             string `json:"jitter"`
+	HealthyThreshold   uint32 `json:"healthy_threshold"`
+	UnhealthyThreshold uint32 `json:"unhealthy_threshold"`
+}
+
+func (hc *HealthCheck) toConfig() (*managertypes.HealthCheckConfig, error) {
+	config := &managertypes.HealthCheckConfig{
+		Path:               hc.Path,
+		HealthyThreshold:   hc.HealthyThreshold,
+		UnhealthyThreshold: hc.UnhealthyThreshold,
+	}
+	for _, d := range []struct {
+		value  string
+		target *time.Duration
+	}{
+		{hc.Interval, &config.Interval},
+		{hc.Timeout, &config.Timeout},
+		{hc.Jitter, &config.Jitter},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, err
+		}
+		*d.target = v
+	}
+	return config, nil
+}
+
+type Cluster struct {
+	Name        string       `json:"name"`
+	Endpoints   []Endpoint   `json:"endpoints"`
+	HealthCheck *HealthCheck `json:"health_check,omitempty"`
+}
+
+// Config is the content of the cluster file, in JSON or YAML:
+//
+//	clusters:
+//	- name: qwen3
+//	  endpoints:
+//	  - address: 10.0.0.1
+//	    port: 8000
+//	    weight: 2
+//	    labels: {version: v2}
+type Config struct {
+	Clusters []Cluster `json:"clusters"`
+}
+
+// Parse parses the config in JSON or YAML, the unknown fields are rejected to catch the typos
+func Parse(data []byte) (*Config, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	config := &Config{}
+	if raw == nil {
+		// empty file
+		return config, nil
+	}
+	js, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(js))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ClusterInfos validates the config and converts it to the cluster infos, keyed by name
+func (c *Config) ClusterInfos() (map[string]*managertypes.ClusterInfo, error) {
+	infos := make(map[string]*managertypes.ClusterInfo, len(c.Clusters))
+	for _, cl := range c.Clusters {
+		if cl.Name == "" {
+			return nil, errors.New("cluster name is empty")
+		}
+		if _, ok := infos[cl.Name]; ok {
+			return nil, fmt.Errorf("duplicate cluster %s", cl.Name)
+		}
+
+		endpoints := make([]managertypes.Endpoint, 0, len(cl.Endpoints))
+		seen := make(map[string]struct{}, len(cl.Endpoints))
+		for _, ep := range cl.Endpoints {
+			if ep.Address == "" {
+				return nil, fmt.Errorf("cluster %s: endpoint address is empty", cl.Name)
+			}
+			if ep.Port == 0 || ep.Port > 65535 {
+				return nil, fmt.Errorf("cluster %s: invalid port %d of endpoint %s", cl.Name, ep.Port, ep.Address)
+			}
+			key := fmt.Sprintf("%s:%d", ep.Address, ep.Port)
+			if _, ok := seen[key]; ok {
+				return nil, fmt.Errorf("cluster %s: duplicate endpoint %s", cl.Name, key)
+			}
+			seen[key] = struct{}{}
+
+			endpoint := managertypes.Endpoint{
+				Address: ep.Address,
+				Port:    ep.Port,
+				Weight:  ep.Weight,
+			}
+			if len(ep.Labels) > 0 {
+				endpoint.Labels = ep.Labels
+			}
+			endpoints = append(endpoints, endpoint)
+		}
+		common.SortEndpoints(endpoints)
+
+		info := &managertypes.ClusterInfo{
+			Name:      cl.Name,
+			Endpoints: endpoints,
+		}
+		if cl.HealthCheck != nil {
+			hc, err := cl.HealthCheck.toConfig()
+			if err != nil {
+				return nil, fmt.Errorf("cluster %s: invalid health check: %w", cl.Name, err)
+			}
+			info.HealthCheck = hc
+		}
+		infos[cl.Name] = info
+	}
+	return infos, nil
+}
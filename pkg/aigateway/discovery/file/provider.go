@@ -0,0 +1,180 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package file provides the clusters from a JSON or YAML file, which is reloaded when it changes.
+package file
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+	pkgcommon "github.com/aigw-project/aigw/pkg/common"
+)
+
+const (
+	AIGW_CLUSTER_FILE                = "AIGW_CLUSTER_FILE"
+	AIGW_CLUSTER_FILE_WATCH_INTERVAL = "AIGW_CLUSTER_FILE_WATCH_INTERVAL"
+
+	DefaultPath          = "/etc/aigw/static_clusters.json"
+	defaultWatchInterval = 5 * time.Second
+)
+
+// Provider is a ClusterInfoProvider reading the clusters from a file. The file is polled,
+// and the watchers are notified with the clusters changed. A removed cluster is notified with no endpoints.
+type Provider struct {
+	path string
+	// reloadLock serializes Reload, so the changes are notified in order
+	reloadLock sync.Mutex
+
+	lock     sync.RWMutex
+	clusters map[string]*managertypes.ClusterInfo
+	// content of the file applied last time
+	content []byte
+
+	watchers common.ClusterWatchers
+}
+
+// NewProvider creates the provider and loads the file. A missing file is not an error,
+// since it may be mounted later, the provider has no clusters until then.
+func NewProvider(path string) (*Provider, error) {
+	p := &Provider{
+		path:     path,
+		clusters: map[string]*managertypes.ClusterInfo{},
+	}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewProviderFromEnv creates the provider with the file in AIGW_CLUSTER_FILE and watches it in background
+func NewProviderFromEnv() (*Provider, error) {
+	path := os.Getenv(AIGW_CLUSTER_FILE)
+	if path == "" {
+		path = DefaultPath
+	}
+	p, err := NewProvider(path)
+	if err != nil {
+		return nil, err
+	}
+	go p.Watch(context.Background(), pkgcommon.GetDurationFromEnv(AIGW_CLUSTER_FILE_WATCH_INTERVAL, defaultWatchInterval))
+	return p, nil
+}
+
+// Reload reads the file and notifies the changes. The clusters are kept when the file is invalid or removed,
+// to survive the partial writes and the volume remounts.
+func (p *Provider) Reload() error {
+	p.reloadLock.Lock()
+	defer p.reloadLock.Unlock()
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			api.LogWarnf("cluster file %s not found", p.path)
+			return nil
+		}
+		return err
+	}
+
+	p.lock.RLock()
+	unchanged := p.content != nil && bytes.Equal(data, p.content)
+	p.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	config, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", p.path, err)
+	}
+	clusters, err := config.ClusterInfos()
+	if err != nil {
+		return fmt.Errorf("invalid clusters in %s: %w", p.path, err)
+	}
+
+	p.lock.Lock()
+	prev := p.clusters
+	p.clusters = clusters
+	p.content = data
+	p.lock.Unlock()
+
+	changed, removed := common.DiffClusters(prev, clusters)
+	api.LogInfof("cluster file %s loaded, %d clusters, changed: %d, removed: %v", p.path, len(clusters), len(changed), removed)
+	for _, info := range changed {
+		p.watchers.Notify(info)
+	}
+	for _, name := range removed {
+		p.watchers.Notify(&managertypes.ClusterInfo{Name: name})
+	}
+	if len(changed) > 0 || len(removed) > 0 {
+		p.watchers.NotifyAll()
+	}
+	return nil
+}
+
+// Watch polls the file every interval until ctx is done
+func (p *Provider) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Reload(); err != nil {
+				api.LogErrorf("failed to reload cluster file: %v", err)
+			}
+		}
+	}
+}
+
+func (p *Provider) GetAllClusters() []*managertypes.ClusterInfo {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	clusters := make([]*managertypes.ClusterInfo, 0, len(p.clusters))
+	for _, cluster := range p.clusters {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	return clusters
+}
+
+func (p *Provider) GetClusterInfo(name string) (*managertypes.ClusterInfo, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if cluster, ok := p.clusters[name]; ok {
+		return cluster, nil
+	}
+	return nil, fmt.Errorf("cluster %s not found in %s", name, p.path)
+}
+
+func (p *Provider) WatchCluster(name string, notifier managertypes.ClusterInfoNotifier) {
+	p.watchers.Add(name, notifier)
+}
+
+func (p *Provider) WatchAllClusters(notifier func()) {
+	p.watchers.AddAll(notifier)
+}
@@ -0,0 +1,203 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+)
+
+func TestParse(t *testing.T) {
+	yamlConfig, err := Parse([]byte(`
+clusters:
+- name: qwen
+  endpoints:
+  - address: 10.0.0.2
+    port: 8000
+  - address: 10.0.0.1
+    port: 8000
+    weight: 2
+    labels: {version: v2}
+  health_check:
+    path: /health
+    interval: 3s
+`))
+	assert.NoError(t, err)
+	jsonConfig, err := Parse([]byte(`{"clusters": [{"name": "qwen", "endpoints": [
+		{"address": "10.0.0.2", "port": 8000},
+		{"address": "10.0.0.1", "port": 8000, "weight": 2, "labels": {"version": "v2"}}
+	], "health_check": {"path": "/health", "interval": "3s"}}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, yamlConfig, jsonConfig)
+
+	infos, err := yamlConfig.ClusterInfos()
+	assert.NoError(t, err)
+	assert.Equal(t, &managertypes.ClusterInfo{
+		Name: "qwen",
+		Endpoints: []managertypes.Endpoint{
+			{Address: "10.0.0.1", Port: 8000, Weight: 2, Labels: map[string]string{"version": "v2"}},
+			{Address: "10.0.0.2", Port: 8000},
+		},
+		HealthCheck: &managertypes.HealthCheckConfig{Path: "/health", Interval: 3 * time.Second},
+	}, infos["qwen"])
+
+	config, err := Parse(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, config.Clusters)
+
+	_, err = Parse([]byte(`clusters: [{name: a, endpoint: []}]`))
+	assert.ErrorContains(t, err, "unknown field")
+}
+
+func TestClusterInfosInvalid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config string
+		err    string
+	}{
+		"no name":       {`clusters: [{endpoints: []}]`, "name is empty"},
+		"duplicate":     {`clusters: [{name: a}, {name: a}]`, "duplicate cluster a"},
+		"no address":    {`clusters: [{name: a, endpoints: [{port: 80}]}]`, "address is empty"},
+		"invalid port":  {`clusters: [{name: a, endpoints: [{address: 1.1.1.1}]}]`, "invalid port 0"},
+		"duplicate ep":  {`clusters: [{name: a, endpoints: [{address: 1.1.1.1, port: 80}, {address: 1.1.1.1, port: 80}]}]`, "duplicate endpoint"},
+		"health check":  {`clusters: [{name: a, health_check: {interval: "3"}}]`, "invalid health check"},
+		"port overflow": {`clusters: [{name: a, endpoints: [{address: 1.1.1.1, port: 65536}]}]`, "invalid port 65536"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config, err := Parse([]byte(tc.config))
+			assert.NoError(t, err)
+			_, err = config.ClusterInfos()
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+type recorder struct {
+	lock    sync.Mutex
+	updates []*managertypes.ClusterInfo
+}
+
+func (r *recorder) notify(info *managertypes.ClusterInfo) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.updates = append(r.updates, info)
+}
+
+func (r *recorder) get() []*managertypes.ClusterInfo {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*managertypes.ClusterInfo{}, r.updates...)
+}
+
+func TestProviderReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clusters.yaml")
+
+	// missing file
+	p, err := NewProvider(path)
+	assert.NoError(t, err)
+	_, err = p.GetClusterInfo("a")
+	assert.Error(t, err)
+
+	write := func(content string) {
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write(`
+clusters:
+- name: a
+  endpoints: [{address: 10.0.0.1, port: 80}]
+- name: b
+  endpoints: [{address: 10.0.0.2, port: 80}]
+`)
+	assert.NoError(t, p.Reload())
+	info, err := p.GetClusterInfo("a")
+	assert.NoError(t, err)
+	assert.Len(t, info.Endpoints, 1)
+	assert.Len(t, p.GetAllClusters(), 2)
+
+	var ra, rb recorder
+	p.WatchCluster("a", ra.notify)
+	p.WatchCluster("b", rb.notify)
+	batches := 0
+	p.WatchAllClusters(func() { batches++ })
+
+	// only the changed cluster is notified, the order of the endpoints doesn't matter
+	write(`
+clusters:
+- name: a
+  endpoints: [{address: 10.0.0.3, port: 80, labels: {lora: x}}, {address: 10.0.0.1, port: 80}]
+- name: b
+  endpoints: [{address: 10.0.0.2, port: 80}]
+`)
+	assert.NoError(t, p.Reload())
+	assert.Len(t, ra.get(), 1)
+	assert.Equal(t, []managertypes.Endpoint{
+		{Address: "10.0.0.1", Port: 80},
+		{Address: "10.0.0.3", Port: 80, Labels: map[string]string{"lora": "x"}},
+	}, ra.get()[0].Endpoints)
+	assert.Empty(t, rb.get())
+	assert.Equal(t, 1, batches)
+
+	// invalid file keeps the clusters
+	write(`clusters: [{name: a, endpoints: [{address: 10.0.0.1}]}]`)
+	assert.Error(t, p.Reload())
+	_, err = p.GetClusterInfo("b")
+	assert.NoError(t, err)
+
+	// removed cluster is notified with no endpoints
+	write(`
+clusters:
+- name: a
+  endpoints: [{address: 10.0.0.3, port: 80, labels: {lora: x}}, {address: 10.0.0.1, port: 80}]
+`)
+	assert.NoError(t, p.Reload())
+	assert.Len(t, ra.get(), 1)
+	assert.Equal(t, []*managertypes.ClusterInfo{{Name: "b"}}, rb.get())
+	assert.Equal(t, 2, batches)
+	_, err = p.GetClusterInfo("b")
+	assert.Error(t, err)
+
+	// removed file keeps the clusters
+	assert.NoError(t, os.Remove(path))
+	assert.NoError(t, p.Reload())
+	_, err = p.GetClusterInfo("a")
+	assert.NoError(t, err)
+}
+
+func TestProviderWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clusters.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"clusters": [{"name": "a", "endpoints": [{"address": "10.0.0.1", "port": 80}]}]}`), 0o644))
+	p, err := NewProvider(path)
+	assert.NoError(t, err)
+
+	var r recorder
+	p.WatchCluster("a", r.notify)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Watch(ctx, 10*time.Millisecond)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"clusters": [{"name": "a", "endpoints": [{"address": "10.0.0.1", "port": 80, "weight": 3}]}]}`), 0o644))
+	assert.Eventually(t, func() bool {
+		updates := r.get()
+		return len(updates) == 1 && updates[0].Endpoints[0].Weight == 3
+	}, time.Second, 10*time.Millisecond)
+}
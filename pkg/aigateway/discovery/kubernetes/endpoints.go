@@ -0,0 +1,171 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+const (
+	// defaultPortName is chosen when the service has multiple ports and the cluster name doesn't specify one
+	defaultPortName = "http"
+)
+
+// serviceRef is the service referred by a cluster name, in the format of
+// "<service>[.<namespace>[.svc[.<cluster domain>]]][:<port name or number>]", e.g. "qwen3.llm:http".
+// The namespace defaults to the provider's namespace.
+type serviceRef struct {
+	namespace string
+	name      string
+	// name or number of the port, empty means the only port or the port named "http"
+	port string
+}
+
+func (r serviceRef) key() string {
+	return r.namespace + "/" + r.name
+}
+
+func parseClusterName(cluster, defaultNamespace string) (serviceRef, error) {
+	ref := serviceRef{}
+	host := cluster
+	if i := strings.LastIndexByte(cluster, ':'); i >= 0 {
+		host, ref.port = cluster[:i], cluster[i+1:]
+		if ref.port == "" {
+			return ref, fmt.Errorf("invalid cluster name %s: empty port", cluster)
+		}
+	}
+	if i := strings.Index(host, ".svc"); i >= 0 && (len(host) == i+4 || host[i+4] == '.') {
+		host = host[:i]
+	}
+
+	parts := strings.Split(host, ".")
+	switch len(parts) {
+	case 1:
+		ref.name, ref.namespace = parts[0], defaultNamespace
+	case 2:
+		ref.name, ref.namespace = parts[0], parts[1]
+	default:
+		return ref, fmt.Errorf("invalid cluster name %s: too many parts", cluster)
+	}
+	if ref.name == "" || ref.namespace == "" {
+		return ref, fmt.Errorf("invalid cluster name %s: empty service or namespace", cluster)
+	}
+	return ref, nil
+}
+
+// slicePort returns the port of the slice referred by the ref, false if not found
+func slicePort(slice *discoveryv1.EndpointSlice, ref serviceRef) (uint32, bool) {
+	var chosen *discoveryv1.EndpointPort
+	switch {
+	case ref.port != "":
+		num, err := strconv.Atoi(ref.port)
+		for i := range slice.Ports {
+			p := &slice.Ports[i]
+			if (p.Name != nil && *p.Name == ref.port) || (err == nil && p.Port != nil && int(*p.Port) == num) {
+				chosen = p
+				break
+			}
+		}
+	case len(slice.Ports) == 1:
+		chosen = &slice.Ports[0]
+	default:
+		for i := range slice.Ports {
+			if p := &slice.Ports[i]; p.Name != nil && *p.Name == defaultPortName {
+				chosen = p
+				break
+			}
+		}
+	}
+	if chosen == nil || chosen.Port == nil || *chosen.Port <= 0 {
+		return 0, false
+	}
+	return uint32(*chosen.Port), true
+}
+
+// isReady follows the EndpointSlice API: a nil ready condition should be interpreted as ready
+func isReady(ep *discoveryv1.Endpoint) bool {
+	return ep.Conditions.Ready == nil || *ep.Conditions.Ready
+}
+
+// podLookup returns the pod of the endpoint, nil if unknown
+type podLookup func(namespace, name string) *corev1.Pod
+
+// buildEndpoints converts the ready endpoints in the slices of a service into the cluster endpoints.
+// The pod labels are copied into the endpoint labels, so the hosts can be selected by LoRA or version.
+func buildEndpoints(slices []*discoveryv1.EndpointSlice, ref serviceRef, getPod podLookup) []managertypes.Endpoint {
+	endpoints := []managertypes.Endpoint{}
+	seen := map[string]struct{}{}
+	for _, slice := range slices {
+		if slice.AddressType != discoveryv1.AddressTypeIPv4 && slice.AddressType != discoveryv1.AddressTypeIPv6 {
+			continue
+		}
+		port, ok := slicePort(slice, ref)
+		if !ok {
+			continue
+		}
+		for i := range slice.Endpoints {
+			ep := &slice.Endpoints[i]
+			if !isReady(ep) || len(ep.Addresses) == 0 {
+				continue
+			}
+			// the addresses are fungible, only the first one is used as kube-proxy does
+			address := ep.Addresses[0]
+			key := address + ":" + strconv.Itoa(int(port))
+			// the same endpoint may be in multiple slices during the update
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			endpoints = append(endpoints, managertypes.Endpoint{
+				Address: address,
+				Port:    port,
+				Labels:  endpointLabels(slice.Namespace, ep, getPod),
+			})
+		}
+	}
+	common.SortEndpoints(endpoints)
+	return endpoints
+}
+
+func endpointLabels(namespace string, ep *discoveryv1.Endpoint, getPod podLookup) map[string]string {
+	labels := map[string]string{}
+	if getPod != nil && ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+		ns := ep.TargetRef.Namespace
+		if ns == "" {
+			ns = namespace
+		}
+		if pod := getPod(ns, ep.TargetRef.Name); pod != nil {
+			for k, v := range pod.Labels {
+				labels[k] = v
+			}
+		}
+	}
+	if ep.Zone != nil && *ep.Zone != "" {
+		labels[common.LabelTopologyZone] = *ep.Zone
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
@@ -0,0 +1,290 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kubernetes provides the clusters from the EndpointSlices of the Kubernetes services.
+// It requires the permission to list and watch EndpointSlices, and Pods when the pod labels are used.
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	discoverylisters "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+	pkgcommon "github.com/aigw-project/aigw/pkg/common"
+)
+
+const (
+	// AIGW_K8S_NAMESPACE is the namespace of the services without namespace in the cluster names,
+	// default to the namespace of the gateway pod
+	AIGW_K8S_NAMESPACE = "AIGW_K8S_NAMESPACE"
+	// AIGW_K8S_WATCH_NAMESPACE limits the watched namespace, all namespaces are watched if empty
+	AIGW_K8S_WATCH_NAMESPACE = "AIGW_K8S_WATCH_NAMESPACE"
+	// AIGW_K8S_WATCH_PODS copies the pod labels into the endpoint labels when it's "true"
+	AIGW_K8S_WATCH_PODS = "AIGW_K8S_WATCH_PODS"
+	// AIGW_K8S_RESYNC_PERIOD is the resync period of the informers
+	AIGW_K8S_RESYNC_PERIOD = "AIGW_K8S_RESYNC_PERIOD"
+
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	defaultResyncPeriod         = 10 * time.Minute
+	syncTimeout                 = 30 * time.Second
+)
+
+type Options struct {
+	// Namespace of the services without namespace in the cluster names
+	Namespace string
+	// WatchNamespace limits the watched namespace, all namespaces are watched if empty
+	WatchNamespace string
+	// WatchPods copies the pod labels into the endpoint labels
+	WatchPods    bool
+	ResyncPeriod time.Duration
+}
+
+// Provider is a ClusterInfoProvider which maps the cluster names to the Kubernetes services,
+// and the ready endpoints of the services to the cluster endpoints.
+type Provider struct {
+	opts    Options
+	factory informers.SharedInformerFactory
+
+	sliceLister discoverylisters.EndpointSliceLister
+	podLister   corelisters.PodLister
+	synced      []cache.InformerSynced
+
+	// lock protects the clusters, and serializes the updates so the changes are notified in order
+	lock sync.Mutex
+	// clusters are the clusters requested by GetClusterInfo, keyed by cluster name
+	clusters map[string]*trackedCluster
+
+	watchers common.ClusterWatchers
+}
+
+type trackedCluster struct {
+	ref  serviceRef
+	info *managertypes.ClusterInfo
+}
+
+func NewProvider(client kubernetes.Interface, opts Options) *Provider {
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+	if opts.ResyncPeriod <= 0 {
+		opts.ResyncPeriod = defaultResyncPeriod
+	}
+
+	var factoryOpts []informers.SharedInformerOption
+	if opts.WatchNamespace != "" {
+		factoryOpts = append(factoryOpts, informers.WithNamespace(opts.WatchNamespace))
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(client, opts.ResyncPeriod, factoryOpts...)
+
+	p := &Provider{
+		opts:     opts,
+		factory:  factory,
+		clusters: map[string]*trackedCluster{},
+	}
+
+	sliceInformer := factory.Discovery().V1().EndpointSlices()
+	p.sliceLister = sliceInformer.Lister()
+	p.synced = append(p.synced, sliceInformer.Informer().HasSynced)
+	_, _ = sliceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { p.onSliceChanged(obj) },
+		UpdateFunc: func(_, obj interface{}) { p.onSliceChanged(obj) },
+		DeleteFunc: func(obj interface{}) { p.onSliceChanged(obj) },
+	})
+
+	if opts.WatchPods {
+		podInformer := factory.Core().V1().Pods()
+		p.podLister = podInformer.Lister()
+		p.synced = append(p.synced, podInformer.Informer().HasSynced)
+		_, _ = podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { p.onPodChanged(nil, obj) },
+			UpdateFunc: func(old, obj interface{}) { p.onPodChanged(old, obj) },
+			// the endpoints of the deleted pods are removed from the slices
+		})
+	}
+	return p
+}
+
+// Start starts the informers and waits for the caches to be synced
+func (p *Provider) Start(ctx context.Context) error {
+	p.factory.Start(ctx.Done())
+	syncCtx, cancel := context.WithTimeout(ctx, syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), p.synced...) {
+		return errors.New("timeout waiting for the kubernetes caches to sync")
+	}
+	return nil
+}
+
+func defaultNamespace() string {
+	if ns := os.Getenv(AIGW_K8S_NAMESPACE); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
+
+// NewProviderFromEnv creates the provider with the in-cluster config and starts it
+func NewProviderFromEnv() (*Provider, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	p := NewProvider(client, Options{
+		Namespace:      defaultNamespace(),
+		WatchNamespace: os.Getenv(AIGW_K8S_WATCH_NAMESPACE),
+		WatchPods:      os.Getenv(AIGW_K8S_WATCH_PODS) == "true",
+		ResyncPeriod:   pkgcommon.GetDurationFromEnv(AIGW_K8S_RESYNC_PERIOD, defaultResyncPeriod),
+	})
+	if err := p.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) getPod(namespace, name string) *corev1.Pod {
+	pod, err := p.podLister.Pods(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	return pod
+}
+
+// build returns the cluster info of the service from the informer caches
+func (p *Provider) build(name string, ref serviceRef) (*managertypes.ClusterInfo, error) {
+	selector := k8slabels.SelectorFromSet(k8slabels.Set{discoveryv1.LabelServiceName: ref.name})
+	slices, err := p.sliceLister.EndpointSlices(ref.namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	var getPod podLookup
+	if p.podLister != nil {
+		getPod = p.getPod
+	}
+	return &managertypes.ClusterInfo{
+		Name:      name,
+		Endpoints: buildEndpoints(slices, ref, getPod),
+	}, nil
+}
+
+func (p *Provider) GetClusterInfo(name string) (*managertypes.ClusterInfo, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if c, ok := p.clusters[name]; ok {
+		return c.info, nil
+	}
+
+	ref, err := parseClusterName(name, p.opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if p.opts.WatchNamespace != "" && ref.namespace != p.opts.WatchNamespace {
+		return nil, fmt.Errorf("cluster %s: namespace %s is not watched", name, ref.namespace)
+	}
+	info, err := p.build(name, ref)
+	if err != nil {
+		return nil, err
+	}
+	// track the cluster even if it has no endpoints yet, the watchers are notified when they are ready
+	p.clusters[name] = &trackedCluster{ref: ref, info: info}
+	if len(info.Endpoints) == 0 {
+		return info, fmt.Errorf("no ready endpoint of service %s", ref.key())
+	}
+	return info, nil
+}
+
+func (p *Provider) WatchCluster(name string, notifier managertypes.ClusterInfoNotifier) {
+	p.watchers.Add(name, notifier)
+}
+
+// refresh rebuilds the tracked clusters matched by the filter, and notifies the changed ones
+func (p *Provider) refresh(match func(ref serviceRef) bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for name, c := range p.clusters {
+		if !match(c.ref) {
+			continue
+		}
+		info, err := p.build(name, c.ref)
+		if err != nil {
+			api.LogErrorf("failed to build cluster %s: %v", name, err)
+			continue
+		}
+		if reflect.DeepEqual(info, c.info) {
+			continue
+		}
+		c.info = info
+		api.LogInfof("cluster %s of service %s changed, %d endpoints", name, c.ref.key(), len(info.Endpoints))
+		p.watchers.Notify(info)
+	}
+}
+
+func (p *Provider) onSliceChanged(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	slice, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		return
+	}
+	service := slice.Labels[discoveryv1.LabelServiceName]
+	if service == "" {
+		return
+	}
+	p.refresh(func(ref serviceRef) bool {
+		return ref.namespace == slice.Namespace && ref.name == service
+	})
+}
+
+func (p *Provider) onPodChanged(old, obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	if oldPod, ok := old.(*corev1.Pod); ok && reflect.DeepEqual(oldPod.Labels, pod.Labels) {
+		// only the labels are used, the readiness comes from the slices
+		return
+	}
+	// the pod doesn't know its services, so refresh the clusters in the namespace
+	p.refresh(func(ref serviceRef) bool {
+		return ref.namespace == pod.Namespace
+	})
+}
@@ -0,0 +1,213 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubernetes
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestParseClusterName(t *testing.T) {
+	for name, want := range map[string]serviceRef{
+		"qwen":                            {namespace: "ns", name: "qwen"},
+		"qwen.llm":                        {namespace: "llm", name: "qwen"},
+		"qwen.llm.svc":                    {namespace: "llm", name: "qwen"},
+		"qwen.llm.svc.cluster.local:8000": {namespace: "llm", name: "qwen", port: "8000"},
+		"qwen:grpc":                       {namespace: "ns", name: "qwen", port: "grpc"},
+		"qwen.svcx":                       {namespace: "svcx", name: "qwen"},
+	} {
+		ref, err := parseClusterName(name, "ns")
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, ref, name)
+	}
+
+	for _, name := range []string{"", "qwen:", "a.b.c", ".llm"} {
+		_, err := parseClusterName(name, "ns")
+		assert.Error(t, err, name)
+	}
+}
+
+func newSlice(name, service string, ports []discoveryv1.EndpointPort, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "llm",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports:       ports,
+		Endpoints:   endpoints,
+	}
+}
+
+func newEndpoint(ip string, ready bool, pod string) discoveryv1.Endpoint {
+	ep := discoveryv1.Endpoint{
+		Addresses:  []string{ip},
+		Conditions: discoveryv1.EndpointConditions{Ready: ptr(ready)},
+	}
+	if pod != "" {
+		ep.TargetRef = &corev1.ObjectReference{Kind: "Pod", Name: pod}
+	}
+	return ep
+}
+
+func TestBuildEndpoints(t *testing.T) {
+	ports := []discoveryv1.EndpointPort{
+		{Name: ptr("metrics"), Port: ptr(int32(9090))},
+		{Name: ptr("http"), Port: ptr(int32(8000))},
+	}
+	zoned := newEndpoint("10.0.0.3", true, "")
+	zoned.Zone = ptr("zone-a")
+	nilReady := newEndpoint("10.0.0.4", true, "")
+	nilReady.Conditions.Ready = nil
+	slices := []*discoveryv1.EndpointSlice{
+		newSlice("s1", "qwen", ports, newEndpoint("10.0.0.2", true, "p2"), newEndpoint("10.0.0.1", false, "p1"), zoned),
+		// duplicated endpoint during the update
+		newSlice("s2", "qwen", ports, newEndpoint("10.0.0.2", true, "p2"), nilReady),
+	}
+	pods := map[string]*corev1.Pod{
+		"p2": {ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"lora": "a"}}},
+	}
+	getPod := func(namespace, name string) *corev1.Pod {
+		assert.Equal(t, "llm", namespace)
+		return pods[name]
+	}
+
+	endpoints := buildEndpoints(slices, serviceRef{namespace: "llm", name: "qwen"}, getPod)
+	assert.Equal(t, []managertypes.Endpoint{
+		{Address: "10.0.0.2", Port: 8000, Labels: map[string]string{"lora": "a"}},
+		{Address: "10.0.0.3", Port: 8000, Labels: map[string]string{common.LabelTopologyZone: "zone-a"}},
+		{Address: "10.0.0.4", Port: 8000},
+	}, endpoints)
+
+	// choose the port by name or number
+	endpoints = buildEndpoints(slices, serviceRef{namespace: "llm", name: "qwen", port: "metrics"}, nil)
+	assert.Equal(t, uint32(9090), endpoints[0].Port)
+	assert.Nil(t, endpoints[0].Labels)
+	endpoints = buildEndpoints(slices, serviceRef{namespace: "llm", name: "qwen", port: "9090"}, nil)
+	assert.Equal(t, uint32(9090), endpoints[0].Port)
+	endpoints = buildEndpoints(slices, serviceRef{namespace: "llm", name: "qwen", port: "grpc"}, nil)
+	assert.Empty(t, endpoints)
+}
+
+type recorder struct {
+	lock    sync.Mutex
+	updates []*managertypes.ClusterInfo
+}
+
+func (r *recorder) notify(info *managertypes.ClusterInfo) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.updates = append(r.updates, info)
+}
+
+func (r *recorder) last() *managertypes.ClusterInfo {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.updates) == 0 {
+		return nil
+	}
+	return r.updates[len(r.updates)-1]
+}
+
+func TestProvider(t *testing.T) {
+	ports := []discoveryv1.EndpointPort{{Port: ptr(int32(8000))}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "p1",
+		Namespace: "llm",
+		Labels:    map[string]string{"version": "v1"},
+	}}
+	client := fake.NewSimpleClientset(
+		newSlice("qwen-1", "qwen", ports, newEndpoint("10.0.0.1", true, "p1")),
+		pod,
+	)
+	p := NewProvider(client, Options{Namespace: "llm", WatchPods: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, p.Start(ctx))
+
+	info, err := p.GetClusterInfo("qwen")
+	assert.NoError(t, err)
+	assert.Equal(t, []managertypes.Endpoint{
+		{Address: "10.0.0.1", Port: 8000, Labels: map[string]string{"version": "v1"}},
+	}, info.Endpoints)
+
+	// the service without ready endpoints is tracked, and notified when it's ready
+	_, err = p.GetClusterInfo("deepseek")
+	assert.ErrorContains(t, err, "no ready endpoint")
+	var rq, rd recorder
+	p.WatchCluster("qwen", rq.notify)
+	p.WatchCluster("deepseek", rd.notify)
+
+	slices := client.DiscoveryV1().EndpointSlices("llm")
+	_, err = slices.Create(ctx, newSlice("deepseek-1", "deepseek", ports, newEndpoint("10.0.1.1", true, "")), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		last := rd.last()
+		return last != nil && len(last.Endpoints) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// scale out, the endpoint not ready is skipped
+	_, err = slices.Update(ctx, newSlice("qwen-1", "qwen", ports,
+		newEndpoint("10.0.0.1", true, "p1"), newEndpoint("10.0.0.2", true, ""), newEndpoint("10.0.0.3", false, "")),
+		metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		last := rq.last()
+		return last != nil && len(last.Endpoints) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// pod labels changed
+	pod.Labels = map[string]string{"version": "v2"}
+	_, err = client.CoreV1().Pods("llm").Update(ctx, pod, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		last := rq.last()
+		return last != nil && last.Endpoints[0].Labels["version"] == "v2"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// service removed
+	assert.NoError(t, slices.Delete(ctx, "qwen-1", metav1.DeleteOptions{}))
+	assert.Eventually(t, func() bool {
+		last := rq.last()
+		return last != nil && len(last.Endpoints) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestProviderWatchNamespace(t *testing.T) {
+	p := NewProvider(fake.NewSimpleClientset(), Options{Namespace: "llm", WatchNamespace: "llm"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, p.Start(ctx))
+
+	_, err := p.GetClusterInfo("qwen.other")
+	assert.ErrorContains(t, err, "not watched")
+}
@@ -16,134 +16,379 @@ package staticdemo
 
 import (
 	"context"
-	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 	cluster "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
 
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
 	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
 )
 
+const (
+	// wildcardResource subscribes all the clusters
+	wildcardResource = "*"
+)
+
+// cdsServerImpl serves the clusters of a ClusterLister via SotW and Delta CDS, so the upstream clusters of
+// Envoy are in sync with the cluster manager. The changes of the lister are pushed to all the streams.
 type cdsServerImpl struct {
-	provider     *StaticClusterProvider
-	responseChan chan *discovery.DeltaDiscoveryResponse
 	cluster.UnimplementedClusterDiscoveryServiceServer
+	lister managertypes.ClusterLister
+
+	lock     sync.Mutex
+	seq      uint64
+	snapshot *snapshot
+	// signaled when the snapshot is changed, one per stream
+	streams map[chan struct{}]struct{}
 }
 
-func NewCDSServer(provider *StaticClusterProvider) cluster.ClusterDiscoveryServiceServer {
-	return &cdsServerImpl{
-		provider:     provider,
-		responseChan: make(chan *discovery.DeltaDiscoveryResponse, 100),
+func NewCDSServer(lister managertypes.ClusterLister) cluster.ClusterDiscoveryServiceServer {
+	s := &cdsServerImpl{
+		lister:   lister,
+		snapshot: emptySnapshot(),
+		streams:  map[chan struct{}]struct{}{},
 	}
+	s.update()
+	lister.WatchAllClusters(s.update)
+	// the clusters are generated again with the new templates
+	common.WatchClusterTemplates(s.update)
+	return s
 }
 
-func (s *cdsServerImpl) StreamClusters(stream cluster.ClusterDiscoveryService_StreamClustersServer) error {
-	return nil
+// update rebuilds the snapshot from the lister and notifies the streams when it's changed
+func (s *cdsServerImpl) update() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// under the lock, so the concurrent updates can't apply a stale cluster list
+	next := s.snapshot.next(s.lister.GetAllClusters(), s.seq+1)
+	if next == s.snapshot {
+		return
+	}
+	s.seq++
+	s.snapshot = next
+	api.LogInfof("cds snapshot updated, version: %s, clusters: %d", next.version, len(next.resources))
+	for ch := range s.streams {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// already signaled, the stream will read the latest snapshot
+		}
+	}
 }
 
-func (s *cdsServerImpl) FetchClusters(ctx context.Context, req *discovery.DiscoveryRequest) (*discovery.DiscoveryResponse, error) {
-	panic("implement me")
+func (s *cdsServerImpl) current() *snapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.snapshot
 }
 
-// processDeltaClusterResponse response delta cluster to envoy
-func (s *cdsServerImpl) processDeltaClusterResponse(stream cluster.ClusterDiscoveryService_DeltaClustersServer, errChan chan error) {
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				api.LogErrorf("recovered from panic, closing stream, r:%+v", r)
-				errChan <- status.Errorf(codes.Internal, "internal server error: %+v", r)
-			}
-		}()
+func (s *cdsServerImpl) watch() chan struct{} {
+	ch := make(chan struct{}, 1)
+	s.lock.Lock()
+	s.streams[ch] = struct{}{}
+	s.lock.Unlock()
+	return ch
+}
+
+func (s *cdsServerImpl) unwatch(ch chan struct{}) {
+	s.lock.Lock()
+	delete(s.streams, ch)
+	s.lock.Unlock()
+}
 
-		api.LogInfof("starting delta response goroutine, stream=%v", stream)
+// receive reads the requests in background, so the stream can wait for the requests and the changes together
+func receive[T any](ctx context.Context, recv func() (T, error)) (<-chan T, <-chan error) {
+	reqs := make(chan T)
+	errCh := make(chan error, 1)
+	go func() {
 		for {
+			req, err := recv()
+			if err != nil {
+				errCh <- err
+				return
+			}
 			select {
-			case resp, ok := <-s.responseChan:
-				if !ok {
-					api.LogCriticalf("response channel closed, exiting goroutine")
-					errChan <- fmt.Errorf("response channel closed, exiting goroutine")
-					return
-				}
-				api.LogInfof("sending cluster response:%+v", resp)
-				if err := stream.Send(resp); err != nil {
-					api.LogCriticalf("cluster sending response error: %+v, error: %v", resp, err)
-					errChan <- err
-					return
-				}
-			case <-stream.Context().Done():
-				api.LogInfof("Stream context done, closing delta response goroutine")
+			case reqs <- req:
+			case <-ctx.Done():
 				return
 			}
 		}
 	}()
+	return reqs, errCh
 }
 
-func (s *cdsServerImpl) processAllClusters() {
-	clusters := s.provider.GetAllClusters()
-	if len(clusters) == 0 {
-		api.LogInfof("no existing clusters to send")
-		return
+func logStreamError(stream string, err error) {
+	if common.IsExpectedGRPCError(err) {
+		api.LogInfof("%s stream terminated with status %v", stream, err)
+	} else {
+		api.LogErrorf("%s stream terminated with unexpected error %v", stream, err)
 	}
-	nonce := common.GenerateNonce()
-	resources := make([]*discovery.Resource, 0, len(clusters))
-	for _, c := range clusters {
-		clustercfg := common.GenerateCluster(c.Name, c.Endpoints, false)
-		res := common.ConvertClusterToResource(clustercfg, c.Name)
-		resources = append(resources, res)
+}
+
+// sotwStream is the state of a SotW stream
+type sotwStream struct {
+	// names subscribed by the last request, nil means all the clusters
+	names map[string]struct{}
+	// nonce and version of the last response
+	nonce   string
+	version string
+	started bool
+}
+
+func subscribedNames(names []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if name == wildcardResource {
+			return nil
+		}
+		set[name] = struct{}{}
 	}
+	if len(set) == 0 {
+		return nil
+	}
+	return set
+}
 
-	resp := common.GenerateDeltaDiscoveryResponseWithRemovedResources(resource.ClusterType, nonce, resources, nil)
-	s.responseChan <- resp
+func sameNames(a, b map[string]struct{}) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			return false
+		}
+	}
+	return true
 }
 
-func (s *cdsServerImpl) DeltaClusters(stream cluster.ClusterDiscoveryService_DeltaClustersServer) error {
-	api.LogInfof("new delta clusters stream: %v", stream)
+// onRequest applies the request, returns whether a response should be sent
+func (st *sotwStream) onRequest(req *discovery.DiscoveryRequest) bool {
+	if req.GetTypeUrl() != "" && req.GetTypeUrl() != resource.ClusterType {
+		api.LogErrorf("unexpected type %s in cds stream", req.GetTypeUrl())
+		return false
+	}
+	if req.GetResponseNonce() != "" {
+		if req.GetResponseNonce() != st.nonce {
+			// stale request, the newer response is on the way
+			return false
+		}
+		if req.GetErrorDetail() != nil {
+			api.LogErrorf("got a NACK of cds version %s with nonce %s, error: %s",
+				st.version, req.GetResponseNonce(), req.GetErrorDetail().GetMessage())
+		} else {
+			api.LogDebugf("got an ACK of cds version %s with nonce %s", req.GetVersionInfo(), req.GetResponseNonce())
+		}
+	}
 
-	errChan := make(chan error, 1)
+	names := subscribedNames(req.GetResourceNames())
+	if st.started && sameNames(st.names, names) {
+		// ACK or NACK, the rejected version is not sent again until the clusters change
+		return false
+	}
+	st.started = true
+	st.names = names
+	return true
+}
 
-	// delta clusters send by current stream. if reconnected the goroutine will restart for new stream.
-	s.processDeltaClusterResponse(stream, errChan)
-	s.processAllClusters()
+func (st *sotwStream) response(snap *snapshot) *discovery.DiscoveryResponse {
+	resources := make([]*anypb.Any, 0, len(snap.resources))
+	for _, name := range snap.names() {
+		if st.names != nil {
+			if _, ok := st.names[name]; !ok {
+				continue
+			}
+		}
+		resources = append(resources, snap.resources[name].GetResource())
+	}
+	st.nonce = common.GenerateNonce()
+	st.version = snap.version
+	return &discovery.DiscoveryResponse{
+		VersionInfo: snap.version,
+		Resources:   resources,
+		TypeUrl:     resource.ClusterType,
+		Nonce:       st.nonce,
+	}
+}
 
-	// start or reconnect
+func (s *cdsServerImpl) StreamClusters(stream cluster.ClusterDiscoveryService_StreamClustersServer) error {
+	notify := s.watch()
+	defer s.unwatch(notify)
+	reqs, errCh := receive(stream.Context(), stream.Recv)
+
+	st := &sotwStream{}
 	for {
 		select {
-		case err := <-errChan:
-			if common.IsExpectedGRPCError(err) {
-				api.LogErrorf("stream [%v] terminated with status %v", stream, err)
-			} else {
-				api.LogErrorf("stream [%v] terminated with unexpected error %v", stream, err)
+		case <-stream.Context().Done():
+			return nil
+		case err := <-errCh:
+			logStreamError("sotw cds", err)
+			return err
+		case req := <-reqs:
+			if !st.onRequest(req) {
+				continue
 			}
+		case <-notify:
+			if !st.started || st.version == s.current().version {
+				continue
+			}
+		}
+
+		resp := st.response(s.current())
+		api.LogInfof("sending cds version %s with %d clusters", resp.VersionInfo, len(resp.Resources))
+		if err := stream.Send(resp); err != nil {
+			logStreamError("sotw cds", err)
 			return err
+		}
+	}
+}
+
+func (s *cdsServerImpl) FetchClusters(ctx context.Context, req *discovery.DiscoveryRequest) (*discovery.DiscoveryResponse, error) {
+	st := &sotwStream{names: subscribedNames(req.GetResourceNames())}
+	return st.response(s.current()), nil
+}
+
+// deltaStream is the state of a Delta stream
+type deltaStream struct {
+	wildcard   bool
+	subscribed map[string]struct{}
+	// versions of the clusters the client has, an empty version means the client knows it doesn't exist
+	known   map[string]string
+	nonce   string
+	started bool
+}
+
+func (st *deltaStream) interested(name string) bool {
+	if st.wildcard {
+		return true
+	}
+	_, ok := st.subscribed[name]
+	return ok
+}
+
+// onRequest applies the subscription changes in the request
+func (st *deltaStream) onRequest(req *discovery.DeltaDiscoveryRequest) bool {
+	if req.GetTypeUrl() != "" && req.GetTypeUrl() != resource.ClusterType {
+		api.LogErrorf("unexpected type %s in delta cds stream", req.GetTypeUrl())
+		return false
+	}
+	if !st.started {
+		st.started = true
+		for name, version := range req.GetInitialResourceVersions() {
+			st.known[name] = version
+		}
+		// legacy wildcard: the first request subscribes nothing
+		st.wildcard = len(req.GetResourceNamesSubscribe()) == 0
+	}
+	// the stale ACK or NACK is ignored, but the subscription changes in it still apply
+	if req.GetResponseNonce() != "" && req.GetResponseNonce() == st.nonce {
+		if req.GetErrorDetail() != nil {
+			api.LogErrorf("got a delta cds NACK with nonce %s, error: %s", req.GetResponseNonce(), req.GetErrorDetail().GetMessage())
+		} else {
+			api.LogDebugf("got a delta cds ACK with nonce %s", req.GetResponseNonce())
+		}
+	}
+
+	for _, name := range req.GetResourceNamesSubscribe() {
+		if name == wildcardResource {
+			st.wildcard = true
+			continue
+		}
+		st.subscribed[name] = struct{}{}
+	}
+	for _, name := range req.GetResourceNamesUnsubscribe() {
+		if name == wildcardResource {
+			st.wildcard = false
+			continue
+		}
+		delete(st.subscribed, name)
+		// sent again when it's subscribed again
+		delete(st.known, name)
+	}
+	return true
+}
+
+// response returns the changes since the last response, nil if nothing is changed
+func (st *deltaStream) response(snap *snapshot) *discovery.DeltaDiscoveryResponse {
+	var resources []*discovery.Resource
+	for _, name := range snap.names() {
+		res := snap.resources[name]
+		if !st.interested(name) || st.known[name] == res.Version {
+			continue
+		}
+		st.known[name] = res.Version
+		resources = append(resources, res)
+	}
+
+	var removed []string
+	for name, version := range st.known {
+		if _, ok := snap.resources[name]; ok || version == "" {
+			continue
+		}
+		removed = append(removed, name)
+		delete(st.known, name)
+	}
+	// tell the client the subscribed clusters don't exist, so it doesn't wait for them
+	for name := range st.subscribed {
+		if _, ok := snap.resources[name]; ok {
+			continue
+		}
+		if _, ok := st.known[name]; !ok {
+			removed = append(removed, name)
+			st.known[name] = ""
+		}
+	}
+	if len(resources) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	sort.Strings(removed)
+	st.nonce = common.GenerateNonce()
+	resp := common.GenerateDeltaDiscoveryResponseWithRemovedResources(resource.ClusterType, st.nonce, resources, removed)
+	resp.SystemVersionInfo = snap.version
+	return resp
+}
+
+func (s *cdsServerImpl) DeltaClusters(stream cluster.ClusterDiscoveryService_DeltaClustersServer) error {
+	notify := s.watch()
+	defer s.unwatch(notify)
+	reqs, errCh := receive(stream.Context(), stream.Recv)
+
+	st := &deltaStream{
+		subscribed: map[string]struct{}{},
+		known:      map[string]string{},
+	}
+	for {
+		select {
 		case <-stream.Context().Done():
-			api.LogInfof("Stream [%v] context done, closing delta clusters stream", stream)
 			return nil
-		default:
-			req, err := stream.Recv()
-			if err != nil {
-				api.LogErrorf("receiving request error: %+v", err)
-				return err
+		case err := <-errCh:
+			logStreamError("delta cds", err)
+			return err
+		case req := <-reqs:
+			if !st.onRequest(req) {
+				continue
 			}
-			api.LogInfof("received delta cluster request: %+v", req)
-
-			// Request is an ACK of a previous response - no need to return a cluster.
-			if req.ResponseNonce != "" {
-				if req.ErrorDetail != nil {
-					api.LogCriticalf("got a NACK with nonce %s, error: %s", req.ResponseNonce, req.ErrorDetail.Message)
-				} else {
-					api.LogInfof("got an ACK with nonce %s", req.ResponseNonce)
-				}
+		case <-notify:
+			if !st.started {
 				continue
 			}
+		}
 
-			// TODO: delta watching cluster
-			for _, r := range req.ResourceNamesSubscribe {
-				api.LogInfof("delta watching cluster: %s", r)
-			}
+		resp := st.response(s.current())
+		if resp == nil {
+			continue
+		}
+		api.LogInfof("sending delta cds version %s, %d clusters, removed: %v",
+			resp.SystemVersionInfo, len(resp.Resources), resp.RemovedResources)
+		if err := stream.Send(resp); err != nil {
+			logStreamError("delta cds", err)
+			return err
 		}
 	}
 }
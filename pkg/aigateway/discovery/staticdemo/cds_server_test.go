@@ -0,0 +1,351 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package staticdemo
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	clustercfg "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	cluster "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/anypb"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+type fakeLister struct {
+	lock     sync.Mutex
+	clusters map[string]*managertypes.ClusterInfo
+	notifier func()
+}
+
+func (l *fakeLister) GetAllClusters() []*managertypes.ClusterInfo {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	clusters := make([]*managertypes.ClusterInfo, 0, len(l.clusters))
+	for _, c := range l.clusters {
+		clusters = append(clusters, c)
+	}
+	return clusters
+}
+
+func (l *fakeLister) WatchAllClusters(notifier func()) {
+	l.notifier = notifier
+}
+
+func (l *fakeLister) set(clusters ...*managertypes.ClusterInfo) {
+	l.lock.Lock()
+	l.clusters = map[string]*managertypes.ClusterInfo{}
+	for _, c := range clusters {
+		l.clusters[c.Name] = c
+	}
+	l.lock.Unlock()
+	if l.notifier != nil {
+		l.notifier()
+	}
+}
+
+func clusterInfo(name string, addresses ...string) *managertypes.ClusterInfo {
+	info := &managertypes.ClusterInfo{Name: name}
+	for _, addr := range addresses {
+		info.Endpoints = append(info.Endpoints, managertypes.Endpoint{Address: addr, Port: 8000})
+	}
+	return info
+}
+
+func startTestServer(t *testing.T, lister managertypes.ClusterLister) cluster.ClusterDiscoveryServiceClient {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := grpc.NewServer()
+	cluster.RegisterClusterDiscoveryServiceServer(srv, NewCDSServer(lister))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return cluster.NewClusterDiscoveryServiceClient(conn)
+}
+
+// receiver reads the responses in background, so the test can check no response is sent
+type receiver[T any] struct {
+	ch chan T
+}
+
+func newReceiver[T any](recv func() (T, error)) *receiver[T] {
+	r := &receiver[T]{ch: make(chan T, 10)}
+	go func() {
+		for {
+			resp, err := recv()
+			if err != nil {
+				return
+			}
+			r.ch <- resp
+		}
+	}()
+	return r
+}
+
+func (r *receiver[T]) next(t *testing.T) T {
+	select {
+	case resp := <-r.ch:
+		return resp
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for response")
+		var zero T
+		return zero
+	}
+}
+
+func (r *receiver[T]) none(t *testing.T) {
+	select {
+	case resp := <-r.ch:
+		t.Fatalf("unexpected response: %v", resp)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func clusterNames(t *testing.T, resources []*anypb.Any) []string {
+	names := []string{}
+	for _, res := range resources {
+		c := &clustercfg.Cluster{}
+		assert.NoError(t, res.UnmarshalTo(c))
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestStreamClusters(t *testing.T) {
+	lister := &fakeLister{}
+	lister.set(clusterInfo("a", "10.0.0.1"), clusterInfo("b", "10.0.0.2"))
+	client := startTestServer(t, lister)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamClusters(ctx)
+	assert.NoError(t, err)
+	r := newReceiver(stream.Recv)
+
+	// wildcard
+	assert.NoError(t, stream.Send(&discovery.DiscoveryRequest{TypeUrl: resource.ClusterType}))
+	resp := r.next(t)
+	assert.Equal(t, []string{"a", "b"}, clusterNames(t, resp.Resources))
+	assert.Equal(t, resource.ClusterType, resp.TypeUrl)
+
+	// ACK
+	assert.NoError(t, stream.Send(&discovery.DiscoveryRequest{
+		TypeUrl:       resource.ClusterType,
+		VersionInfo:   resp.VersionInfo,
+		ResponseNonce: resp.Nonce,
+	}))
+	r.none(t)
+
+	// the full state is pushed when a cluster changes
+	lister.set(clusterInfo("a", "10.0.0.1", "10.0.0.3"), clusterInfo("b", "10.0.0.2"))
+	prev := resp
+	resp = r.next(t)
+	assert.NotEqual(t, prev.VersionInfo, resp.VersionInfo)
+	assert.Equal(t, []string{"a", "b"}, clusterNames(t, resp.Resources))
+
+	// NACK, the rejected version is not sent again
+	assert.NoError(t, stream.Send(&discovery.DiscoveryRequest{
+		TypeUrl:       resource.ClusterType,
+		VersionInfo:   prev.VersionInfo,
+		ResponseNonce: resp.Nonce,
+		ErrorDetail:   &status.Status{Message: "invalid"},
+	}))
+	r.none(t)
+
+	// the unchanged clusters are not pushed
+	lister.set(clusterInfo("a", "10.0.0.1", "10.0.0.3"), clusterInfo("b", "10.0.0.2"))
+	r.none(t)
+
+	// subscribe by names
+	assert.NoError(t, stream.Send(&discovery.DiscoveryRequest{
+		TypeUrl:       resource.ClusterType,
+		VersionInfo:   prev.VersionInfo,
+		ResponseNonce: resp.Nonce,
+		ResourceNames: []string{"b"},
+	}))
+	resp = r.next(t)
+	assert.Equal(t, []string{"b"}, clusterNames(t, resp.Resources))
+
+	// stale nonce is ignored
+	assert.NoError(t, stream.Send(&discovery.DiscoveryRequest{
+		TypeUrl:       resource.ClusterType,
+		ResponseNonce: prev.Nonce,
+		ResourceNames: []string{"a"},
+	}))
+	r.none(t)
+}
+
+func TestFetchClusters(t *testing.T) {
+	lister := &fakeLister{}
+	lister.set(clusterInfo("a", "10.0.0.1"), clusterInfo("b", "10.0.0.2"))
+	client := startTestServer(t, lister)
+
+	resp, err := client.FetchClusters(context.Background(), &discovery.DiscoveryRequest{ResourceNames: []string{"a"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, clusterNames(t, resp.Resources))
+	assert.Equal(t, "1", resp.VersionInfo)
+}
+
+func resourceNames(resources []*discovery.Resource) []string {
+	names := []string{}
+	for _, res := range resources {
+		names = append(names, res.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestDeltaClusters(t *testing.T) {
+	lister := &fakeLister{}
+	lister.set(clusterInfo("a", "10.0.0.1"), clusterInfo("b", "10.0.0.2"))
+	client := startTestServer(t, lister)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.DeltaClusters(ctx)
+	assert.NoError(t, err)
+	r := newReceiver(stream.Recv)
+
+	// legacy wildcard
+	assert.NoError(t, stream.Send(&discovery.DeltaDiscoveryRequest{TypeUrl: resource.ClusterType}))
+	resp := r.next(t)
+	assert.Equal(t, []string{"a", "b"}, resourceNames(resp.Resources))
+	versions := map[string]string{}
+	for _, res := range resp.Resources {
+		assert.NotEmpty(t, res.Version)
+		versions[res.Name] = res.Version
+	}
+
+	assert.NoError(t, stream.Send(&discovery.DeltaDiscoveryRequest{TypeUrl: resource.ClusterType, ResponseNonce: resp.Nonce}))
+	r.none(t)
+
+	// only the changes are sent
+	lister.set(clusterInfo("a", "10.0.0.1", "10.0.0.3"), clusterInfo("c", "10.0.0.4"))
+	resp = r.next(t)
+	assert.Equal(t, []string{"a", "c"}, resourceNames(resp.Resources))
+	assert.Equal(t, []string{"b"}, resp.RemovedResources)
+	assert.Equal(t, "2", resp.SystemVersionInfo)
+	for _, res := range resp.Resources {
+		versions[res.Name] = res.Version
+	}
+	delete(versions, "b")
+
+	// NACK is not sent again
+	assert.NoError(t, stream.Send(&discovery.DeltaDiscoveryRequest{
+		TypeUrl:       resource.ClusterType,
+		ResponseNonce: resp.Nonce,
+		ErrorDetail:   &status.Status{Message: "invalid"},
+	}))
+	r.none(t)
+	cancel()
+
+	// the clusters the client already has are not sent after reconnection
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	stream, err = client.DeltaClusters(ctx)
+	assert.NoError(t, err)
+	r = newReceiver(stream.Recv)
+	versions["a"] = "stale"
+	assert.NoError(t, stream.Send(&discovery.DeltaDiscoveryRequest{
+		TypeUrl:                 resource.ClusterType,
+		InitialResourceVersions: versions,
+	}))
+	resp = r.next(t)
+	assert.Equal(t, []string{"a"}, resourceNames(resp.Resources))
+	assert.Empty(t, resp.RemovedResources)
+}
+
+func TestDeltaClustersSubscribe(t *testing.T) {
+	lister := &fakeLister{}
+	lister.set(clusterInfo("a", "10.0.0.1"), clusterInfo("b", "10.0.0.2"))
+	client := startTestServer(t, lister)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.DeltaClusters(ctx)
+	assert.NoError(t, err)
+	r := newReceiver(stream.Recv)
+
+	// the missing cluster is reported as removed
+	assert.NoError(t, stream.Send(&discovery.DeltaDiscoveryRequest{
+		TypeUrl:                resource.ClusterType,
+		ResourceNamesSubscribe: []string{"a", "x"},
+	}))
+	resp := r.next(t)
+	assert.Equal(t, []string{"a"}, resourceNames(resp.Resources))
+	assert.Equal(t, []string{"x"}, resp.RemovedResources)
+
+	// the cluster not subscribed is not sent
+	lister.set(clusterInfo("a", "10.0.0.1"), clusterInfo("b", "10.0.0.3"))
+	r.none(t)
+
+	// the subscribed cluster is sent once it exists
+	lister.set(clusterInfo("a", "10.0.0.1"), clusterInfo("b", "10.0.0.3"), clusterInfo("x", "10.0.0.5"))
+	resp = r.next(t)
+	assert.Equal(t, []string{"x"}, resourceNames(resp.Resources))
+
+	assert.NoError(t, stream.Send(&discovery.DeltaDiscoveryRequest{
+		TypeUrl:                  resource.ClusterType,
+		ResourceNamesSubscribe:   []string{"b"},
+		ResourceNamesUnsubscribe: []string{"a"},
+	}))
+	resp = r.next(t)
+	assert.Equal(t, []string{"b"}, resourceNames(resp.Resources))
+	assert.Empty(t, resp.RemovedResources)
+
+	// the unsubscribed cluster is not sent
+	lister.set(clusterInfo("a", "10.0.0.9"), clusterInfo("b", "10.0.0.3"), clusterInfo("x", "10.0.0.5"))
+	r.none(t)
+}
+
+func TestDeltaClustersTemplateChanged(t *testing.T) {
+	lister := &fakeLister{}
+	lister.set(clusterInfo("a", "10.0.0.1"), clusterInfo("b", "10.0.0.2"))
+	client := startTestServer(t, lister)
+	t.Cleanup(func() { assert.NoError(t, common.SetClusterTemplates(nil)) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.DeltaClusters(ctx)
+	assert.NoError(t, err)
+	r := newReceiver(stream.Recv)
+	assert.NoError(t, stream.Send(&discovery.DeltaDiscoveryRequest{TypeUrl: resource.ClusterType}))
+	resp := r.next(t)
+	assert.Equal(t, []string{"a", "b"}, resourceNames(resp.Resources))
+
+	// only the cluster using the template is sent again
+	assert.NoError(t, common.SetClusterTemplates(map[string]*common.ClusterTemplate{"a": {HTTP2: true}}))
+	resp = r.next(t)
+	assert.Equal(t, []string{"a"}, resourceNames(resp.Resources))
+	c := &clustercfg.Cluster{}
+	assert.NoError(t, resp.Resources[0].Resource.UnmarshalTo(c))
+	assert.NotNil(t, c.Http2ProtocolOptions)
+}
@@ -15,53 +15,38 @@
 package staticdemo
 
 import (
-	"encoding/json"
 	"errors"
-	"io"
 	"os"
 
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 
 	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/file"
 )
 
 const (
-	staticClusterFile = "/etc/aigw/static_clusters.json"
+	staticClusterFile = file.DefaultPath
 )
 
-type StaticEndpoint struct {
-	Address string `json:"address"`
-	Port    uint32 `json:"port"`
-}
-
-type StaticCluster struct {
-	Name      string           `json:"name"`
-	Endpoints []StaticEndpoint `json:"endpoints"`
-}
-
-type ClustersConfig struct {
-	Clusters []StaticCluster `json:"clusters"`
-}
-
-var config ClustersConfig
+// clusters are loaded once, use the file provider to reload the clusters when the file changes
+var clusters map[string]*managertypes.ClusterInfo
 
 func init() {
-	fp, err := os.Open(staticClusterFile)
+	data, err := os.ReadFile(staticClusterFile)
 	if err != nil {
-		api.LogErrorf("failed to open %s: %v", staticClusterFile, err)
+		api.LogErrorf("failed to read %s: %v", staticClusterFile, err)
 		return
 	}
-	defer fp.Close()
 
-	data, err := io.ReadAll(fp)
+	config, err := file.Parse(data)
 	if err != nil {
-		api.LogErrorf("failed to read %s: %v", staticClusterFile, err)
+		api.LogErrorf("failed to parse %s: %v", staticClusterFile, err)
 		return
 	}
-
-	err = json.Unmarshal(data, &config)
+	clusters, err = config.ClusterInfos()
 	if err != nil {
-		api.LogErrorf("failed to unmarshal %s: %v", staticClusterFile, err)
+		api.LogErrorf("invalid clusters in %s: %v", staticClusterFile, err)
+		return
 	}
 
 	api.LogInfof("static cluster config loaded: %+v", config)
@@ -75,23 +60,10 @@ func NewStaticClusterProvider() managertypes.ClusterInfoProvider {
 	p := &StaticClusterProvider{
 		allClusters: make(map[string]*managertypes.ClusterInfo),
 	}
-	for _, c := range config.Clusters {
-		endpoints := make([]managertypes.Endpoint, 0, len(c.Endpoints))
-		for _, ep := range c.Endpoints {
-			endpoints = append(endpoints, managertypes.Endpoint{
-				Address: ep.Address,
-				Port:    ep.Port,
-			})
-		}
-		p.allClusters[c.Name] = &managertypes.ClusterInfo{
-			Name:      c.Name,
-			Endpoints: endpoints,
-		}
+	for name, info := range clusters {
+		p.allClusters[name] = info
 	}
-
 	api.LogInfof("new static cluster provider: %+v", p)
-
-	startCdsServer(defaultCdsAddress, p)
 	return p
 }
 
@@ -118,9 +90,10 @@ func (p *StaticClusterProvider) GetClusterInfo(name string) (*managertypes.Clust
 	return nil, errors.New("cluster not found")
 }
 
+func (p *StaticClusterProvider) WatchAllClusters(notifier func()) {
+	// static clusters won't change
+}
+
 func (p *StaticClusterProvider) WatchCluster(name string, notifier managertypes.ClusterInfoNotifier) {
-	// TODO: static cluster won't change, so just notify once
-	if cluster := p.getCluster(name); cluster != nil {
-		notifier(cluster)
-	}
+	// static clusters won't change, the caller already got them via GetClusterInfo
 }
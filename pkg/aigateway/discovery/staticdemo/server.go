@@ -16,19 +16,34 @@ package staticdemo
 
 import (
 	"net"
+	"os"
 	"time"
 
 	cluster "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
 )
 
 const (
+	// AIGW_CDS_ADDRESS is the listening address of the local CDS server
+	AIGW_CDS_ADDRESS = "AIGW_CDS_ADDRESS"
+
 	defaultCdsAddress = "127.0.0.1:9999"
 )
 
-func startCdsServer(address string, provider *StaticClusterProvider) {
+// StartCDSServer serves the clusters of the lister to Envoy, on the address in AIGW_CDS_ADDRESS
+func StartCDSServer(lister managertypes.ClusterLister) {
+	address := os.Getenv(AIGW_CDS_ADDRESS)
+	if address == "" {
+		address = defaultCdsAddress
+	}
+	startCdsServer(address, lister)
+}
+
+func startCdsServer(address string, lister managertypes.ClusterLister) {
 	lis, err := net.Listen("tcp", address)
 	if err != nil {
 		api.LogErrorf("listen local cds server failed: %v", err)
@@ -44,7 +59,7 @@ func startCdsServer(address string, provider *StaticClusterProvider) {
 		}),
 	}
 
-	cdsServer := NewCDSServer(provider)
+	cdsServer := NewCDSServer(lister)
 	grpcSrv := grpc.NewServer(grpcOptions...)
 	cluster.RegisterClusterDiscoveryServiceServer(grpcSrv, cdsServer)
 
@@ -0,0 +1,89 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package staticdemo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"google.golang.org/protobuf/proto"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+// snapshot is an immutable view of the CDS resources. Each resource has its own version, the hash of the
+// cluster, so the delta streams only send the changed clusters.
+type snapshot struct {
+	// version is bumped when any resource is added, changed or removed
+	version   string
+	resources map[string]*discovery.Resource
+}
+
+func emptySnapshot() *snapshot {
+	return &snapshot{version: "0", resources: map[string]*discovery.Resource{}}
+}
+
+// resourceVersion hashes the cluster, the bytes in the Any of the resource can't be used since they are not
+// marshaled deterministically, e.g. the order of the endpoint metadata
+func resourceVersion(cluster proto.Message) string {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(cluster)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// next returns the snapshot of the clusters, or the snapshot itself when nothing is changed
+func (s *snapshot) next(clusters []*managertypes.ClusterInfo, seq uint64) *snapshot {
+	resources := make(map[string]*discovery.Resource, len(clusters))
+	for _, c := range clusters {
+		cluster := common.GenerateClusterWithTemplate(c.Name, c.Endpoints, common.GetClusterTemplate(c.Name))
+		res := common.ConvertClusterToResource(cluster, c.Name)
+		if res == nil {
+			api.LogErrorf("failed to convert cluster %s to resource", c.Name)
+			continue
+		}
+		res.Version = resourceVersion(cluster)
+		resources[c.Name] = res
+	}
+
+	changed := len(resources) != len(s.resources)
+	for name, res := range resources {
+		if old, ok := s.resources[name]; !ok || old.Version != res.Version {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return s
+	}
+	return &snapshot{version: strconv.FormatUint(seq, 10), resources: resources}
+}
+
+// names returns the sorted resource names, so the responses are stable
+func (s *snapshot) names() []string {
+	names := make([]string, 0, len(s.resources))
+	for name := range s.resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
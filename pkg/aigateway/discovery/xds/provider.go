@@ -0,0 +1,318 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package xds provides the clusters from the EDS of an xDS management server like istiod, so the scheduling
+// view of the gateway is the same as Envoy's. The cluster names are the EDS resource names,
+// e.g. "outbound|8000||qwen.llm.svc.cluster.local" in Istio.
+package xds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	corecfg "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointcfg "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+	pkgcommon "github.com/aigw-project/aigw/pkg/common"
+)
+
+const (
+	// AIGW_XDS_SERVER is the address of the ADS server, e.g. "istiod.istio-system.svc:15010", in plaintext
+	AIGW_XDS_SERVER = "AIGW_XDS_SERVER"
+	// AIGW_XDS_NODE_ID is the node id sent to the server, default to "aigw~<hostname>"
+	AIGW_XDS_NODE_ID = "AIGW_XDS_NODE_ID"
+	// AIGW_XDS_NODE_CLUSTER is the node cluster sent to the server, default to "aigw"
+	AIGW_XDS_NODE_CLUSTER = "AIGW_XDS_NODE_CLUSTER"
+	// AIGW_XDS_INITIAL_FETCH_TIMEOUT limits how long GetClusterInfo waits for the first EDS response of a cluster
+	AIGW_XDS_INITIAL_FETCH_TIMEOUT = "AIGW_XDS_INITIAL_FETCH_TIMEOUT"
+
+	defaultNodeCluster        = "aigw"
+	defaultInitialFetchTimout = time.Second
+	minBackoff                = 100 * time.Millisecond
+	maxBackoff                = 30 * time.Second
+)
+
+type Options struct {
+	Node *corecfg.Node
+	// InitialFetchTimeout limits how long GetClusterInfo waits for the first EDS response of a cluster
+	InitialFetchTimeout time.Duration
+}
+
+type edsCluster struct {
+	info *managertypes.ClusterInfo
+	// closed once the first response of the cluster is received
+	ready     chan struct{}
+	readyOnce sync.Once
+}
+
+func (c *edsCluster) markReady() {
+	c.readyOnce.Do(func() { close(c.ready) })
+}
+
+// Provider is a ClusterInfoProvider subscribing the ClusterLoadAssignments over ADS.
+// The clusters are subscribed on demand, when they are requested by GetClusterInfo.
+type Provider struct {
+	opts   Options
+	client discovery.AggregatedDiscoveryServiceClient
+
+	lock     sync.Mutex
+	clusters map[string]*edsCluster
+	// subscribed is signaled when new clusters are added, so the stream sends the new resource names
+	subscribed chan struct{}
+
+	watchers common.ClusterWatchers
+}
+
+func NewProvider(conn grpc.ClientConnInterface, opts Options) *Provider {
+	if opts.InitialFetchTimeout <= 0 {
+		opts.InitialFetchTimeout = defaultInitialFetchTimout
+	}
+	return &Provider{
+		opts:       opts,
+		client:     discovery.NewAggregatedDiscoveryServiceClient(conn),
+		clusters:   map[string]*edsCluster{},
+		subscribed: make(chan struct{}, 1),
+	}
+}
+
+// NewProviderFromEnv connects to the server in AIGW_XDS_SERVER and runs the subscription in background
+func NewProviderFromEnv() (*Provider, error) {
+	server := os.Getenv(AIGW_XDS_SERVER)
+	if server == "" {
+		return nil, fmt.Errorf("%s is not set", AIGW_XDS_SERVER)
+	}
+	conn, err := grpc.NewClient(server, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	nodeID := os.Getenv(AIGW_XDS_NODE_ID)
+	if nodeID == "" {
+		hostname, _ := os.Hostname()
+		nodeID = "aigw~" + hostname
+	}
+	nodeCluster := os.Getenv(AIGW_XDS_NODE_CLUSTER)
+	if nodeCluster == "" {
+		nodeCluster = defaultNodeCluster
+	}
+	p := NewProvider(conn, Options{
+		Node:                &corecfg.Node{Id: nodeID, Cluster: nodeCluster},
+		InitialFetchTimeout: pkgcommon.GetDurationFromEnv(AIGW_XDS_INITIAL_FETCH_TIMEOUT, defaultInitialFetchTimout),
+	})
+	go p.Run(context.Background())
+	return p, nil
+}
+
+// Run keeps the ADS stream until ctx is done, reconnects with backoff when the stream is broken
+func (p *Provider) Run(ctx context.Context) {
+	backoff := minBackoff
+	for {
+		start := time.Now()
+		err := p.stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > maxBackoff {
+			// the stream worked for a while, reconnect quickly
+			backoff = minBackoff
+		}
+		api.LogErrorf("xds stream terminated, reconnect after %v: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func (p *Provider) resourceNames() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	names := make([]string, 0, len(p.clusters))
+	for name := range p.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stream runs a single ADS stream, the resources are subscribed again after reconnection
+func (p *Provider) stream(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s, err := p.client.StreamAggregatedResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	responses := make(chan *discovery.DiscoveryResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := s.Recv()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			select {
+			case responses <- resp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// version and nonce of the last accepted response
+	version, nonce := "", ""
+	names := p.resourceNames()
+	send := func(errorDetail *status.Status) error {
+		return s.Send(&discovery.DiscoveryRequest{
+			Node:          p.opts.Node,
+			TypeUrl:       resource.EndpointType,
+			ResourceNames: names,
+			VersionInfo:   version,
+			ResponseNonce: nonce,
+			ErrorDetail:   errorDetail,
+		})
+	}
+	if len(names) > 0 {
+		if err := send(nil); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errCh:
+			return err
+		case <-p.subscribed:
+			latest := p.resourceNames()
+			if reflect.DeepEqual(latest, names) {
+				// already sent when the stream started
+				continue
+			}
+			names = latest
+			if err := send(nil); err != nil {
+				return err
+			}
+		case resp := <-responses:
+			if resp.GetTypeUrl() != resource.EndpointType {
+				continue
+			}
+			if err := p.handleResponse(resp); err != nil {
+				// NACK with the last accepted version
+				api.LogErrorf("rejected eds response, version: %s, nonce: %s: %v", resp.GetVersionInfo(), resp.GetNonce(), err)
+				nonce = resp.GetNonce()
+				if err := send(&status.Status{Code: int32(codes.InvalidArgument), Message: err.Error()}); err != nil {
+					return err
+				}
+				continue
+			}
+			version, nonce = resp.GetVersionInfo(), resp.GetNonce()
+			if err := send(nil); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleResponse applies the response, nothing is changed if any resource is invalid
+func (p *Provider) handleResponse(resp *discovery.DiscoveryResponse) error {
+	infos := make([]*managertypes.ClusterInfo, 0, len(resp.GetResources()))
+	for _, res := range resp.GetResources() {
+		cla := &endpointcfg.ClusterLoadAssignment{}
+		if err := res.UnmarshalTo(cla); err != nil {
+			return err
+		}
+		info, err := translate(cla)
+		if err != nil {
+			return err
+		}
+		infos = append(infos, info)
+	}
+
+	var changed []*managertypes.ClusterInfo
+	p.lock.Lock()
+	for _, info := range infos {
+		c, ok := p.clusters[info.Name]
+		if !ok {
+			// not subscribed, e.g. unsubscribed by the previous stream
+			continue
+		}
+		if c.info == nil || !reflect.DeepEqual(c.info, info) {
+			c.info = info
+			changed = append(changed, info)
+		}
+		c.markReady()
+	}
+	p.lock.Unlock()
+
+	for _, info := range changed {
+		api.LogInfof("eds cluster %s changed, %d endpoints", info.Name, len(info.Endpoints))
+		p.watchers.Notify(info)
+	}
+	return nil
+}
+
+func (p *Provider) GetClusterInfo(name string) (*managertypes.ClusterInfo, error) {
+	p.lock.Lock()
+	c, ok := p.clusters[name]
+	if !ok {
+		c = &edsCluster{ready: make(chan struct{})}
+		p.clusters[name] = c
+		select {
+		case p.subscribed <- struct{}{}:
+		default:
+			// already signaled, the new name will be sent together
+		}
+	}
+	p.lock.Unlock()
+
+	select {
+	case <-c.ready:
+	case <-time.After(p.opts.InitialFetchTimeout):
+		return nil, fmt.Errorf("timeout waiting for the endpoints of cluster %s", name)
+	}
+
+	p.lock.Lock()
+	info := c.info
+	p.lock.Unlock()
+	if len(info.Endpoints) == 0 {
+		return info, errors.New("no healthy endpoint")
+	}
+	return info, nil
+}
+
+func (p *Provider) WatchCluster(name string, notifier managertypes.ClusterInfoNotifier) {
+	p.watchers.Add(name, notifier)
+}
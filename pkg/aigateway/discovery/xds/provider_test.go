@@ -0,0 +1,311 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	corecfg "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointcfg "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+func lbEndpoint(ip string, port uint32, health corecfg.HealthStatus, labels map[string]interface{}) *endpointcfg.LbEndpoint {
+	ep := &endpointcfg.LbEndpoint{
+		HostIdentifier: &endpointcfg.LbEndpoint_Endpoint{
+			Endpoint: &endpointcfg.Endpoint{
+				Address: &corecfg.Address{
+					Address: &corecfg.Address_SocketAddress{
+						SocketAddress: &corecfg.SocketAddress{
+							Address:       ip,
+							PortSpecifier: &corecfg.SocketAddress_PortValue{PortValue: port},
+						},
+					},
+				},
+			},
+		},
+		HealthStatus: health,
+	}
+	if labels != nil {
+		md, _ := structpb.NewStruct(labels)
+		ep.Metadata = &corecfg.Metadata{FilterMetadata: map[string]*structpb.Struct{lbMetadataKey: md}}
+	}
+	return ep
+}
+
+func TestTranslate(t *testing.T) {
+	weighted := lbEndpoint("10.0.0.2", 8000, corecfg.HealthStatus_HEALTHY, nil)
+	weighted.LoadBalancingWeight = wrapperspb.UInt32(3)
+	cla := &endpointcfg.ClusterLoadAssignment{
+		ClusterName: "outbound|8000||qwen.llm.svc.cluster.local",
+		Endpoints: []*endpointcfg.LocalityLbEndpoints{
+			{
+				Locality: &corecfg.Locality{Region: "cn", Zone: "a"},
+				LbEndpoints: []*endpointcfg.LbEndpoint{
+					lbEndpoint("10.0.0.3", 8000, corecfg.HealthStatus_UNKNOWN, map[string]interface{}{"version": "v2", "replicas": 2.0}),
+					lbEndpoint("10.0.0.1", 8000, corecfg.HealthStatus_UNHEALTHY, nil),
+					weighted,
+				},
+			},
+			{
+				// failover
+				Priority:    1,
+				LbEndpoints: []*endpointcfg.LbEndpoint{lbEndpoint("10.1.0.1", 8000, corecfg.HealthStatus_HEALTHY, nil)},
+			},
+		},
+	}
+	info, err := translate(cla)
+	assert.NoError(t, err)
+	zone := map[string]string{common.LabelTopologyRegion: "cn", common.LabelTopologyZone: "a"}
+	assert.Equal(t, &managertypes.ClusterInfo{
+		Name: cla.ClusterName,
+		Endpoints: []managertypes.Endpoint{
+			{Address: "10.0.0.2", Port: 8000, Weight: 3, Labels: zone},
+			{Address: "10.0.0.3", Port: 8000, Labels: map[string]string{
+				"version":                  "v2",
+				common.LabelTopologyRegion: "cn",
+				common.LabelTopologyZone:   "a",
+			}},
+		},
+	}, info)
+
+	// failover to the lower priority when the higher one has no healthy endpoint
+	cla.Endpoints[0].LbEndpoints = cla.Endpoints[0].LbEndpoints[1:2]
+	info, err = translate(cla)
+	assert.NoError(t, err)
+	assert.Equal(t, []managertypes.Endpoint{{Address: "10.1.0.1", Port: 8000}}, info.Endpoints)
+
+	_, err = translate(&endpointcfg.ClusterLoadAssignment{
+		ClusterName: "a",
+		Endpoints: []*endpointcfg.LocalityLbEndpoints{
+			{LbEndpoints: []*endpointcfg.LbEndpoint{lbEndpoint("10.0.0.1", 0, corecfg.HealthStatus_HEALTHY, nil)}},
+		},
+	})
+	assert.ErrorContains(t, err, "invalid endpoint address")
+}
+
+// adsServer is a fake ADS server, which records the requests and sends the responses pushed by the test
+type adsServer struct {
+	discovery.UnimplementedAggregatedDiscoveryServiceServer
+
+	requests  chan *discovery.DiscoveryRequest
+	responses chan *discovery.DiscoveryResponse
+	// closing the channel breaks the current stream
+	lock  sync.Mutex
+	reset chan struct{}
+}
+
+func (s *adsServer) StreamAggregatedResources(stream discovery.AggregatedDiscoveryService_StreamAggregatedResourcesServer) error {
+	s.lock.Lock()
+	brk := s.reset
+	s.lock.Unlock()
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			s.requests <- req
+		}
+	}()
+	for {
+		select {
+		case resp := <-s.responses:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		case <-brk:
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *adsServer) breakStream() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	close(s.reset)
+	s.reset = make(chan struct{})
+}
+
+func startServer(t *testing.T) (*adsServer, *grpc.ClientConn) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	srv := grpc.NewServer()
+	ads := &adsServer{
+		requests:  make(chan *discovery.DiscoveryRequest, 100),
+		responses: make(chan *discovery.DiscoveryResponse, 100),
+		reset:     make(chan struct{}),
+	}
+	discovery.RegisterAggregatedDiscoveryServiceServer(srv, ads)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return ads, conn
+}
+
+func nextRequest(t *testing.T, ads *adsServer) *discovery.DiscoveryRequest {
+	select {
+	case req := <-ads.requests:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for request")
+		return nil
+	}
+}
+
+func response(t *testing.T, version, nonce string, clas ...*endpointcfg.ClusterLoadAssignment) *discovery.DiscoveryResponse {
+	resp := &discovery.DiscoveryResponse{
+		TypeUrl:     resource.EndpointType,
+		VersionInfo: version,
+		Nonce:       nonce,
+	}
+	for _, cla := range clas {
+		res, err := anypb.New(cla)
+		assert.NoError(t, err)
+		resp.Resources = append(resp.Resources, res)
+	}
+	return resp
+}
+
+func assignment(name string, ips ...string) *endpointcfg.ClusterLoadAssignment {
+	locality := &endpointcfg.LocalityLbEndpoints{}
+	for _, ip := range ips {
+		locality.LbEndpoints = append(locality.LbEndpoints, lbEndpoint(ip, 8000, corecfg.HealthStatus_HEALTHY, nil))
+	}
+	return &endpointcfg.ClusterLoadAssignment{
+		ClusterName: name,
+		Endpoints:   []*endpointcfg.LocalityLbEndpoints{locality},
+	}
+}
+
+type recorder struct {
+	lock    sync.Mutex
+	updates []*managertypes.ClusterInfo
+}
+
+func (r *recorder) notify(info *managertypes.ClusterInfo) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.updates = append(r.updates, info)
+}
+
+func (r *recorder) count() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.updates)
+}
+
+func (r *recorder) last() *managertypes.ClusterInfo {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.updates[len(r.updates)-1]
+}
+
+func TestProvider(t *testing.T) {
+	ads, conn := startServer(t)
+	node := &corecfg.Node{Id: "aigw~test"}
+	p := NewProvider(conn, Options{Node: node, InitialFetchTimeout: 5 * time.Second})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	// subscribe on demand
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		info, err := p.GetClusterInfo("qwen")
+		assert.NoError(t, err)
+		assert.Len(t, info.Endpoints, 2)
+	}()
+	req := nextRequest(t, ads)
+	assert.Equal(t, resource.EndpointType, req.TypeUrl)
+	assert.Equal(t, []string{"qwen"}, req.ResourceNames)
+	assert.Equal(t, "aigw~test", req.Node.Id)
+	ads.responses <- response(t, "1", "n1", assignment("qwen", "10.0.0.1", "10.0.0.2"))
+	<-done
+
+	// ACK
+	req = nextRequest(t, ads)
+	assert.Equal(t, "1", req.VersionInfo)
+	assert.Equal(t, "n1", req.ResponseNonce)
+	assert.Nil(t, req.ErrorDetail)
+
+	var r recorder
+	p.WatchCluster("qwen", r.notify)
+
+	// NACK, keep the accepted version
+	ads.responses <- response(t, "2", "n2", &endpointcfg.ClusterLoadAssignment{})
+	req = nextRequest(t, ads)
+	assert.Equal(t, "1", req.VersionInfo)
+	assert.Equal(t, "n2", req.ResponseNonce)
+	assert.NotNil(t, req.ErrorDetail)
+	assert.Equal(t, 0, r.count())
+
+	// unchanged assignment is not notified
+	ads.responses <- response(t, "3", "n3", assignment("qwen", "10.0.0.2", "10.0.0.1"))
+	nextRequest(t, ads)
+	assert.Equal(t, 0, r.count())
+
+	// scale in
+	ads.responses <- response(t, "4", "n4", assignment("qwen", "10.0.0.1"))
+	nextRequest(t, ads)
+	assert.Eventually(t, func() bool { return r.count() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []managertypes.Endpoint{{Address: "10.0.0.1", Port: 8000}}, r.last().Endpoints)
+
+	// subscribed again after reconnection
+	ads.breakStream()
+	req = nextRequest(t, ads)
+	assert.Equal(t, []string{"qwen"}, req.ResourceNames)
+	assert.Equal(t, "", req.VersionInfo)
+}
+
+func TestProviderInitialFetchTimeout(t *testing.T) {
+	ads, conn := startServer(t)
+	p := NewProvider(conn, Options{InitialFetchTimeout: 50 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	_, err := p.GetClusterInfo("qwen")
+	assert.ErrorContains(t, err, "timeout")
+	nextRequest(t, ads)
+
+	// no healthy endpoint
+	ads.responses <- response(t, "1", "n1", assignment("qwen"))
+	nextRequest(t, ads)
+	_, err = p.GetClusterInfo("qwen")
+	assert.ErrorContains(t, err, "no healthy endpoint")
+}
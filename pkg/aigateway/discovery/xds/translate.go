@@ -0,0 +1,115 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xds
+
+import (
+	"fmt"
+
+	corecfg "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointcfg "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	managertypes "github.com/aigw-project/aigw/pkg/aigateway/clustermanager/types"
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+const (
+	// the metadata used by the subset load balancer of Envoy, Istio puts the workload labels here
+	lbMetadataKey = "envoy.lb"
+)
+
+// isUsable returns whether Envoy would route to the endpoint, the endpoints without health check are UNKNOWN
+func isUsable(lbEp *endpointcfg.LbEndpoint) bool {
+	switch lbEp.GetHealthStatus() {
+	case corecfg.HealthStatus_UNKNOWN, corecfg.HealthStatus_HEALTHY:
+		return true
+	}
+	return false
+}
+
+// endpointLabels merges the string values in the "envoy.lb" metadata and the locality into the labels
+func endpointLabels(locality *corecfg.Locality, lbEp *endpointcfg.LbEndpoint) map[string]string {
+	labels := map[string]string{}
+	if md := lbEp.GetMetadata().GetFilterMetadata()[lbMetadataKey]; md != nil {
+		for k, v := range md.GetFields() {
+			if s, ok := v.GetKind().(*structpb.Value_StringValue); ok {
+				labels[k] = s.StringValue
+			}
+		}
+	}
+	for k, v := range map[string]string{
+		common.LabelTopologyRegion:  locality.GetRegion(),
+		common.LabelTopologyZone:    locality.GetZone(),
+		common.LabelTopologySubZone: locality.GetSubZone(),
+	} {
+		if v != "" {
+			labels[k] = v
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// translate converts the ClusterLoadAssignment into the ClusterInfo. Like Envoy, only the endpoints in the
+// highest priority (the lowest number) with any usable endpoint are used, the lower priorities are the failover.
+func translate(cla *endpointcfg.ClusterLoadAssignment) (*managertypes.ClusterInfo, error) {
+	info := &managertypes.ClusterInfo{
+		Name:      cla.GetClusterName(),
+		Endpoints: []managertypes.Endpoint{},
+	}
+	if info.Name == "" {
+		return nil, fmt.Errorf("cluster name is empty")
+	}
+
+	byPriority := map[uint32][]managertypes.Endpoint{}
+	for _, locality := range cla.GetEndpoints() {
+		for _, lbEp := range locality.GetLbEndpoints() {
+			if !isUsable(lbEp) {
+				continue
+			}
+			// the named endpoints are resolved by Envoy, which can't be used here
+			socket := lbEp.GetEndpoint().GetAddress().GetSocketAddress()
+			if socket == nil {
+				continue
+			}
+			port := socket.GetPortValue()
+			if socket.GetAddress() == "" || port == 0 {
+				return nil, fmt.Errorf("cluster %s: invalid endpoint address %v", info.Name, socket)
+			}
+			byPriority[locality.GetPriority()] = append(byPriority[locality.GetPriority()], managertypes.Endpoint{
+				Address: socket.GetAddress(),
+				Port:    port,
+				Labels:  endpointLabels(locality.GetLocality(), lbEp),
+				Weight:  lbEp.GetLoadBalancingWeight().GetValue(),
+			})
+		}
+	}
+
+	best := uint32(0)
+	found := false
+	for priority := range byPriority {
+		if !found || priority < best {
+			best = priority
+			found = true
+		}
+	}
+	if found {
+		info.Endpoints = byPriority[best]
+	}
+	common.SortEndpoints(info.Endpoints)
+	return info, nil
+}
@@ -0,0 +1,74 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package basiclb implements the load balancers which don't depend on the metrics of the inference engines.
+// They honor the subsets, retries and outlier detection in the same way as the inference load balancer.
+package basiclb
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/manager"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+)
+
+func init() {
+	manager.RegisterLbType(types.Random, NewRandom)
+	manager.RegisterLbType(types.RoundRobin, NewRoundRobin)
+	manager.RegisterLbType(types.LeastRequest, NewLeastRequest)
+	manager.RegisterLbType(types.ConsistentHash, NewConsistentHash)
+}
+
+// hostList is the hosts replaced by UpdateHosts, which may be called concurrently with ChooseHost
+type hostList struct {
+	hosts atomic.Pointer[[]types.Host]
+}
+
+func (l *hostList) UpdateHosts(hosts []types.Host) {
+	l.hosts.Store(&hosts)
+}
+
+// candidates returns the hosts could be chosen by the request
+func (l *hostList) candidates(ctx context.Context) []types.Host {
+	return inferencelb.FilterHosts(ctx, *l.hosts.Load())
+}
+
+func hostWeight(h types.Host) uint32 {
+	return max(1, h.Weight())
+}
+
+func randomHost(hosts []types.Host) types.Host {
+	if len(hosts) == 0 {
+		return nil
+	}
+	return hosts[rand.Intn(len(hosts))]
+}
+
+type randomLoadBalancer struct {
+	hostList
+}
+
+// NewRandom creates the load balancer which chooses the host randomly, regardless of the weights
+func NewRandom(ctx context.Context, hosts []types.Host) types.LoadBalancer {
+	lb := &randomLoadBalancer{}
+	lb.UpdateHosts(hosts)
+	return lb
+}
+
+func (lb *randomLoadBalancer) ChooseHost(ctx context.Context) types.Host {
+	return randomHost(lb.candidates(ctx))
+}
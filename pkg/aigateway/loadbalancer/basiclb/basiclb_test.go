@@ -0,0 +1,234 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basiclb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/host"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/manager"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+)
+
+func buildHosts(weights ...uint32) []types.Host {
+	hosts := make([]types.Host, 0, len(weights))
+	for i, w := range weights {
+		h := host.BuildHost("test", fmt.Sprintf("10.0.0.%d", i+1), 8000, w)
+		h.SetLabels(map[string]string{"group": fmt.Sprint(i % 2)})
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+func testContext() context.Context {
+	return context.WithValue(context.Background(), inferencelb.KeyClusterName, "test")
+}
+
+func countChoices(lb types.LoadBalancer, ctx context.Context, n int) map[string]int {
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[lb.ChooseHost(ctx).Address()]++
+	}
+	return counts
+}
+
+func TestRegistered(t *testing.T) {
+	hosts := buildHosts(1)
+	for _, lbType := range []types.LoadBalancerType{types.Random, types.RoundRobin, types.LeastRequest, types.ConsistentHash} {
+		lb := manager.CreateLbByType(lbType, context.Background(), hosts)
+		assert.NotNil(t, lb, lbType)
+		assert.Implements(t, (*types.HostUpdater)(nil), lb, lbType)
+	}
+	// unknown algorithm falls back to round robin
+	assert.IsType(t, &roundRobinLoadBalancer{}, manager.CreateLbByType("unknown", context.Background(), hosts))
+}
+
+func TestFilterCandidates(t *testing.T) {
+	hosts := buildHosts(1, 1, 1, 1)
+	selected := context.WithValue(testContext(), inferencelb.KeyLbSelector, map[string]string{"group": "1"})
+	for _, f := range []func(context.Context, []types.Host) types.LoadBalancer{NewRandom, NewRoundRobin, NewLeastRequest, NewConsistentHash} {
+		lb := f(context.Background(), hosts)
+		counts := countChoices(lb, selected, 100)
+		assert.Equal(t, 100, counts["10.0.0.2:8000"]+counts["10.0.0.4:8000"], "%T", lb)
+
+		none := context.WithValue(testContext(), inferencelb.KeyLbSelector, map[string]string{"group": "2"})
+		assert.Nil(t, lb.ChooseHost(none), "%T", lb)
+		assert.Nil(t, f(context.Background(), nil).ChooseHost(testContext()), "%T", lb)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	lb := NewRandom(context.Background(), buildHosts(1, 1, 1))
+	counts := countChoices(lb, testContext(), 3000)
+	assert.Len(t, counts, 3)
+	for addr, n := range counts {
+		assert.InDelta(t, 1000, n, 200, addr)
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	hosts := buildHosts(1, 2, 0)
+	lb := NewRoundRobin(context.Background(), hosts)
+	ctx := testContext()
+
+	// smooth: the host with weight 2 is interleaved with the others
+	var seq []string
+	for i := 0; i < 8; i++ {
+		seq = append(seq, lb.ChooseHost(ctx).Address())
+	}
+	assert.Equal(t, []string{
+		"10.0.0.2:8000", "10.0.0.1:8000", "10.0.0.3:8000", "10.0.0.2:8000",
+		"10.0.0.2:8000", "10.0.0.1:8000", "10.0.0.3:8000", "10.0.0.2:8000",
+	}, seq)
+
+	// the removed host is forgotten
+	lb.(types.HostUpdater).UpdateHosts(hosts[:2])
+	rr := lb.(*roundRobinLoadBalancer)
+	rr.lock.Lock()
+	assert.NotContains(t, rr.current, "10.0.0.3:8000")
+	rr.lock.Unlock()
+	assert.Equal(t, map[string]int{"10.0.0.1:8000": 100, "10.0.0.2:8000": 200}, countChoices(lb, ctx, 300))
+}
+
+func TestLeastRequest(t *testing.T) {
+	hosts := buildHosts(1, 1)
+	lb := NewLeastRequest(context.Background(), hosts)
+	ctx := testContext()
+	for i := 0; i < 3; i++ {
+		hosts[0].(types.RequestTracker).RequestStarted()
+	}
+	assert.Equal(t, map[string]int{"10.0.0.2:8000": 20}, countChoices(lb, ctx, 20))
+
+	// the load is weighted: (2 + 1) / 4 < (0 + 1) / 1
+	weighted := buildHosts(4, 1)
+	lb.(types.HostUpdater).UpdateHosts(weighted)
+	for i := 0; i < 2; i++ {
+		weighted[0].(types.RequestTracker).RequestStarted()
+	}
+	assert.Equal(t, map[string]int{"10.0.0.1:8000": 20}, countChoices(lb, ctx, 20))
+
+	// with more hosts, the busiest one is never chosen
+	many := buildHosts(1, 1, 1, 1)
+	many[2].(types.RequestTracker).RequestStarted()
+	lb.(types.HostUpdater).UpdateHosts(many)
+	assert.NotContains(t, countChoices(lb, ctx, 200), "10.0.0.3:8000")
+}
+
+func TestConsistentHash(t *testing.T) {
+	hosts := buildHosts(1, 1, 1, 1, 1)
+	lb := NewConsistentHash(context.Background(), hosts)
+	withKey := func(key string) context.Context {
+		return context.WithValue(testContext(), inferencelb.KeyHashKey, key)
+	}
+
+	chosen := map[string]string{}
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("session-", i)
+		h := lb.ChooseHost(withKey(key))
+		assert.Equal(t, h, lb.ChooseHost(withKey(key)))
+		chosen[key] = h.Address()
+	}
+	for addr, n := range countValues(chosen) {
+		assert.InDelta(t, 200, n, 80, addr)
+	}
+
+	// adding a host only moves the keys to it
+	added := buildHosts(1, 1, 1, 1, 1, 1)
+	lb.(types.HostUpdater).UpdateHosts(added)
+	moved := 0
+	for key, addr := range chosen {
+		now := lb.ChooseHost(withKey(key)).Address()
+		if now != addr {
+			assert.Equal(t, "10.0.0.6:8000", now)
+			moved++
+		}
+	}
+	assert.InDelta(t, 1000/6, moved, 80)
+
+	// random without the key
+	assert.Len(t, countChoices(lb, testContext(), 600), 6)
+}
+
+func TestConsistentHashWeight(t *testing.T) {
+	lb := NewConsistentHash(context.Background(), buildHosts(1, 3))
+	chosen := map[string]string{}
+	for i := 0; i < 4000; i++ {
+		key := fmt.Sprint("user-", i)
+		chosen[key] = lb.ChooseHost(context.WithValue(testContext(), inferencelb.KeyHashKey, key)).Address()
+	}
+	counts := countValues(chosen)
+	assert.InDelta(t, 1000, counts["10.0.0.1:8000"], 250)
+	assert.InDelta(t, 3000, counts["10.0.0.2:8000"], 250)
+}
+
+func TestConsistentHashBoundedLoad(t *testing.T) {
+	hosts := buildHosts(1, 1, 1)
+	lb := NewConsistentHash(context.Background(), hosts)
+	ctx := context.WithValue(testContext(), inferencelb.KeyHashKey, "session")
+	sticky := lb.ChooseHost(ctx)
+	sticky.(types.RequestTracker).RequestStarted()
+	sticky.(types.RequestTracker).RequestStarted()
+
+	// unbounded
+	assert.Equal(t, sticky, lb.ChooseHost(ctx))
+	// the bound is ceil((2 + 1) / 3 * 1.5) = 2, the sticky host is full
+	bounded := context.WithValue(ctx, inferencelb.KeyAffinityLoadFactor, 0.5)
+	next := lb.ChooseHost(bounded)
+	assert.NotEqual(t, sticky, next)
+	// the bound is ceil((2 + 1) / 3 * 3) = 3
+	loose := context.WithValue(ctx, inferencelb.KeyAffinityLoadFactor, 2.0)
+	assert.Equal(t, sticky, lb.ChooseHost(loose))
+
+	// the spilled requests go to the same next host
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, next, lb.ChooseHost(bounded))
+	}
+}
+
+func countValues(m map[string]string) map[string]int {
+	counts := map[string]int{}
+	for _, v := range m {
+		counts[v]++
+	}
+	return counts
+}
+
+func TestConcurrentUpdate(t *testing.T) {
+	for _, f := range []func(context.Context, []types.Host) types.LoadBalancer{NewRandom, NewRoundRobin, NewLeastRequest, NewConsistentHash} {
+		lb := f(context.Background(), buildHosts(1, 2))
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx := context.WithValue(testContext(), inferencelb.KeyHashKey, fmt.Sprint(i))
+				for j := 0; j < 500; j++ {
+					assert.NotNil(t, lb.ChooseHost(ctx))
+				}
+			}()
+		}
+		for i := 0; i < 100; i++ {
+			lb.(types.HostUpdater).UpdateHosts(buildHosts(1, uint32(i%3), 2)[:1+i%3])
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basiclb
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/hashring"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+	pkgcommon "github.com/aigw-project/aigw/pkg/common"
+)
+
+// consistentHashLoadBalancer is the ring hash on inferencelb.KeyHashKey. When the host of the key is not a candidate,
+// e.g. it's ejected or not matching the selector, the next one on the ring is chosen, so the key still maps to
+// the same host consistently. With inferencelb.KeyAffinityLoadFactor, the loads are bounded by the requests in flight.
+type consistentHashLoadBalancer struct {
+	// the hosts and their ring are replaced together
+	state atomic.Pointer[ringState]
+}
+
+type ringState struct {
+	hosts []types.Host
+	ring  *hashring.Ring
+}
+
+func NewConsistentHash(ctx context.Context, hosts []types.Host) types.LoadBalancer {
+	lb := &consistentHashLoadBalancer{}
+	lb.UpdateHosts(hosts)
+	return lb
+}
+
+func (lb *consistentHashLoadBalancer) UpdateHosts(hosts []types.Host) {
+	lb.state.Store(&ringState{hosts: hosts, ring: hashring.New(hosts)})
+}
+
+func (lb *consistentHashLoadBalancer) ChooseHost(ctx context.Context) types.Host {
+	state := lb.state.Load()
+	hosts := inferencelb.FilterHosts(ctx, state.hosts)
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	key := pkgcommon.GetValueFromCtx(ctx, inferencelb.KeyHashKey, "")
+	if key == "" {
+		api.LogDebugf("no hash key, choose the host randomly")
+		return randomHost(hosts)
+	}
+
+	candidates := make(map[string]int64, len(hosts))
+	var total int64
+	for _, h := range hosts {
+		load := inflight(h)
+		candidates[h.Address()] = load
+		total += load
+	}
+	bound := -1.0
+	if factor, ok := ctx.Value(inferencelb.KeyAffinityLoadFactor).(float64); ok {
+		bound = hashring.BoundedLoad(float64(total), len(hosts), factor)
+	}
+	h := state.ring.Find(key, func(h types.Host) bool {
+		load, ok := candidates[h.Address()]
+		return ok && (bound < 0 || float64(load+1) <= bound)
+	})
+	if h == nil {
+		return randomHost(hosts)
+	}
+	return h
+}
@@ -0,0 +1,64 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basiclb
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+)
+
+// leastRequestLoadBalancer chooses the less loaded one of two random hosts, known as the power of two choices.
+// It avoids herding to the same least loaded host, as the in-flight counts are local to this gateway.
+type leastRequestLoadBalancer struct {
+	hostList
+}
+
+func NewLeastRequest(ctx context.Context, hosts []types.Host) types.LoadBalancer {
+	lb := &leastRequestLoadBalancer{}
+	lb.UpdateHosts(hosts)
+	return lb
+}
+
+func (lb *leastRequestLoadBalancer) ChooseHost(ctx context.Context) types.Host {
+	hosts := lb.candidates(ctx)
+	if len(hosts) <= 1 {
+		return randomHost(hosts)
+	}
+
+	i := rand.Intn(len(hosts))
+	j := rand.Intn(len(hosts) - 1)
+	if j >= i {
+		j++
+	}
+	if hostLoad(hosts[j]) < hostLoad(hosts[i]) {
+		return hosts[j]
+	}
+	return hosts[i]
+}
+
+// hostLoad is the requests in flight per weight, including the one to choose,
+// so a host with a higher weight takes proportionally more requests
+func hostLoad(h types.Host) float64 {
+	return float64(inflight(h)+1) / float64(hostWeight(h))
+}
+
+func inflight(h types.Host) int64 {
+	if t, ok := h.(types.RequestTracker); ok {
+		return t.Inflight()
+	}
+	return 0
+}
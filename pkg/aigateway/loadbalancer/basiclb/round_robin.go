@@ -0,0 +1,77 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package basiclb
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+)
+
+// roundRobinLoadBalancer is the smooth weighted round robin of nginx: every host gains its weight in each round,
+// the host with the most is chosen and loses the total weight. The hosts are interleaved instead of in bursts.
+type roundRobinLoadBalancer struct {
+	hostList
+
+	lock sync.Mutex
+	// current weights by address, kept when the hosts are updated
+	current map[string]int64
+}
+
+func NewRoundRobin(ctx context.Context, hosts []types.Host) types.LoadBalancer {
+	lb := &roundRobinLoadBalancer{current: map[string]int64{}}
+	lb.UpdateHosts(hosts)
+	return lb
+}
+
+func (lb *roundRobinLoadBalancer) UpdateHosts(hosts []types.Host) {
+	lb.hostList.UpdateHosts(hosts)
+
+	addresses := make(map[string]struct{}, len(hosts))
+	for _, h := range hosts {
+		addresses[h.Address()] = struct{}{}
+	}
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	for addr := range lb.current {
+		if _, ok := addresses[addr]; !ok {
+			delete(lb.current, addr)
+		}
+	}
+}
+
+func (lb *roundRobinLoadBalancer) ChooseHost(ctx context.Context) types.Host {
+	hosts := lb.candidates(ctx)
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	var chosen types.Host
+	var best, total int64
+	for _, h := range hosts {
+		w := int64(hostWeight(h))
+		total += w
+		cur := lb.current[h.Address()] + w
+		lb.current[h.Address()] = cur
+		if chosen == nil || cur > best {
+			chosen, best = h, cur
+		}
+	}
+	lb.current[chosen.Address()] -= total
+	return chosen
+}
@@ -0,0 +1,123 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hashring implements the consistent hash ring of the hosts, adding or removing a host only remaps
+// the keys of its neighbors.
+package hashring
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/twmb/murmur3"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+)
+
+const (
+	// virtual nodes of each host per weight on the ring
+	replicas = 100
+	// max virtual nodes on the ring, so a large cluster with high weights doesn't take too much memory
+	maxSize = 64 * 1024
+)
+
+type entry struct {
+	hash uint64
+	host types.Host
+}
+
+// Ring is sorted by hash, immutable after it's built
+type Ring struct {
+	entries []entry
+	hosts   int
+}
+
+func weight(h types.Host) uint32 {
+	return max(1, h.Weight())
+}
+
+// New builds the ring, each host has virtual nodes in proportion to its weight
+func New(hosts []types.Host) *Ring {
+	var total int
+	for _, h := range hosts {
+		total += int(weight(h)) * replicas
+	}
+	// scale down the replicas when the ring is too large, at least one per host
+	scale := min(1, float64(maxSize)/float64(max(total, 1)))
+
+	entries := make([]entry, 0, min(total, maxSize+len(hosts)))
+	for _, h := range hosts {
+		n := max(1, int(float64(int(weight(h))*replicas)*scale))
+		addr := h.Address()
+		for i := 0; i < n; i++ {
+			entries = append(entries, entry{hash: murmur3.StringSum64(addr + "_" + strconv.Itoa(i)), host: h})
+		}
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		// the address keeps the order stable for the collisions
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.host.Address(), b.host.Address()))
+	})
+	return &Ring{entries: entries, hosts: len(hosts)}
+}
+
+// Size returns the number of virtual nodes
+func (r *Ring) Size() int {
+	return len(r.entries)
+}
+
+// Find walks the distinct hosts clockwise from the hash of the key, and returns the first one accepted,
+// nil if none is accepted
+func (r *Ring) Find(key string, accept func(types.Host) bool) types.Host {
+	if len(r.entries) == 0 {
+		return nil
+	}
+	hash := murmur3.StringSum64(key)
+	start, _ := slices.BinarySearchFunc(r.entries, hash, func(e entry, h uint64) int {
+		return cmp.Compare(e.hash, h)
+	})
+
+	// only allocated when the first host is rejected
+	var rejected map[string]struct{}
+	for i := 0; i < len(r.entries); i++ {
+		h := r.entries[(start+i)%len(r.entries)].host
+		if rejected != nil {
+			if _, ok := rejected[h.Address()]; ok {
+				continue
+			}
+		}
+		if accept(h) {
+			return h
+		}
+		if rejected == nil {
+			rejected = map[string]struct{}{}
+		}
+		rejected[h.Address()] = struct{}{}
+		if len(rejected) == r.hosts {
+			break
+		}
+	}
+	return nil
+}
+
+// BoundedLoad returns the max load of each host in the consistent hashing with bounded loads:
+// the average load including the new request, multiplied by (1 + factor), rounded up.
+// A host whose load reaches the bound is skipped, so the hot keys spill over to the next hosts on the ring.
+func BoundedLoad(totalLoad float64, hosts int, factor float64) float64 {
+	if hosts <= 0 {
+		return 0
+	}
+	return math.Ceil((totalLoad + 1) / float64(hosts) * (1 + factor))
+}
@@ -0,0 +1,67 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/host"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+)
+
+func buildHosts(weights ...uint32) []types.Host {
+	hosts := make([]types.Host, 0, len(weights))
+	for i, w := range weights {
+		hosts = append(hosts, host.BuildHost("test", fmt.Sprintf("10.0.0.%d", i+1), 8000, w))
+	}
+	return hosts
+}
+
+func all(types.Host) bool { return true }
+
+func TestRing(t *testing.T) {
+	assert.Nil(t, New(nil).Find("key", all))
+
+	hosts := buildHosts(1, 2, 0)
+	r := New(hosts)
+	assert.Equal(t, 400, r.Size())
+	h := r.Find("key", all)
+	assert.Equal(t, h, New(hosts).Find("key", all))
+
+	// the distinct hosts are walked in order
+	var walked []string
+	assert.Nil(t, r.Find("key", func(h types.Host) bool {
+		walked = append(walked, h.Address())
+		return false
+	}))
+	assert.Len(t, walked, 3)
+	assert.Equal(t, h.Address(), walked[0])
+	assert.Equal(t, walked[1], r.Find("key", func(h types.Host) bool { return h.Address() != walked[0] }).Address())
+
+	// the ring is bounded
+	assert.LessOrEqual(t, New(buildHosts(100000, 100000)).Size(), maxSize+2)
+	// scaled down to 65 virtual nodes per host
+	assert.Equal(t, 65*1000, New(buildHosts(make([]uint32, 1000)...)).Size())
+}
+
+func TestBoundedLoad(t *testing.T) {
+	assert.Equal(t, 0.0, BoundedLoad(10, 0, 0.25))
+	assert.Equal(t, 2.0, BoundedLoad(2, 3, 0.5))
+	assert.Equal(t, 1.0, BoundedLoad(0, 4, 0.25))
+	assert.Equal(t, 13.0, BoundedLoad(40, 4, 0.25))
+}
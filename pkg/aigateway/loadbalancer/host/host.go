@@ -14,13 +14,50 @@
 
 package host
 
-import "strconv"
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
 
+// State is the mutable state of a host. It's shared by the hosts with the same address across the cluster
+// updates, so the state is kept when the weight or labels of the host change.
+type State struct {
+	addedAt time.Time
+	// requests in flight, routed to the host by this gateway
+	inflight atomic.Int64
+	// the host is removed from the cluster and waits for the requests in flight
+	draining atomic.Bool
+}
+
+func NewState() *State {
+	return &State{addedAt: time.Now()}
+}
+
+// AddedAt returns when the host is added to the cluster
+func (s *State) AddedAt() time.Time {
+	return s.addedAt
+}
+
+func (s *State) Inflight() int64 {
+	return s.inflight.Load()
+}
+
+func (s *State) Draining() bool {
+	return s.draining.Load()
+}
+
+func (s *State) SetDraining(draining bool) {
+	s.draining.Store(draining)
+}
+
+// Host is immutable except the State, so it can be read without lock while the cluster is updated
 type Host struct {
 	ip     string
 	port   uint32
 	weight uint32
 	labels map[string]string
+	state  *State
 }
 
 func BuildHost(clusterName string, ip string, port uint32, weight uint32) *Host {
@@ -28,6 +65,18 @@ func BuildHost(clusterName string, ip string, port uint32, weight uint32) *Host
 		ip:     ip,
 		port:   port,
 		weight: weight,
+		state:  NewState(),
+	}
+}
+
+// WithAttributes returns a copy of the host with the new weight and labels, which shares the state
+func (h *Host) WithAttributes(weight uint32, labels map[string]string) *Host {
+	return &Host{
+		ip:     h.ip,
+		port:   h.port,
+		weight: weight,
+		labels: labels,
+		state:  h.state,
 	}
 }
 
@@ -48,6 +97,7 @@ func (h *Host) Weight() uint32 {
 	return h.weight
 }
 
+// SetLabels should only be called before the host is used by the load balancers
 func (h *Host) SetLabels(labels map[string]string) {
 	h.labels = labels
 }
@@ -55,3 +105,24 @@ func (h *Host) SetLabels(labels map[string]string) {
 func (h *Host) Labels() map[string]string {
 	return h.labels
 }
+
+func (h *Host) State() *State {
+	return h.state
+}
+
+// AddedAt returns when the host is added to the cluster, kept across the updates of its weight and labels
+func (h *Host) AddedAt() time.Time {
+	return h.state.AddedAt()
+}
+
+func (h *Host) RequestStarted() {
+	h.state.inflight.Add(1)
+}
+
+func (h *Host) RequestFinished() {
+	h.state.inflight.Add(-1)
+}
+
+func (h *Host) Inflight() int64 {
+	return h.state.Inflight()
+}
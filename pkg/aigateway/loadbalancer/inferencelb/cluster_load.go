@@ -0,0 +1,85 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferencelb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+
+	"github.com/aigw-project/aigw/pkg/metadata_center"
+	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
+)
+
+// the cached load is considered outdated after clusterLoadTTL
+const clusterLoadTTL = 3 * time.Second
+
+// ClusterLoad is the summary of the load of a cluster, which is queried from metadata center when choosing host
+type ClusterLoad struct {
+	Hosts       int
+	TotalReqs   int
+	PrefillReqs int
+	UpdatedAt   time.Time
+}
+
+var clusterLoads sync.Map
+
+func storeClusterLoad(cluster string, stats map[string]*mctypes.EndpointStats) {
+	load := &ClusterLoad{
+		Hosts:     len(stats),
+		UpdatedAt: time.Now(),
+	}
+	for _, stat := range stats {
+		if stat == nil {
+			continue
+		}
+		load.TotalReqs += stat.TotalReqs
+		load.PrefillReqs += stat.PrefillReqs
+	}
+	clusterLoads.Store(cluster, load)
+}
+
+// GetClusterLoad returns the latest load of the cluster, false is returned when there is no recent load
+func GetClusterLoad(cluster string) (ClusterLoad, bool) {
+	v, ok := clusterLoads.Load(cluster)
+	if !ok {
+		return ClusterLoad{}, false
+	}
+	load := v.(*ClusterLoad)
+	if time.Since(load.UpdatedAt) > clusterLoadTTL {
+		return ClusterLoad{}, false
+	}
+	return *load, true
+}
+
+// QueryClusterLoad returns the latest load of the cluster like GetClusterLoad, but queries it from metadata center
+// when there is no recent load, e.g. the cluster is not chosen recently
+func QueryClusterLoad(ctx context.Context, cluster string) (ClusterLoad, bool) {
+	if load, ok := GetClusterLoad(cluster); ok {
+		return load, true
+	}
+	if !metadata_center.IsMetaDataCenterEnable() {
+		return ClusterLoad{}, false
+	}
+	stats, err := metadata_center.GetMetadataCenter().QueryLoad(ctx, cluster)
+	if err != nil {
+		api.LogWarnf("failed to query the load of cluster %s from metadata center, err: %v", cluster, err)
+		return ClusterLoad{}, false
+	}
+	storeClusterLoad(cluster, stats)
+	return GetClusterLoad(cluster)
+}
@@ -0,0 +1,131 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferencelb
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+	pkgcommon "github.com/aigw-project/aigw/pkg/common"
+	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+const (
+	// KeyExplain is a *Explanation filled by the load balancer, only set when the explanation is requested
+	KeyExplain pkgcommon.LBCtxKey = "lb.explain"
+
+	StrategyScore          = "score"
+	StrategyRandom         = "random"
+	StrategyFallbackRandom = "fallback_random"
+	// StrategyAffinity keeps the session on its host by the consistent hash with bounded loads
+	StrategyAffinity = "affinity"
+
+	// max candidates in the explanation, to keep the header compact
+	maxExplainCandidates = 5
+)
+
+// CandidateExplanation is the score and its factors of a ranked host
+type CandidateExplanation struct {
+	Host         string  `json:"host"`
+	Score        float64 `json:"score"`
+	CacheHitRate float64 `json:"cache"`
+	RequestLoad  float64 `json:"req_load"`
+	PrefillLoad  float64 `json:"prefill_load"`
+	Warmup       float64 `json:"warmup"`
+	QueuedReqs   int     `json:"queued"`
+	PromptLength int     `json:"prompt_len"`
+}
+
+// Explanation explains why the host is chosen
+type Explanation struct {
+	Cluster  string `json:"cluster"`
+	Strategy string `json:"strategy"`
+	// hosts after filtering by selector and outlier detection
+	Hosts int `json:"hosts"`
+	// the host is chosen randomly from the top CandidateNum hosts
+	CandidateNum int                    `json:"cand_num"`
+	Chosen       string                 `json:"chosen"`
+	Top          []CandidateExplanation `json:"top,omitempty"`
+}
+
+func (e *Explanation) String() string {
+	b, _ := json.Marshal(e)
+	return string(b)
+}
+
+// decision is the result of ranking the hosts
+type decision struct {
+	cluster  string
+	strategy string
+	hosts    int
+	candNum  int
+	// sorted by score in desc order, empty if not ranked
+	ranked []*EndpointStatsWrapper
+}
+
+// record exports the metrics of the decision, and fills the explanation if requested
+func (d *decision) record(ctx context.Context, chosen types.Host) {
+	prom.LBCandidateCount.WithLabelValues(d.cluster).Observe(float64(d.hosts))
+	if d.strategy == StrategyFallbackRandom {
+		prom.LBFallbackRandomTotal.WithLabelValues(d.cluster).Inc()
+	}
+
+	var chosenStat *EndpointStatsWrapper
+	if len(d.ranked) > 0 {
+		prom.LBScoreSpread.WithLabelValues(d.cluster).Observe(math.Abs(d.ranked[0].Score - d.ranked[len(d.ranked)-1].Score))
+		// the affinity host may be out of the top candidates
+		for _, stat := range d.ranked {
+			if stat.Host.Address() == chosen.Address() {
+				chosenStat = stat
+				break
+			}
+		}
+		if chosenStat != nil {
+			prom.LBChosenCacheHitRatio.WithLabelValues(d.cluster).Observe(chosenStat.CacheHitRate)
+			if stats, ok := ctx.Value(KeyChosenStats).(*mctypes.EndpointStats); ok && stats != nil && chosenStat.EndpointStats != nil {
+				*stats = *chosenStat.EndpointStats
+			}
+		}
+	}
+
+	explanation, ok := ctx.Value(KeyExplain).(*Explanation)
+	if !ok || explanation == nil {
+		return
+	}
+	explanation.Cluster = d.cluster
+	explanation.Strategy = d.strategy
+	explanation.Hosts = d.hosts
+	explanation.CandidateNum = d.candNum
+	explanation.Chosen = chosen.Address()
+	explanation.Top = explanation.Top[:0]
+	for _, stat := range d.ranked[:min(len(d.ranked), maxExplainCandidates)] {
+		c := CandidateExplanation{
+			Host:         stat.Host.Address(),
+			Score:        stat.Score,
+			CacheHitRate: stat.CacheHitRate,
+			RequestLoad:  stat.RequestLoad,
+			PrefillLoad:  stat.PrefillLoad,
+			Warmup:       stat.Warmup,
+		}
+		if stat.EndpointStats != nil {
+			c.QueuedReqs = stat.EndpointStats.TotalReqs
+			c.PromptLength = stat.EndpointStats.PromptLength
+		}
+		explanation.Top = append(explanation.Top, c)
+	}
+}
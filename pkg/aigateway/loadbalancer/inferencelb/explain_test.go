@@ -0,0 +1,87 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferencelb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/host"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+func TestExplanation(t *testing.T) {
+	cluster := t.Name()
+	mockEndpointStats(t, map[string]int{"10.0.0.1": 0, "10.0.0.2": 4}, nil)
+	lb := InferenceLoadBalancerFactory(context.Background(), []types.Host{
+		host.BuildHost(cluster, "10.0.0.1", 8000, 1),
+		host.BuildHost(cluster, "10.0.0.2", 8000, 1),
+	})
+
+	explanation := &Explanation{}
+	chosen := &mctypes.EndpointStats{}
+	ctx := context.WithValue(lbContext(cluster, true), KeyExplain, explanation)
+	ctx = context.WithValue(ctx, KeyChosenStats, chosen)
+	assert.Equal(t, "10.0.0.1:8000", lb.ChooseHost(ctx).Address())
+	assert.JSONEq(t, `{
+		"cluster": "TestExplanation",
+		"strategy": "score",
+		"hosts": 2,
+		"cand_num": 1,
+		"chosen": "10.0.0.1:8000",
+		"top": [
+			{"host": "10.0.0.1:8000", "score": 0, "cache": 0, "req_load": 0, "prefill_load": 0, "warmup": 1, "queued": 0, "prompt_len": 0},
+			{"host": "10.0.0.2:8000", "score": -1, "cache": 0, "req_load": 1, "prefill_load": 0, "warmup": 1, "queued": 4, "prompt_len": 0}
+		]
+	}`, explanation.String())
+	assert.Equal(t, mctypes.EndpointStats{TotalReqs: 0}, *chosen)
+}
+
+func TestExplanationRandom(t *testing.T) {
+	cluster := t.Name()
+	fallback := prom.LBFallbackRandomTotal.WithLabelValues(cluster)
+	mockEndpointStats(t, nil, errors.New("no load"))
+	lb := InferenceLoadBalancerFactory(context.Background(), []types.Host{host.BuildHost(cluster, "10.0.0.1", 8000, 1)})
+
+	tests := []struct {
+		loadAware bool
+		strategy  string
+		fallback  float64
+	}{
+		{loadAware: false, strategy: StrategyRandom},
+		// the load metrics are unavailable
+		{loadAware: true, strategy: StrategyFallbackRandom, fallback: 1},
+	}
+	for _, tt := range tests {
+		before := testutil.ToFloat64(fallback)
+		explanation := &Explanation{}
+		ctx := context.WithValue(lbContext(cluster, tt.loadAware), KeyExplain, explanation)
+		assert.Equal(t, "10.0.0.1:8000", lb.ChooseHost(ctx).Address())
+		assert.JSONEq(t, `{
+			"cluster": "TestExplanationRandom",
+			"strategy": "`+tt.strategy+`",
+			"hosts": 1,
+			"cand_num": 1,
+			"chosen": "10.0.0.1:8000"
+		}`, explanation.String())
+		assert.Equal(t, tt.fallback, testutil.ToFloat64(fallback)-before, tt.strategy)
+	}
+}
@@ -20,21 +20,24 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
-	"os"
 	"slices"
-	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
 	filtermanager "mosn.io/htnn/api/pkg/filtermanager/api"
 
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/hashring"
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/manager"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/outlier"
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
 	pkgcommon "github.com/aigw-project/aigw/pkg/common"
 	"github.com/aigw-project/aigw/pkg/metadata_center"
 	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
 	"github.com/aigw-project/aigw/pkg/request"
+	"github.com/aigw-project/aigw/pkg/runtimeconfig"
 )
 
 const (
@@ -54,6 +57,13 @@ const (
 	KeyPromptHash     pkgcommon.LBCtxKey = "lb.promptHash"
 	KeyHostMatchInfo  pkgcommon.LBCtxKey = "lb.hostMatchInfo"
 	KeyLbSelector     pkgcommon.LBCtxKey = "lb.selector"
+	// KeyChosenStats is a *mctypes.EndpointStats filled with the load of the chosen host, when it's ranked by load
+	KeyChosenStats pkgcommon.LBCtxKey = "lb.chosenStats"
+	// KeyHashKey is the string hashed onto the ring of the hosts, e.g. the session of the request
+	KeyHashKey pkgcommon.LBCtxKey = "lb.hashKey"
+	// KeyAffinityLoadFactor is a float64 which enables the session affinity on KeyHashKey: the host of the key is kept
+	// unless its load is more than (1 + factor) times the average of the candidates
+	KeyAffinityLoadFactor pkgcommon.LBCtxKey = "lb.affinityLoadFactor"
 
 	KeyLoadAwareEnable   pkgcommon.LBCtxKey = "lb.load_aware_enable"
 	KeyCacheAwareEnable  pkgcommon.LBCtxKey = "lb.cache_aware_enable"
@@ -74,23 +84,32 @@ const (
 )
 
 type inferenceLoadBalancer struct {
+	// replaced by UpdateHosts when the cluster is updated
+	hosts atomic.Pointer[lbHosts]
+}
+
+type lbHosts struct {
 	hosts []types.Host
+	// the ring is only built for the session affinity
+	ring func() *hashring.Ring
 }
 
 func InferenceLoadBalancerFactory(context context.Context, hosts []types.Host) types.LoadBalancer {
-	return &inferenceLoadBalancer{
+	lb := &inferenceLoadBalancer{}
+	lb.UpdateHosts(hosts)
+	return lb
+}
+
+func (lb *inferenceLoadBalancer) UpdateHosts(hosts []types.Host) {
+	lb.hosts.Store(&lbHosts{
 		hosts: hosts,
-	}
+		ring:  sync.OnceValue(func() *hashring.Ring { return hashring.New(hosts) }),
+	})
 }
 
 func (lb *inferenceLoadBalancer) ChooseHost(ctx context.Context) types.Host {
-	candidateHosts := lb.hosts
-	selector := pkgcommon.GetValueFromCtx(ctx, KeyLbSelector, map[string]string{})
-	if len(selector) > 0 {
-		candidateHosts = filterHostsBySelector(lb.hosts, selector)
-		api.LogDebugf("filter hosts by selector: %v", candidateHosts)
-	}
-
+	state := lb.hosts.Load()
+	candidateHosts := FilterHosts(ctx, state.hosts)
 	if len(candidateHosts) == 0 {
 		return nil
 	}
@@ -99,31 +118,97 @@ func (lb *inferenceLoadBalancer) ChooseHost(ctx context.Context) types.Host {
 	traceId := pkgcommon.GetValueFromCtx(ctx, KeyTraceId, "")
 	ctx = context.WithValue(ctx, metadata_center.MetaCenterTraceId, traceId)
 
+	d := &decision{
+		cluster:  clusterName,
+		strategy: StrategyRandom,
+		hosts:    len(candidateHosts),
+		candNum:  len(candidateHosts),
+	}
 	// only use random when cluster's load-aware is set to false, default is true when not set
 	hosts := candidateHosts
 	if isModelLoadAwareEnable(ctx) {
 		candNum := candidateNumFromContext(ctx, candidateHosts)
-		hosts = lb.GetCandidateByStats(ctx, clusterName, candidateHosts, candNum)
+		ranked, err := lb.rankHosts(ctx, clusterName, candidateHosts)
+		if err != nil {
+			d.strategy = StrategyFallbackRandom
+		} else {
+			d.strategy = StrategyScore
+			d.ranked = ranked
+			d.candNum = candNum
+			hosts = topHosts(ranked, candNum)
+		}
+	}
+
+	if host := chooseAffinityHost(ctx, state, candidateHosts, d.ranked); host != nil {
+		api.LogDebugf("choose affinity address %s for cluster [%s], traceID: %s", host.Address(), clusterName, traceId)
+		d.strategy = StrategyAffinity
+		d.record(ctx, host)
+		return host
 	}
 
-	return chooseHosts(hosts, clusterName, traceId)
+	var slowStart *common.SlowStart
+	if d.strategy != StrategyScore {
+		// the ranked hosts are ramped up by the score already
+		slowStart = clusterSlowStart(clusterName)
+	}
+	host := chooseHosts(hosts, clusterName, traceId, slowStart)
+	d.record(ctx, host)
+	return host
 }
 
-var (
-	inferLbCandidatePercent     = 5
-	inferLbCandidatePercentOnce sync.Once
-)
+// chooseAffinityHost returns the host of the session on the ring, with the bounded load: the hosts whose load reaches
+// the bound are skipped, so the session stays on its host unless the host is overloaded. The load is the queued
+// requests in the metadata center when the hosts are ranked, or the local requests in flight.
+// It returns nil when the session affinity is disabled.
+func chooseAffinityHost(ctx context.Context, state *lbHosts, candidates []types.Host, ranked []*EndpointStatsWrapper) types.Host {
+	key := pkgcommon.GetValueFromCtx(ctx, KeyHashKey, "")
+	factor, ok := ctx.Value(KeyAffinityLoadFactor).(float64)
+	if key == "" || !ok {
+		return nil
+	}
 
-func getCandidatePercent() int {
-	inferLbCandidatePercentOnce.Do(func() {
-		env := os.Getenv("HTNN_AIGW_INFER_LB_CANDIDATE_PERCENT")
-		if env != "" {
-			if d, err := strconv.Atoi(env); err == nil {
-				inferLbCandidatePercent = d
+	loads := make(map[string]float64, len(candidates))
+	if len(ranked) > 0 {
+		for _, stat := range ranked {
+			if stat.EndpointStats != nil {
+				loads[stat.Host.Address()] = float64(stat.EndpointStats.TotalReqs)
+			} else {
+				loads[stat.Host.Address()] = 0
 			}
 		}
+	} else {
+		for _, h := range candidates {
+			var load int64
+			if t, ok := h.(types.RequestTracker); ok {
+				load = t.Inflight()
+			}
+			loads[h.Address()] = float64(load)
+		}
+	}
+	var total float64
+	for _, load := range loads {
+		total += load
+	}
+	bound := hashring.BoundedLoad(total, len(loads), factor)
+	return state.ring().Find(key, func(h types.Host) bool {
+		load, ok := loads[h.Address()]
+		return ok && load+1 <= bound
 	})
-	return inferLbCandidatePercent
+}
+
+// FilterHosts returns the candidates matching the selector in the context, without the ejected hosts.
+// It's shared by the load balancers, so the subsets work with any of them.
+func FilterHosts(ctx context.Context, hosts []types.Host) []types.Host {
+	selector := pkgcommon.GetValueFromCtx(ctx, KeyLbSelector, map[string]string{})
+	if len(selector) > 0 {
+		hosts = filterHostsBySelector(hosts, selector)
+		api.LogDebugf("filter hosts by selector: %v", hosts)
+	}
+	if len(hosts) == 0 {
+		return nil
+	}
+	clusterName := pkgcommon.MustGetValueFromCtx[string](ctx, KeyClusterName)
+	return outlier.GetDetector().FilterEjected(clusterName, hosts)
 }
 
 func setLogField(ctx context.Context, k string, v interface{}) {
@@ -136,7 +221,7 @@ func setLogField(ctx context.Context, k string, v interface{}) {
 }
 
 func candidateNumFromContext(ctx context.Context, hosts []types.Host) int {
-	percent := pkgcommon.GetValueFromCtx(ctx, KeyCandidatePercent, getCandidatePercent())
+	percent := pkgcommon.GetValueFromCtx(ctx, KeyCandidatePercent, runtimeconfig.Get().CandidatePercent)
 	api.LogDebugf("percent: %d, hosts: %d", percent, len(hosts))
 	// at least 1, at most all
 	return min(max(1, len(hosts)*percent/100), len(hosts))
@@ -146,19 +231,36 @@ type HostMatchInfo struct {
 	CacheRatio float64 `json:"cache_ratio"`
 }
 
-func setHostMatchInfo(ctx context.Context, stat *EndpointStatsWrapper) context.Context {
-	if stat.CacheStats != nil {
-		hostInfo := &HostMatchInfo{
-			CacheRatio: stat.CacheStats.CacheHitScore,
+// selectHosts chooses a host randomly, the new hosts are weighted by the slow start if it's enabled
+func selectHosts(hosts []types.Host, slowStart *common.SlowStart) (int, types.Host) {
+	if slowStart != nil {
+		now := time.Now()
+		weights := make([]float64, len(hosts))
+		var total float64
+		for i, h := range hosts {
+			weights[i] = 1
+			if age, ok := h.(types.HostAge); ok {
+				weights[i] = slowStart.Weight(now.Sub(age.AddedAt()))
+			}
+			total += weights[i]
+		}
+		// all the hosts are just added with zero weight, choose them evenly
+		if total > 0 {
+			r := rand.Float64() * total
+			for i, w := range weights {
+				r -= w
+				if r < 0 {
+					return i, hosts[i]
+				}
+			}
+			// the rounding error
+			for i := len(weights) - 1; i >= 0; i-- {
+				if weights[i] > 0 {
+					return i, hosts[i]
+				}
+			}
 		}
-		ctx = context.WithValue(ctx, KeyHostMatchInfo, hostInfo)
 	}
-	return ctx
-}
-
-// TODO: weighted by score
-func selectHosts(hosts []types.Host) (int, types.Host) {
-	// random for now
 	i := rand.Intn(len(hosts))
 	addr := hosts[i]
 	return i, addr
@@ -182,9 +284,9 @@ func filterHostsBySelector(hosts []types.Host, selector map[string]string) []typ
 	return matchedHosts
 }
 
-func chooseHosts(candidateHosts []types.Host, clusterName, traceId string) types.Host {
-	i, addr := selectHosts(candidateHosts)
-	api.LogInfof("choose %d th address %+v for cluster [%s], traceID: %s", i, addr.Address(), clusterName, traceId)
+func chooseHosts(candidateHosts []types.Host, clusterName, traceId string, slowStart *common.SlowStart) types.Host {
+	i, addr := selectHosts(candidateHosts, slowStart)
+	api.LogDebugf("choose %d th address %+v for cluster [%s], traceID: %s", i, addr.Address(), clusterName, traceId)
 	return candidateHosts[i]
 }
 
@@ -234,6 +336,7 @@ func getClusterMetric(ctx context.Context, cluster string) (map[string]*mctypes.
 				return nil, fmt.Errorf("get load metrics form metacenter error, fallback to use random. model name: %s, backend: %s, err: %+v", modelName, backend, err)
 			} else {
 				api.LogDebugf("get load metrics from metacenter, model name: %s, backend: %s, stats: %v", modelName, backend, stats)
+				storeClusterLoad(clusterName, stats)
 				setLogField(ctx, KeyUseMetaLoad, 1)
 				duration := time.Since(start)
 				if duration > 10*time.Millisecond {
@@ -355,6 +458,11 @@ func mergeEndpointsStatsWrapperCacheStats(ctx context.Context, load []*EndpointS
 
 	api.LogDebugf("cacheHitWeight: %f, requestLoadWeight: %f, prefillRadioWeight: %f", cacheHitWeight, requestLoadWeight, prefillRadioWeight)
 
+	// a cold host scores as low as a host without cache hit under the max load, which is the range of the score
+	slowStart := clusterSlowStart(pkgcommon.GetValueFromCtx(ctx, KeyClusterName, ""))
+	warmupPenalty := cacheHitWeight + requestLoadWeight + prefillRadioWeight
+	now := time.Now()
+
 	res := make([]*EndpointStatsWrapper, len(load))
 	for i, stat := range load {
 		stat.CacheHitRate = 0
@@ -377,18 +485,43 @@ func mergeEndpointsStatsWrapperCacheStats(ctx context.Context, load []*EndpointS
 
 		// score = W1 * cache_ratio - W2 * request_load - W3 * prefill_load
 		stat.Score = cacheHitWeight*stat.CacheHitRate - requestLoadWeight*stat.RequestLoad - prefillRadioWeight*stat.PrefillLoad
+
+		// the new hosts are ramped up by the slow start weight, since their KV cache and CUDA graphs are cold
+		stat.Warmup = 1
+		if h, ok := stat.Host.(types.HostAge); ok && slowStart != nil {
+			stat.Warmup = slowStart.Weight(now.Sub(h.AddedAt()))
+			stat.Score -= warmupPenalty * (1 - stat.Warmup)
+		}
 		res[i] = stat
 	}
 	return res
 }
 
+// clusterSlowStart returns the slow start in the template of the cluster, nil when it's disabled
+func clusterSlowStart(cluster string) *common.SlowStart {
+	tmpl := common.GetClusterTemplate(cluster)
+	if tmpl == nil {
+		return nil
+	}
+	return tmpl.SlowStart
+}
+
 // GetCandidateByStats get sorted hosts by metrics.
 func (lb *inferenceLoadBalancer) GetCandidateByStats(ctx context.Context, clusterName string, hosts []types.Host, candNum int) []types.Host {
+	ranked, err := lb.rankHosts(ctx, clusterName, hosts)
+	if err != nil {
+		return hosts
+	}
+	return topHosts(ranked, candNum)
+}
+
+// rankHosts sorts the hosts by score in desc order, returns error when the load metrics are not available
+func (lb *inferenceLoadBalancer) rankHosts(ctx context.Context, clusterName string, hosts []types.Host) ([]*EndpointStatsWrapper, error) {
 	// EndpointStats in EndpointStatsWrapper: won't be empty
 	stats, err := getEndpointStatsByClusterName(ctx, clusterName, hosts)
 	if err != nil {
 		api.LogErrorf("failed to get endpoint stats by cluster name:%s, err: %+v", clusterName, err)
-		return hosts
+		return nil, err
 	}
 
 	caches, err := getEndpointCacheStats(ctx)
@@ -404,23 +537,19 @@ func (lb *inferenceLoadBalancer) GetCandidateByStats(ctx context.Context, cluste
 	stats = mergeEndpointsStatsWrapperCacheStats(ctx, stats, caches)
 	slices.SortFunc(stats, CompareEndpointStatsWrapperWithCache)
 
-	if api.GetLogLevel() <= api.Info {
+	if api.GetLogLevel() <= api.Debug {
 		traceId := pkgcommon.GetValueFromCtx(ctx, KeyTraceId, "")
-		for i, stat := range stats {
-			// use for debugging
-			if i < candNum+5 {
-				api.LogInfof("the %d candidate for cluster [%s] are %s, traceID: %s", i, clusterName, stat, traceId)
-			} else {
-				api.LogDebugf("the %d candidate for cluster [%s] are %s, traceID: %s", i, clusterName, stat, traceId)
-			}
+		for i, stat := range stats[:min(len(stats), maxExplainCandidates)] {
+			api.LogDebugf("the %d candidate for cluster [%s] are %s, traceID: %s", i, clusterName, stat, traceId)
 		}
 	}
+	return stats, nil
+}
 
-	res := make([]types.Host, 0, len(hosts))
-	for i := 0; i < candNum; i++ {
-		ctx = setHostMatchInfo(ctx, stats[i])
-		res = append(res, stats[i].Host)
+func topHosts(ranked []*EndpointStatsWrapper, candNum int) []types.Host {
+	res := make([]types.Host, 0, candNum)
+	for _, stat := range ranked[:candNum] {
+		res = append(res, stat.Host)
 	}
-
 	return res
 }
@@ -0,0 +1,144 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inferencelb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/host"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
+)
+
+// agedHost is a host added at the given time
+type agedHost struct {
+	types.Host
+	addedAt time.Time
+}
+
+func (h *agedHost) AddedAt() time.Time {
+	return h.addedAt
+}
+
+func newAgedHost(ip string, age time.Duration) types.Host {
+	return &agedHost{
+		Host:    host.BuildHost("test", ip, 8000, 1),
+		addedAt: time.Now().Add(-age),
+	}
+}
+
+// mockEndpointStats replaces the load in the metadata center with the queued requests by ip,
+// or the error if it's not nil
+func mockEndpointStats(t *testing.T, queued map[string]int, err error) {
+	orig := getEndpointStatsByClusterName
+	t.Cleanup(func() { getEndpointStatsByClusterName = orig })
+	getEndpointStatsByClusterName = func(ctx context.Context, clusterName string, hosts []types.Host) ([]*EndpointStatsWrapper, error) {
+		if err != nil {
+			return nil, err
+		}
+		res := make([]*EndpointStatsWrapper, 0, len(hosts))
+		for _, h := range hosts {
+			res = append(res, &EndpointStatsWrapper{
+				Host:          h,
+				EndpointStats: &mctypes.EndpointStats{TotalReqs: queued[h.Ip()]},
+			})
+		}
+		return res, nil
+	}
+}
+
+func setSlowStart(t *testing.T, cluster string, slowStart *common.SlowStart) {
+	assert.NoError(t, common.UpdateClusterTemplates(map[string]*common.ClusterTemplate{cluster: {SlowStart: slowStart}}))
+	t.Cleanup(func() {
+		assert.NoError(t, common.UpdateClusterTemplates(map[string]*common.ClusterTemplate{cluster: nil}))
+	})
+}
+
+func lbContext(cluster string, loadAware bool) context.Context {
+	ctx := context.WithValue(context.Background(), KeyClusterName, cluster)
+	ctx = context.WithValue(ctx, KeyLoadAwareEnable, loadAware)
+	ctx = context.WithValue(ctx, KeyCacheAwareEnable, false)
+	// only the top host is the candidate
+	return context.WithValue(ctx, KeyCandidatePercent, 1)
+}
+
+func countChoices(lb types.LoadBalancer, ctx context.Context, n int) map[string]int {
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		counts[lb.ChooseHost(ctx).Ip()]++
+	}
+	return counts
+}
+
+func TestSlowStartScore(t *testing.T) {
+	cluster := t.Name()
+	setSlowStart(t, cluster, &common.SlowStart{Window: time.Hour, MinWeightPercent: 10})
+	// the new host has less load
+	mockEndpointStats(t, map[string]int{"10.0.0.1": 4, "10.0.0.2": 0}, nil)
+	ctx := lbContext(cluster, true)
+
+	warm := newAgedHost("10.0.0.1", 2*time.Hour)
+	lb := InferenceLoadBalancerFactory(context.Background(), []types.Host{warm, newAgedHost("10.0.0.2", time.Minute)})
+	counts := countChoices(lb, ctx, 20)
+	assert.Equal(t, map[string]int{"10.0.0.1": 20}, counts)
+
+	ranked, err := lb.(*inferenceLoadBalancer).rankHosts(ctx, cluster, lb.(*inferenceLoadBalancer).hosts.Load().hosts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, ranked[0].Warmup)
+	assert.InDelta(t, 0.1, ranked[1].Warmup, 0.01)
+
+	// the window ends
+	lb.(types.HostUpdater).UpdateHosts([]types.Host{warm, newAgedHost("10.0.0.2", time.Hour)})
+	counts = countChoices(lb, ctx, 20)
+	assert.Equal(t, map[string]int{"10.0.0.2": 20}, counts)
+
+	// slow start disabled
+	setSlowStart(t, cluster, nil)
+	lb.(types.HostUpdater).UpdateHosts([]types.Host{warm, newAgedHost("10.0.0.2", 0)})
+	counts = countChoices(lb, ctx, 20)
+	assert.Equal(t, map[string]int{"10.0.0.2": 20}, counts)
+}
+
+func TestSlowStartRandom(t *testing.T) {
+	for _, fallback := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fallback %v", fallback), func(t *testing.T) {
+			cluster := t.Name()
+			setSlowStart(t, cluster, &common.SlowStart{Window: time.Hour, MinWeightPercent: 10})
+			// no load in the metadata center
+			mockEndpointStats(t, nil, errors.New("no load"))
+			ctx := lbContext(cluster, fallback)
+
+			warm := newAgedHost("10.0.0.1", 2*time.Hour)
+			lb := InferenceLoadBalancerFactory(context.Background(), []types.Host{warm, newAgedHost("10.0.0.2", 0)})
+			// the new host is chosen with weight 0.1, about 91 of 1000
+			counts := countChoices(lb, ctx, 1000)
+			assert.Greater(t, counts["10.0.0.2"], 0)
+			assert.Less(t, counts["10.0.0.2"], 200)
+
+			// the window ends
+			lb.(types.HostUpdater).UpdateHosts([]types.Host{warm, newAgedHost("10.0.0.2", time.Hour)})
+			counts = countChoices(lb, ctx, 1000)
+			assert.Greater(t, counts["10.0.0.2"], 350)
+		})
+	}
+}
@@ -42,11 +42,13 @@ type EndpointStatsWrapper struct {
 	RequestLoad  float64
 	PrefillLoad  float64
 	CacheHitRate float64
-	Score        float64
+	// Warmup is the slow start weight in [0, 1], 1 for the warm hosts
+	Warmup float64
+	Score  float64
 }
 
 func (e *EndpointStatsWrapper) String() string {
 	host := fmt.Sprintf(`{"ip":"%s","port":%d}`, e.Host.Ip(), e.Host.Port())
-	load := fmt.Sprintf(`{"cache_radio":%f, "request_load":%f, "prefill_load":%f, "score":%f}`, e.CacheHitRate, e.RequestLoad, e.PrefillLoad, e.Score)
+	load := fmt.Sprintf(`{"cache_radio":%f, "request_load":%f, "prefill_load":%f, "warmup":%f, "score":%f}`, e.CacheHitRate, e.RequestLoad, e.PrefillLoad, e.Warmup, e.Score)
 	return fmt.Sprintf("EndpointStatsWrapper{Host: %s, EndpointStats: %s, Stats: %s}", host, e.EndpointStats, load)
 }
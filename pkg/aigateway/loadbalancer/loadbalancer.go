@@ -19,13 +19,20 @@ import (
 
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 
+	// register the basic load balancers
+	_ "github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/basiclb"
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
 )
 
-func ChooseServer(ctx context.Context, callbacks api.FilterCallbackHandler, header api.HeaderMap, cluster string, lbType types.LoadBalancerType) (types.Host, error) {
+// ChooseHost chooses a host from the cluster, without routing the current request to it
+func ChooseHost(ctx context.Context, cluster string, lbType types.LoadBalancerType) (types.Host, error) {
 	ctx = context.WithValue(ctx, inferencelb.KeyClusterName, cluster)
-	host, err := globalLoadBalancer.ChooseHost(ctx, cluster, lbType)
+	return globalLoadBalancer.ChooseHost(ctx, cluster, lbType)
+}
+
+func ChooseServer(ctx context.Context, callbacks api.FilterCallbackHandler, header api.HeaderMap, cluster string, lbType types.LoadBalancerType) (types.Host, error) {
+	host, err := ChooseHost(ctx, cluster, lbType)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,141 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package locality orders the clusters serving a model, so the gateway prefers the clusters with the higher
+// priority and then the ones close to it, and spills over to the others when they are saturated.
+package locality
+
+import (
+	"cmp"
+	"os"
+	"slices"
+	"sync"
+)
+
+const (
+	// the region and zone of the gateway, the clusters are not ordered by locality when they are not set
+	EnvRegion = "AIGW_REGION"
+	EnvZone   = "AIGW_ZONE"
+)
+
+type Locality struct {
+	Region string
+	Zone   string
+}
+
+var local = sync.OnceValue(func() Locality {
+	return Locality{Region: os.Getenv(EnvRegion), Zone: os.Getenv(EnvZone)}
+})
+
+// Local returns the locality of the gateway
+func Local() Locality {
+	return local()
+}
+
+const (
+	sameZone = iota
+	sameRegion
+	remote
+)
+
+// distance from l to the other locality, the zones are only compared in the same region
+func (l Locality) distance(other Locality) int {
+	if l.Region == "" || l.Region != other.Region {
+		return remote
+	}
+	if l.Zone != "" && l.Zone == other.Zone {
+		return sameZone
+	}
+	return sameRegion
+}
+
+// Cluster is a cluster serving the model
+type Cluster struct {
+	Name string
+	// the lower is preferred
+	Priority uint32
+	Locality Locality
+	// the cluster is saturated when its queued requests per host reach MaxRequestsPerHost, 0 means never
+	MaxRequestsPerHost uint32
+}
+
+// Order sorts the clusters by priority, and then by the distance to the local locality.
+// The clusters with the same priority and distance keep their configured order.
+func Order(clusters []Cluster, local Locality) []Cluster {
+	ordered := slices.Clone(clusters)
+	slices.SortStableFunc(ordered, func(a, b Cluster) int {
+		return cmp.Or(
+			cmp.Compare(a.Priority, b.Priority),
+			cmp.Compare(local.distance(a.Locality), local.distance(b.Locality)),
+		)
+	})
+	return ordered
+}
+
+// Load is the queued requests of a cluster
+type Load struct {
+	Hosts     int
+	TotalReqs int
+}
+
+func (l Load) perHost() float64 {
+	return float64(l.TotalReqs) / float64(max(l.Hosts, 1))
+}
+
+// LoadFunc returns the recent load of the cluster, false when it's unknown
+type LoadFunc func(cluster string) (Load, bool)
+
+// Saturated returns whether the cluster is saturated, the cluster without recent load is not saturated
+func (c Cluster) Saturated(load LoadFunc) bool {
+	if c.MaxRequestsPerHost == 0 {
+		return false
+	}
+	l, ok := load(c.Name)
+	if !ok || l.Hosts == 0 {
+		return false
+	}
+	return l.TotalReqs >= l.Hosts*int(c.MaxRequestsPerHost)
+}
+
+// Spillover returns the ordered clusters in the order to try: the clusters which are not saturated keep their order,
+// and the saturated ones follow, the less loaded first. So the requests spill over to the next clusters when the
+// preferred ones are saturated, and go to the least saturated one when all of them are saturated.
+func Spillover(ordered []Cluster, load LoadFunc) []Cluster {
+	res := make([]Cluster, 0, len(ordered))
+	var saturated []Cluster
+	loads := map[string]float64{}
+	for _, c := range ordered {
+		if c.Saturated(load) {
+			l, _ := load(c.Name)
+			loads[c.Name] = l.perHost() / float64(c.MaxRequestsPerHost)
+			saturated = append(saturated, c)
+			continue
+		}
+		res = append(res, c)
+	}
+	slices.SortStableFunc(saturated, func(a, b Cluster) int {
+		return cmp.Compare(loads[a.Name], loads[b.Name])
+	})
+	return append(res, saturated...)
+}
+
+// AllSaturated returns whether all the clusters are saturated
+func AllSaturated(clusters []Cluster, load LoadFunc) bool {
+	for _, c := range clusters {
+		if !c.Saturated(load) {
+			return false
+		}
+	}
+	return len(clusters) > 0
+}
@@ -0,0 +1,79 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locality
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func names(clusters []Cluster) []string {
+	res := make([]string, 0, len(clusters))
+	for _, c := range clusters {
+		res = append(res, c.Name)
+	}
+	return res
+}
+
+func TestOrder(t *testing.T) {
+	clusters := []Cluster{
+		{Name: "remote", Locality: Locality{Region: "us", Zone: "us-1"}},
+		{Name: "region", Locality: Locality{Region: "cn", Zone: "cn-2"}},
+		{Name: "zone", Locality: Locality{Region: "cn", Zone: "cn-1"}},
+		{Name: "backup", Priority: 1, Locality: Locality{Region: "cn", Zone: "cn-1"}},
+		{Name: "unknown"},
+	}
+	assert.Equal(t, []string{"zone", "region", "remote", "unknown", "backup"},
+		names(Order(clusters, Locality{Region: "cn", Zone: "cn-1"})))
+	// the same zone name in another region is remote
+	assert.Equal(t, []string{"remote", "unknown", "backup"},
+		names(Order([]Cluster{clusters[0], clusters[4], clusters[3]}, Locality{Region: "eu", Zone: "us-1"})))
+	// the configured order without the local locality
+	assert.Equal(t, []string{"remote", "region", "zone", "unknown", "backup"}, names(Order(clusters, Locality{})))
+}
+
+func TestSpillover(t *testing.T) {
+	loads := map[string]Load{
+		"a": {Hosts: 2, TotalReqs: 20},
+		"b": {Hosts: 4, TotalReqs: 10},
+		"c": {Hosts: 1, TotalReqs: 30},
+		"d": {Hosts: 1, TotalReqs: 100},
+	}
+	load := func(cluster string) (Load, bool) {
+		l, ok := loads[cluster]
+		return l, ok
+	}
+	clusters := []Cluster{
+		{Name: "a", MaxRequestsPerHost: 10},
+		{Name: "b", MaxRequestsPerHost: 10},
+		{Name: "c", MaxRequestsPerHost: 10},
+		{Name: "unknown", MaxRequestsPerHost: 10},
+		{Name: "d"},
+	}
+	assert.True(t, clusters[0].Saturated(load))
+	assert.False(t, clusters[1].Saturated(load))
+	assert.False(t, clusters[3].Saturated(load))
+	assert.False(t, clusters[4].Saturated(load))
+
+	assert.Equal(t, []string{"b", "unknown", "d", "a", "c"}, names(Spillover(clusters, load)))
+	assert.False(t, AllSaturated(clusters, load))
+
+	// all saturated, the less loaded first
+	all := []Cluster{clusters[2], clusters[0]}
+	assert.True(t, AllSaturated(all, load))
+	assert.Equal(t, []string{"a", "c"}, names(Spillover(all, load)))
+	assert.False(t, AllSaturated(nil, load))
+}
@@ -0,0 +1,38 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package outlier
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const DebugPath = "/debug/outlier"
+
+// DebugHandler dumps the outlier status of the hosts, filtered by the optional "cluster" query parameter
+func DebugHandler(d *Detector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cluster := r.URL.Query().Get("cluster")
+		res := []HostStatus{}
+		for _, s := range d.Status() {
+			if cluster == "" || s.Cluster == cluster {
+				res = append(res, s)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(res)
+	})
+}
@@ -0,0 +1,401 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package outlier implements passive outlier detection for inference hosts.
+// The detector is fed by the outcome of the requests, and ejects the hosts which keep failing,
+// or whose TTFT is much higher than the other hosts in the same cluster.
+package outlier
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+	"github.com/aigw-project/aigw/pkg/common"
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+type ErrorKind string
+
+const (
+	// ErrorKindStatus is the 5xx response from the inference server
+	ErrorKindStatus ErrorKind = "status"
+	// ErrorKindFirstChunk is the error message in the first chunk of the streaming response
+	ErrorKindFirstChunk ErrorKind = "first_chunk"
+	// ErrorKindTimeout is the timeout or reset before the response is received
+	ErrorKindTimeout ErrorKind = "timeout"
+	// ErrorKindRetried is the failed attempt retried by the Envoy router, the reason of the failure is unknown
+	ErrorKindRetried ErrorKind = "retried"
+)
+
+const (
+	ReasonConsecutiveErrors = "consecutive_errors"
+	ReasonErrorRate         = "error_rate"
+	ReasonTTFT              = "ttft"
+)
+
+// the environment variables of the default config
+const (
+	AIGW_OUTLIER_CONSECUTIVE_ERRORS      = "AIGW_OUTLIER_CONSECUTIVE_ERRORS"
+	AIGW_OUTLIER_ERROR_RATE_WINDOW       = "AIGW_OUTLIER_ERROR_RATE_WINDOW"
+	AIGW_OUTLIER_ERROR_RATE_MIN_REQUESTS = "AIGW_OUTLIER_ERROR_RATE_MIN_REQUESTS"
+	AIGW_OUTLIER_ERROR_RATE_PERCENT      = "AIGW_OUTLIER_ERROR_RATE_PERCENT"
+	AIGW_OUTLIER_TTFT_FACTOR             = "AIGW_OUTLIER_TTFT_FACTOR"
+	AIGW_OUTLIER_TTFT_MIN_SAMPLES        = "AIGW_OUTLIER_TTFT_MIN_SAMPLES"
+	AIGW_OUTLIER_BASE_EJECTION_TIME      = "AIGW_OUTLIER_BASE_EJECTION_TIME"
+	AIGW_OUTLIER_MAX_EJECTION_TIME       = "AIGW_OUTLIER_MAX_EJECTION_TIME"
+	AIGW_OUTLIER_MAX_EJECTION_PERCENT    = "AIGW_OUTLIER_MAX_EJECTION_PERCENT"
+)
+
+// ttft moving average weight of the latest sample
+const ttftEwmaAlpha = 0.2
+
+type Config struct {
+	// eject the host when it returns ConsecutiveErrors errors in a row, 0 means disabled
+	ConsecutiveErrors int
+	// the window of error rate detection
+	ErrorRateWindow time.Duration
+	// the minimum requests in the window to detect error rate
+	ErrorRateMinRequests int
+	// eject the host when the error rate reaches ErrorRatePercent, 0 means disabled
+	ErrorRatePercent int
+	// eject the host when its TTFT is TTFTOutlierFactor times of the average of the other hosts, 0 means disabled
+	TTFTOutlierFactor int
+	// the minimum TTFT samples of the host to take part in TTFT detection
+	TTFTMinSamples int
+	// the ejection time is BaseEjectionTime * 2^(ejections-1), but no more than MaxEjectionTime
+	BaseEjectionTime time.Duration
+	MaxEjectionTime  time.Duration
+	// at most MaxEjectionPercent of the hosts in a cluster could be ejected
+	MaxEjectionPercent int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		ConsecutiveErrors:    common.GetIntFromEnv(AIGW_OUTLIER_CONSECUTIVE_ERRORS, 5),
+		ErrorRateWindow:      common.GetDurationFromEnv(AIGW_OUTLIER_ERROR_RATE_WINDOW, 30*time.Second),
+		ErrorRateMinRequests: common.GetIntFromEnv(AIGW_OUTLIER_ERROR_RATE_MIN_REQUESTS, 20),
+		ErrorRatePercent:     common.GetIntFromEnv(AIGW_OUTLIER_ERROR_RATE_PERCENT, 50),
+		TTFTOutlierFactor:    common.GetIntFromEnv(AIGW_OUTLIER_TTFT_FACTOR, 5),
+		TTFTMinSamples:       common.GetIntFromEnv(AIGW_OUTLIER_TTFT_MIN_SAMPLES, 10),
+		BaseEjectionTime:     common.GetDurationFromEnv(AIGW_OUTLIER_BASE_EJECTION_TIME, 30*time.Second),
+		MaxEjectionTime:      common.GetDurationFromEnv(AIGW_OUTLIER_MAX_EJECTION_TIME, 300*time.Second),
+		MaxEjectionPercent:   common.GetIntFromEnv(AIGW_OUTLIER_MAX_EJECTION_PERCENT, 50),
+	}
+}
+
+type hostState struct {
+	consecutiveErrors int
+
+	windowStart    time.Time
+	windowRequests int
+	windowErrors   int
+	// whether the host is ejected in the current window
+	windowEjected bool
+
+	ttftEwma    float64
+	ttftSamples int
+
+	ejectedUntil time.Time
+	// ejections decides the ejection time, decreased when the host is healthy for a whole window
+	ejections  int
+	lastReason string
+}
+
+func (h *hostState) isEjected(now time.Time) bool {
+	return now.Before(h.ejectedUntil)
+}
+
+// HostStatus is the ejection status of a host, used in the debug endpoint
+type HostStatus struct {
+	Cluster           string     `json:"cluster"`
+	Address           string     `json:"address"`
+	Ejected           bool       `json:"ejected"`
+	EjectedUntil      *time.Time `json:"ejected_until,omitempty"`
+	Ejections         int        `json:"ejections"`
+	LastReason        string     `json:"last_reason,omitempty"`
+	ConsecutiveErrors int        `json:"consecutive_errors"`
+	WindowRequests    int        `json:"window_requests"`
+	WindowErrors      int        `json:"window_errors"`
+	TTFTMs            float64    `json:"ttft_ms"`
+}
+
+type Detector struct {
+	config Config
+	now    func() time.Time
+
+	lock     sync.Mutex
+	clusters map[string]map[string]*hostState
+}
+
+func NewDetector(config Config) *Detector {
+	return &Detector{
+		config:   config,
+		now:      time.Now,
+		clusters: make(map[string]map[string]*hostState),
+	}
+}
+
+var (
+	defaultDetector     *Detector
+	defaultDetectorOnce sync.Once
+)
+
+// GetDetector returns the global detector shared by the filters and the load balancers
+func GetDetector() *Detector {
+	defaultDetectorOnce.Do(func() {
+		defaultDetector = NewDetector(DefaultConfig())
+	})
+	return defaultDetector
+}
+
+// getHost must be called with lock held
+func (d *Detector) getHost(cluster, address string, now time.Time) *hostState {
+	hosts, ok := d.clusters[cluster]
+	if !ok {
+		hosts = make(map[string]*hostState)
+		d.clusters[cluster] = hosts
+	}
+	h, ok := hosts[address]
+	if !ok {
+		h = &hostState{windowStart: now}
+		hosts[address] = h
+	}
+	if d.config.ErrorRateWindow > 0 && now.Sub(h.windowStart) >= d.config.ErrorRateWindow {
+		if !h.windowEjected && !h.isEjected(now) && h.ejections > 0 {
+			h.ejections--
+		}
+		h.windowStart = now
+		h.windowRequests = 0
+		h.windowErrors = 0
+		h.windowEjected = false
+	}
+	return h
+}
+
+// RecordSuccess records a successful request of the host
+func (d *Detector) RecordSuccess(cluster, address string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	h := d.getHost(cluster, address, d.now())
+	h.consecutiveErrors = 0
+	h.windowRequests++
+}
+
+// RecordError records a failed request of the host, and ejects it when it's an outlier
+func (d *Detector) RecordError(cluster, address string, kind ErrorKind) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := d.now()
+	h := d.getHost(cluster, address, now)
+	h.consecutiveErrors++
+	h.windowRequests++
+	h.windowErrors++
+	api.LogDebugf("outlier detector records %s error for host %s in cluster %s, consecutive errors: %d",
+		kind, address, cluster, h.consecutiveErrors)
+
+	if h.isEjected(now) {
+		return
+	}
+	if d.config.ConsecutiveErrors > 0 && h.consecutiveErrors >= d.config.ConsecutiveErrors {
+		d.eject(cluster, address, h, ReasonConsecutiveErrors, now)
+		return
+	}
+	if d.config.ErrorRatePercent > 0 && h.windowRequests >= d.config.ErrorRateMinRequests &&
+		h.windowErrors*100 >= h.windowRequests*d.config.ErrorRatePercent {
+		d.eject(cluster, address, h, ReasonErrorRate, now)
+	}
+}
+
+// RecordTTFT records the TTFT of a successful request, and ejects the host when it's much slower than the others
+func (d *Detector) RecordTTFT(cluster, address string, ttft time.Duration) {
+	if ttft <= 0 {
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := d.now()
+	h := d.getHost(cluster, address, now)
+	ms := float64(ttft.Microseconds()) / 1000
+	if h.ttftSamples == 0 {
+		h.ttftEwma = ms
+	} else {
+		h.ttftEwma = ttftEwmaAlpha*ms + (1-ttftEwmaAlpha)*h.ttftEwma
+	}
+	h.ttftSamples++
+
+	if d.config.TTFTOutlierFactor <= 0 || h.ttftSamples < d.config.TTFTMinSamples || h.isEjected(now) {
+		return
+	}
+
+	var sum float64
+	var n int
+	for addr, other := range d.clusters[cluster] {
+		if addr == address || other.ttftSamples < d.config.TTFTMinSamples || other.isEjected(now) {
+			continue
+		}
+		sum += other.ttftEwma
+		n++
+	}
+	// need enough peers to tell the outlier
+	if n < 2 {
+		return
+	}
+	if h.ttftEwma > sum/float64(n)*float64(d.config.TTFTOutlierFactor) {
+		d.eject(cluster, address, h, ReasonTTFT, now)
+		// start over after the ejection, otherwise the host will be ejected again once it's back
+		h.ttftSamples = 0
+	}
+}
+
+// eject must be called with lock held
+func (d *Detector) eject(cluster, address string, h *hostState, reason string, now time.Time) {
+	hosts := d.clusters[cluster]
+	ejected := 0
+	for _, other := range hosts {
+		if other.isEjected(now) {
+			ejected++
+		}
+	}
+	if (ejected+1)*100 > len(hosts)*d.config.MaxEjectionPercent {
+		api.LogWarnf("outlier detector skips ejecting host %s in cluster %s for %s, too many ejected hosts: %d/%d",
+			address, cluster, reason, ejected, len(hosts))
+		return
+	}
+
+	h.ejections++
+	ejectionTime := d.config.BaseEjectionTime << min(h.ejections-1, 16)
+	if ejectionTime > d.config.MaxEjectionTime || ejectionTime <= 0 {
+		ejectionTime = d.config.MaxEjectionTime
+	}
+	h.ejectedUntil = now.Add(ejectionTime)
+	h.windowEjected = true
+	h.consecutiveErrors = 0
+	h.lastReason = reason
+
+	api.LogWarnf("outlier detector ejects host %s in cluster %s for %s, ejection time: %s",
+		address, cluster, reason, ejectionTime)
+	prom.OutlierEjectionsTotal.WithLabelValues(cluster, reason).Inc()
+	prom.OutlierEjectedHosts.WithLabelValues(cluster, address).Set(1)
+}
+
+// IsEjected checks whether the host is ejected
+func (d *Detector) IsEjected(cluster, address string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	h, ok := d.clusters[cluster][address]
+	if !ok {
+		return false
+	}
+	if h.isEjected(d.now()) {
+		return true
+	}
+	d.unejectIfNeeded(cluster, address, h)
+	return false
+}
+
+// unejectIfNeeded resets the metric after the ejection ends, must be called with lock held
+func (d *Detector) unejectIfNeeded(cluster, address string, h *hostState) {
+	if !h.ejectedUntil.IsZero() {
+		h.ejectedUntil = time.Time{}
+		api.LogInfof("outlier detector brings host %s in cluster %s back", address, cluster)
+		prom.OutlierEjectedHosts.WithLabelValues(cluster, address).Set(0)
+	}
+}
+
+// FilterEjected removes the ejected hosts from the candidates.
+// All the hosts are returned when all of them are ejected, since choosing an ejected host is better than nothing.
+func (d *Detector) FilterEjected(cluster string, hosts []types.Host) []types.Host {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	states, ok := d.clusters[cluster]
+	if !ok {
+		return hosts
+	}
+
+	now := d.now()
+	var res []types.Host
+	for _, host := range hosts {
+		h, ok := states[host.Address()]
+		if ok && h.isEjected(now) {
+			continue
+		}
+		if ok {
+			d.unejectIfNeeded(cluster, host.Address(), h)
+		}
+		res = append(res, host)
+	}
+	if len(res) == 0 {
+		api.LogWarnf("all %d hosts in cluster %s are ejected, ignore the ejection", len(hosts), cluster)
+		return hosts
+	}
+	return res
+}
+
+// RemoveHost forgets the host, e.g. when it's removed from the cluster
+func (d *Detector) RemoveHost(cluster, address string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if hosts, ok := d.clusters[cluster]; ok {
+		delete(hosts, address)
+		if len(hosts) == 0 {
+			delete(d.clusters, cluster)
+		}
+	}
+	prom.OutlierEjectedHosts.DeleteLabelValues(cluster, address)
+}
+
+// Status returns the status of all the tracked hosts
+func (d *Detector) Status() []HostStatus {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := d.now()
+	var res []HostStatus
+	for cluster, hosts := range d.clusters {
+		for address, h := range hosts {
+			s := HostStatus{
+				Cluster:           cluster,
+				Address:           address,
+				Ejected:           h.isEjected(now),
+				Ejections:         h.ejections,
+				LastReason:        h.lastReason,
+				ConsecutiveErrors: h.consecutiveErrors,
+				WindowRequests:    h.windowRequests,
+				WindowErrors:      h.windowErrors,
+				TTFTMs:            h.ttftEwma,
+			}
+			if s.Ejected {
+				until := h.ejectedUntil
+				s.EjectedUntil = &until
+			}
+			res = append(res, s)
+		}
+	}
+	slices.SortFunc(res, func(a, b HostStatus) int {
+		return cmp.Or(cmp.Compare(a.Cluster, b.Cluster), cmp.Compare(a.Address, b.Address))
+	})
+	return res
+}
@@ -0,0 +1,178 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package outlier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/host"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+)
+
+func testConfig() Config {
+	return Config{
+		ConsecutiveErrors:    3,
+		ErrorRateWindow:      10 * time.Second,
+		ErrorRateMinRequests: 10,
+		ErrorRatePercent:     50,
+		TTFTOutlierFactor:    3,
+		TTFTMinSamples:       2,
+		BaseEjectionTime:     10 * time.Second,
+		MaxEjectionTime:      30 * time.Second,
+		MaxEjectionPercent:   50,
+	}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestDetector() (*Detector, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	d := NewDetector(testConfig())
+	d.now = clock.Now
+	// register the hosts so that max ejection percent works
+	for i := 0; i < 4; i++ {
+		d.RecordSuccess("c", fmt.Sprintf("10.0.0.%d:80", i))
+	}
+	return d, clock
+}
+
+func TestConsecutiveErrors(t *testing.T) {
+	d, clock := newTestDetector()
+	addr := "10.0.0.0:80"
+
+	d.RecordError("c", addr, ErrorKindStatus)
+	d.RecordError("c", addr, ErrorKindStatus)
+	d.RecordSuccess("c", addr)
+	d.RecordError("c", addr, ErrorKindStatus)
+	d.RecordError("c", addr, ErrorKindStatus)
+	assert.False(t, d.IsEjected("c", addr))
+
+	d.RecordError("c", addr, ErrorKindTimeout)
+	assert.True(t, d.IsEjected("c", addr))
+
+	clock.now = clock.now.Add(11 * time.Second)
+	assert.False(t, d.IsEjected("c", addr))
+
+	// ejected again with a doubled ejection time
+	for i := 0; i < 3; i++ {
+		d.RecordError("c", addr, ErrorKindFirstChunk)
+	}
+	clock.now = clock.now.Add(11 * time.Second)
+	assert.True(t, d.IsEjected("c", addr))
+	clock.now = clock.now.Add(10 * time.Second)
+	assert.False(t, d.IsEjected("c", addr))
+}
+
+func TestErrorRate(t *testing.T) {
+	d, _ := newTestDetector()
+	d.config.ConsecutiveErrors = 0
+	addr := "10.0.0.1:80"
+
+	for i := 0; i < 4; i++ {
+		d.RecordSuccess("c", addr)
+		d.RecordError("c", addr, ErrorKindStatus)
+	}
+	assert.False(t, d.IsEjected("c", addr))
+
+	d.RecordError("c", addr, ErrorKindStatus)
+	assert.True(t, d.IsEjected("c", addr))
+	assert.Equal(t, ReasonErrorRate, d.Status()[1].LastReason)
+}
+
+func TestTTFTOutlier(t *testing.T) {
+	d, _ := newTestDetector()
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			d.RecordTTFT("c", fmt.Sprintf("10.0.0.%d:80", j), 100*time.Millisecond)
+		}
+	}
+	slow := "10.0.0.3:80"
+	d.RecordTTFT("c", slow, 200*time.Millisecond)
+	d.RecordTTFT("c", slow, 200*time.Millisecond)
+	assert.False(t, d.IsEjected("c", slow))
+
+	d.RecordTTFT("c", slow, 5*time.Second)
+	assert.True(t, d.IsEjected("c", slow))
+}
+
+func TestMaxEjectionPercent(t *testing.T) {
+	d, _ := newTestDetector()
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 3; j++ {
+			d.RecordError("c", fmt.Sprintf("10.0.0.%d:80", i), ErrorKindStatus)
+		}
+	}
+
+	ejected := 0
+	for _, s := range d.Status() {
+		if s.Ejected {
+			ejected++
+		}
+	}
+	assert.Equal(t, 2, ejected)
+}
+
+func TestFilterEjected(t *testing.T) {
+	d, _ := newTestDetector()
+	var hosts []types.Host
+	for i := 0; i < 2; i++ {
+		hosts = append(hosts, host.BuildHost("c", fmt.Sprintf("10.0.0.%d", i), 80, 1))
+	}
+
+	assert.Len(t, d.FilterEjected("c", hosts), 2)
+	assert.Len(t, d.FilterEjected("unknown", hosts), 2)
+
+	for j := 0; j < 3; j++ {
+		d.RecordError("c", "10.0.0.0:80", ErrorKindStatus)
+	}
+	res := d.FilterEjected("c", hosts)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "10.0.0.1:80", res[0].Address())
+
+	// all the candidates are ejected, fallback to all of them
+	assert.Len(t, d.FilterEjected("c", hosts[:1]), 1)
+}
+
+func TestDebugHandler(t *testing.T) {
+	d, _ := newTestDetector()
+	for j := 0; j < 3; j++ {
+		d.RecordError("c", "10.0.0.0:80", ErrorKindStatus)
+	}
+
+	w := httptest.NewRecorder()
+	DebugHandler(d).ServeHTTP(w, httptest.NewRequest(http.MethodGet, DebugPath+"?cluster=c", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"address":"10.0.0.0:80","ejected":true`)
+
+	w = httptest.NewRecorder()
+	DebugHandler(d).ServeHTTP(w, httptest.NewRequest(http.MethodGet, DebugPath+"?cluster=none", nil))
+	assert.Equal(t, "[]\n", w.Body.String())
+}
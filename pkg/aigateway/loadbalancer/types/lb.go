@@ -14,13 +14,20 @@
 
 package types
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type LoadBalancerType string
 
 const (
 	Random     LoadBalancerType = "Random"
 	RoundRobin LoadBalancerType = "RoundRobin"
+	// LeastRequest chooses the host with less requests in flight
+	LeastRequest LoadBalancerType = "LeastRequest"
+	// ConsistentHash chooses the host by the hash key, so the requests with the same key go to the same host
+	ConsistentHash LoadBalancerType = "ConsistentHash"
 )
 
 type Host interface {
@@ -29,6 +36,8 @@ type Host interface {
 	// Address returns the address in "ip:port" format
 	Address() string
 	Labels() map[string]string
+	// Weight is the load balancing weight, 0 is treated as 1
+	Weight() uint32
 }
 
 // GlobalLoadBalancer choose host from multiple clusters
@@ -40,3 +49,25 @@ type GlobalLoadBalancer interface {
 type LoadBalancer interface {
 	ChooseHost(context context.Context) Host
 }
+
+// HostUpdater is implemented by the load balancers which can replace their hosts in place,
+// so their state, e.g. the round robin position, is kept when the cluster is updated.
+// UpdateHosts may be called concurrently with ChooseHost.
+type HostUpdater interface {
+	UpdateHosts(hosts []Host)
+}
+
+// RequestTracker is implemented by the hosts which count the requests in flight,
+// so the hosts removed from the cluster are drained after the requests finish
+type RequestTracker interface {
+	RequestStarted()
+	RequestFinished()
+	// Inflight returns the requests in flight routed to the host by this gateway
+	Inflight() int64
+}
+
+// HostAge is implemented by the hosts which know when they are added to the cluster,
+// used to ramp up the traffic to the new hosts
+type HostAge interface {
+	AddedAt() time.Time
+}
@@ -15,71 +15,331 @@
 package async_log
 
 import (
-	"io"
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/natefinch/lumberjack.v2"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/prom"
 )
 
-const DefaultLogPath = "/home/admin/logs/llm.log"
+const (
+	DefaultLogPath       = "/home/admin/logs/llm.log"
+	DefaultQueueSize     = 1000
+	DefaultBatchSize     = 128
+	DefaultFlushInterval = time.Second
+	DefaultBlockTimeout  = 100 * time.Millisecond
+)
 
 var (
 	logger     *AsyncLogger
 	loggerOnce sync.Once
+
+	// sinks shared by the loggers, keyed by SinkConfig.key(), so that the same destination is only written
+	// by a single goroutine across the config updates. The settings of a destination are fixed once it's opened,
+	// so the sinks are never replaced until they are closed.
+	sinksLock sync.Mutex
+	sinks     = map[string]*queuedSink{}
+
+	errCloseTimeout = errors.New("timeout waiting for the log sink to flush")
 )
 
+// AsyncLogger writes the records to one or more sinks, each sink has its own queue,
+// so a slow sink won't block the others.
 type AsyncLogger struct {
-	logQueue chan []byte
-	stopChan chan struct{}
-	logger   io.Writer
+	sinks []*queuedSink
 }
 
+// GetAsyncLoggerInstance returns the process-wide logger which writes to the rotating file
 func GetAsyncLoggerInstance(path string, queueSize int) *AsyncLogger {
 	loggerOnce.Do(func() {
-		if path == "" {
-			path = DefaultLogPath
+		var err error
+		logger, err = NewAsyncLogger([]SinkConfig{
+			{
+				Type:      SinkTypeFile,
+				Path:      path,
+				QueueSize: queueSize,
+			},
+		})
+		if err != nil {
+			// the path is opened by a sink with different settings
+			api.LogErrorf("failed to create async logger: %v", err)
 		}
-		logger = createAsyncLogger(path, queueSize)
 	})
 	return logger
 }
 
-func createAsyncLogger(path string, queueSize int) *AsyncLogger {
-	l := getLogger(path)
-	al := &AsyncLogger{
-		logQueue: make(chan []byte, queueSize),
-		stopChan: make(chan struct{}),
-		logger:   l,
+// NewAsyncLogger creates a logger which writes to the given sinks. The sinks of the same destination are shared,
+// and they must have the same settings, the config with different settings is rejected.
+func NewAsyncLogger(configs []SinkConfig) (*AsyncLogger, error) {
+	sinksLock.Lock()
+	defer sinksLock.Unlock()
+
+	if err := checkSinkConfigs(configs); err != nil {
+		return nil, err
 	}
-	go al.run()
-	return al
+
+	al := &AsyncLogger{}
+	for _, config := range configs {
+		config = config.withDefaults()
+		key := config.key()
+		if qs, ok := sinks[key]; ok {
+			al.sinks = append(al.sinks, qs)
+			continue
+		}
+		sink, err := newSink(config)
+		if err != nil {
+			return nil, err
+		}
+		qs := newQueuedSink(config, sink)
+		sinks[key] = qs
+		al.sinks = append(al.sinks, qs)
+	}
+	return al, nil
 }
 
+// CheckSinkConfigs returns an error when a destination is configured with the settings different from
+// the opened sink or the other configs, so that the conflicting config can be rejected before it's used
+func CheckSinkConfigs(configs []SinkConfig) error {
+	sinksLock.Lock()
+	defer sinksLock.Unlock()
+	return checkSinkConfigs(configs)
+}
+
+// checkSinkConfigs must be called with sinksLock held
+func checkSinkConfigs(configs []SinkConfig) error {
+	seen := make(map[string]SinkConfig, len(configs))
+	for _, config := range configs {
+		config = config.withDefaults()
+		key := config.key()
+		if prev, ok := seen[key]; ok && prev != config {
+			return fmt.Errorf("log sink %s is configured with different settings", config.name())
+		}
+		seen[key] = config
+		if qs, ok := sinks[key]; ok && qs.config != config {
+			return fmt.Errorf("log sink %s is already opened with different settings, "+
+				"restart to change them. opened: %+v, configured: %+v", qs.name, qs.config, config)
+		}
+	}
+	return nil
+}
+
+// Write enqueues the record to all the sinks, the overflow policy of each sink applies when its queue is full
 func (al *AsyncLogger) Write(p []byte) {
+	for _, qs := range al.sinks {
+		qs.enqueue(p)
+	}
+}
+
+// Close flushes the queued records and closes the sinks of the logger, it waits up to timeout.
+// The records written after Close are dropped.
+func (al *AsyncLogger) Close(timeout time.Duration) error {
+	return closeSinks(al.sinks, timeout)
+}
+
+// Close flushes and closes all the sinks in the process, it's called on shutdown
+func Close(timeout time.Duration) error {
+	sinksLock.Lock()
+	all := make([]*queuedSink, 0, len(sinks))
+	for _, qs := range sinks {
+		all = append(all, qs)
+	}
+	sinksLock.Unlock()
+
+	return closeSinks(all, timeout)
+}
+
+func closeSinks(list []*queuedSink, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	sinksLock.Lock()
+	for _, qs := range list {
+		qs.stop()
+		if sinks[qs.key] == qs {
+			delete(sinks, qs.key)
+		}
+	}
+	sinksLock.Unlock()
+
+	var errs []error
+	for _, qs := range list {
+		if err := qs.wait(time.Until(deadline)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", qs.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type queuedSink struct {
+	key    string
+	name   string
+	config SinkConfig
+	sink   Sink
+
+	logQueue  chan []byte
+	queueSize prometheus.Gauge
+	closed    atomic.Bool
+	stopOnce  sync.Once
+	stopChan  chan struct{}
+	done      chan struct{}
+}
+
+func newQueuedSink(config SinkConfig, sink Sink) *queuedSink {
+	config = config.withDefaults()
+	name := config.name()
+	qs := &queuedSink{
+		key:       config.key(),
+		name:      name,
+		config:    config,
+		sink:      sink,
+		logQueue:  make(chan []byte, config.QueueSize),
+		queueSize: prom.LLMLogQueueSize.WithLabelValues(name),
+		stopChan:  make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go qs.run()
+	return qs
+}
+
+func (qs *queuedSink) drop(reason string, n int) {
+	prom.LLMLogDroppedTotal.WithLabelValues(qs.name, reason).Add(float64(n))
+}
+
+func (qs *queuedSink) enqueue(p []byte) {
+	if qs.closed.Load() {
+		qs.drop("closed", 1)
+		return
+	}
+
 	select {
-	case al.logQueue <- p:
+	case qs.logQueue <- p:
+		return
 	default:
-		api.LogErrorf("log queue is full, dropping log")
+	}
+
+	switch qs.config.OverflowPolicy {
+	case OverflowDropOldest:
+		// the consumer may take the records concurrently, so the room is not guaranteed after evicting one
+		for i := 0; i < 3; i++ {
+			select {
+			case <-qs.logQueue:
+				qs.drop("queue_full", 1)
+			default:
+			}
+			select {
+			case qs.logQueue <- p:
+				return
+			default:
+			}
+		}
+		qs.drop("queue_full", 1)
+	case OverflowBlock:
+		timer := time.NewTimer(qs.config.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case qs.logQueue <- p:
+		case <-timer.C:
+			qs.drop("block_timeout", 1)
+		}
+	default:
+		qs.drop("queue_full", 1)
 	}
 }
 
-func (al *AsyncLogger) run() {
+func (qs *queuedSink) flush(batch [][]byte) [][]byte {
+	qs.queueSize.Set(float64(len(qs.logQueue)))
+	if len(batch) == 0 {
+		return batch
+	}
+
+	start := time.Now()
+	err := qs.sink.Write(batch)
+	prom.LLMLogWriteDuration.WithLabelValues(qs.name).Observe(float64(time.Since(start).Microseconds()) / 1000)
+	if err != nil {
+		api.LogErrorf("async log write to %s failed: %v", qs.name, err)
+		qs.drop("write_error", len(batch))
+	}
+	return batch[:0]
+}
+
+func (qs *queuedSink) run() {
+	defer close(qs.done)
+	defer func() {
+		if r := recover(); r != nil {
+			api.LogErrorf("async log sink %s panic: %v", qs.name, r)
+		}
+	}()
+
+	ticker := time.NewTicker(qs.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, qs.config.BatchSize)
 	for {
 		select {
-		case log := <-al.logQueue:
-			_, err := al.logger.Write(log)
-			if err != nil {
-				api.LogErrorf("async log write failed: %v", err)
+		case log := <-qs.logQueue:
+			batch = append(batch, log)
+			if len(batch) >= qs.config.BatchSize {
+				batch = qs.flush(batch)
 			}
-		case <-al.stopChan:
+		case <-ticker.C:
+			batch = qs.flush(batch)
+		case <-qs.stopChan:
+			qs.drain(batch)
 			return
 		}
 	}
 }
 
+// drain writes all the queued records and closes the sink
+func (qs *queuedSink) drain(batch [][]byte) {
+	for drained := false; !drained; {
+		select {
+		case log := <-qs.logQueue:
+			batch = append(batch, log)
+			if len(batch) >= qs.config.BatchSize {
+				batch = qs.flush(batch)
+			}
+		default:
+			drained = true
+		}
+	}
+	qs.flush(batch)
+
+	if err := qs.sink.Close(); err != nil {
+		api.LogErrorf("failed to close async log sink %s: %v", qs.name, err)
+	}
+	// the records enqueued concurrently with closing
+	if n := len(qs.logQueue); n > 0 {
+		qs.drop("closed", n)
+	}
+	prom.LLMLogQueueSize.DeleteLabelValues(qs.name)
+}
+
+func (qs *queuedSink) stop() {
+	qs.stopOnce.Do(func() {
+		qs.closed.Store(true)
+		close(qs.stopChan)
+	})
+}
+
+func (qs *queuedSink) wait(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-qs.done:
+		return nil
+	case <-timer.C:
+		return errCloseTimeout
+	}
+}
+
 func getLogger(path string) *lumberjack.Logger {
 	return &lumberjack.Logger{
 		Filename:   path,
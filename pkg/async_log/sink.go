@@ -0,0 +1,134 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package async_log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	SinkTypeFile   = "file"
+	SinkTypeStdout = "stdout"
+	SinkTypeUnix   = "unix"
+	SinkTypeTCP    = "tcp"
+	SinkTypeOTLP   = "otlp"
+)
+
+const (
+	// OverflowDropNewest drops the record being written when the queue is full
+	OverflowDropNewest = "drop_newest"
+	// OverflowDropOldest drops the oldest record in the queue to make room for the new one
+	OverflowDropOldest = "drop_oldest"
+	// OverflowBlock blocks the writer until the queue has room or BlockTimeout is reached
+	OverflowBlock = "block"
+)
+
+// Sink is the destination of the log records. Write is called in a single goroutine per sink,
+// so the implementations don't need to be thread-safe.
+type Sink interface {
+	// Write writes a batch of records, each record is a complete line ending with '\n'
+	Write(records [][]byte) error
+	Close() error
+}
+
+// SinkConfig configures a sink, the same destination shares the same sink and queue in the process
+type SinkConfig struct {
+	Type string
+	// file path for file sink
+	Path string
+	// socket path for unix sink, host:port for tcp sink, and the logs endpoint for otlp sink,
+	// e.g. http://127.0.0.1:4318/v1/logs
+	Address string
+	// size of the queue in front of the sink
+	QueueSize int
+	// OverflowPolicy is the policy when the queue is full, defaults to OverflowDropNewest
+	OverflowPolicy string
+	// BlockTimeout is the max time to wait for OverflowBlock
+	BlockTimeout time.Duration
+	// records are written in batches of BatchSize, or every FlushInterval
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// withDefaults fills the unset queue settings with the defaults
+func (c SinkConfig) withDefaults() SinkConfig {
+	if c.QueueSize <= 0 {
+		c.QueueSize = DefaultQueueSize
+	}
+	if c.OverflowPolicy == "" {
+		c.OverflowPolicy = OverflowDropNewest
+	}
+	if c.BlockTimeout <= 0 {
+		c.BlockTimeout = DefaultBlockTimeout
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultBatchSize
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = DefaultFlushInterval
+	}
+	return c
+}
+
+// key identifies the destination of the sink
+func (c SinkConfig) key() string {
+	return fmt.Sprintf("%s|%s|%s", c.Type, c.Path, c.Address)
+}
+
+// name is used as the metric label
+func (c SinkConfig) name() string {
+	switch c.Type {
+	case SinkTypeFile:
+		return c.Type + ":" + c.Path
+	case SinkTypeStdout:
+		return c.Type
+	default:
+		return c.Type + ":" + c.Address
+	}
+}
+
+type SinkFactory func(config SinkConfig) (Sink, error)
+
+var (
+	sinkFactoryLock sync.RWMutex
+	sinkFactories   = map[string]SinkFactory{}
+)
+
+func init() {
+	RegisterSinkType(SinkTypeFile, newFileSink)
+	RegisterSinkType(SinkTypeStdout, newStdoutSink)
+	RegisterSinkType(SinkTypeUnix, newSocketSink)
+	RegisterSinkType(SinkTypeTCP, newSocketSink)
+	RegisterSinkType(SinkTypeOTLP, newOTLPSink)
+}
+
+// RegisterSinkType registers a new type of sink
+func RegisterSinkType(typ string, factory SinkFactory) {
+	sinkFactoryLock.Lock()
+	defer sinkFactoryLock.Unlock()
+	sinkFactories[typ] = factory
+}
+
+func newSink(config SinkConfig) (Sink, error) {
+	sinkFactoryLock.RLock()
+	factory, ok := sinkFactories[config.Type]
+	sinkFactoryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown log sink type: %s", config.Type)
+	}
+	return factory(config)
+}
@@ -0,0 +1,56 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package async_log
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// writerSink writes the records to an io.Writer, a batch is written at once
+type writerSink struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (s *writerSink) Write(records [][]byte) error {
+	s.buf.Reset()
+	for _, r := range records {
+		s.buf.Write(r)
+	}
+	_, err := s.w.Write(s.buf.Bytes())
+	return err
+}
+
+func (s *writerSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok && s.w != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
+
+// newFileSink creates a rotating file sink
+func newFileSink(config SinkConfig) (Sink, error) {
+	path := config.Path
+	if path == "" {
+		path = DefaultLogPath
+	}
+	return &writerSink{w: getLogger(path)}, nil
+}
+
+func newStdoutSink(config SinkConfig) (Sink, error) {
+	return &writerSink{w: os.Stdout}, nil
+}
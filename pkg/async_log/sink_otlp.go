@@ -0,0 +1,128 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package async_log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	otlpServiceName = "aigw"
+	otlpScopeName   = "aigw.llm"
+	otlpTimeout     = 5 * time.Second
+)
+
+// OTLP/HTTP JSON encoding of the logs, see
+// https://opentelemetry.io/docs/specs/otlp/#otlphttp
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano string       `json:"timeUnixNano"`
+	Body         otlpAnyValue `json:"body"`
+}
+
+type otlpScopeLogs struct {
+	Scope      map[string]string `json:"scope"`
+	LogRecords []otlpLogRecord   `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource  map[string][]otlpKeyValue `json:"resource"`
+	ScopeLogs []otlpScopeLogs           `json:"scopeLogs"`
+}
+
+type otlpExportRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// otlpSink exports the records to an OTLP/HTTP logs endpoint, each record is sent as the string body of a log record
+type otlpSink struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newOTLPSink(config SinkConfig) (Sink, error) {
+	if config.Address == "" {
+		return nil, errors.New("address is required for otlp log sink")
+	}
+	return &otlpSink{
+		endpoint: config.Address,
+		client:   &http.Client{Timeout: otlpTimeout},
+	}, nil
+}
+
+func buildOTLPRequest(records [][]byte, now time.Time) otlpExportRequest {
+	ts := strconv.FormatInt(now.UnixNano(), 10)
+	logRecords := make([]otlpLogRecord, 0, len(records))
+	for _, r := range records {
+		logRecords = append(logRecords, otlpLogRecord{
+			TimeUnixNano: ts,
+			Body:         otlpAnyValue{StringValue: string(bytes.TrimRight(r, "\n"))},
+		})
+	}
+	return otlpExportRequest{
+		ResourceLogs: []otlpResourceLogs{
+			{
+				Resource: map[string][]otlpKeyValue{
+					"attributes": {
+						{Key: "service.name", Value: otlpAnyValue{StringValue: otlpServiceName}},
+					},
+				},
+				ScopeLogs: []otlpScopeLogs{
+					{
+						Scope:      map[string]string{"name": otlpScopeName},
+						LogRecords: logRecords,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (s *otlpSink) Write(records [][]byte) error {
+	data, err := json.Marshal(buildOTLPRequest(records, time.Now()))
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp endpoint %s returned status %d", s.endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *otlpSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package async_log
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"time"
+)
+
+const (
+	socketDialTimeout  = time.Second
+	socketWriteTimeout = 5 * time.Second
+)
+
+// socketSink forwards the records to a unix domain socket or TCP address, e.g. a log agent.
+// The connection is established lazily, and re-established after a failure.
+type socketSink struct {
+	network string
+	address string
+	conn    net.Conn
+	buf     bytes.Buffer
+}
+
+func newSocketSink(config SinkConfig) (Sink, error) {
+	if config.Address == "" {
+		return nil, errors.New("address is required for socket log sink")
+	}
+	return &socketSink{
+		network: config.Type,
+		address: config.Address,
+	}, nil
+}
+
+func (s *socketSink) Write(records [][]byte) error {
+	if s.conn == nil {
+		conn, err := net.DialTimeout(s.network, s.address, socketDialTimeout)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.buf.Reset()
+	for _, r := range records {
+		s.buf.Write(r)
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+	if _, err := s.conn.Write(s.buf.Bytes()); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *socketSink) Close() error {
+	if s.conn != nil {
+		err := s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package async_log
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+type memorySink struct {
+	lock    sync.Mutex
+	records []string
+	block   chan struct{}
+}
+
+func (s *memorySink) Write(records [][]byte) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, r := range records {
+		s.records = append(s.records, string(r))
+	}
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
+
+func (s *memorySink) Records() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.records...)
+}
+
+// registerMemorySink registers the sink with a unique type, since the sinks are shared in the process
+func registerMemorySink(s *memorySink) string {
+	typ := fmt.Sprintf("memory-%d", memorySinkSeq.Add(1))
+	RegisterSinkType(typ, func(SinkConfig) (Sink, error) { return s, nil })
+	return typ
+}
+
+var memorySinkSeq atomic.Int64
+
+func TestNewAsyncLoggerUnknownType(t *testing.T) {
+	_, err := NewAsyncLogger([]SinkConfig{{Type: "unknown"}})
+	assert.Error(t, err)
+
+	_, err = NewAsyncLogger([]SinkConfig{{Type: SinkTypeTCP}})
+	assert.Error(t, err)
+}
+
+func TestAsyncLoggerFanOut(t *testing.T) {
+	a := &memorySink{}
+	b := &memorySink{}
+	typA := registerMemorySink(a)
+	typB := registerMemorySink(b)
+
+	l, err := NewAsyncLogger([]SinkConfig{{Type: typA, FlushInterval: 10 * time.Millisecond}, {Type: typB, FlushInterval: 10 * time.Millisecond}})
+	assert.NoError(t, err)
+	l.Write([]byte("hello\n"))
+
+	assert.Eventually(t, func() bool {
+		return len(a.Records()) == 1 && len(b.Records()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// the sink with the same config is shared
+	l2, err := NewAsyncLogger([]SinkConfig{{Type: typA, FlushInterval: 10 * time.Millisecond}})
+	assert.NoError(t, err)
+	assert.Same(t, l.sinks[0], l2.sinks[0])
+}
+
+func TestAsyncLoggerSharedDestination(t *testing.T) {
+	s := &memorySink{}
+	typ := registerMemorySink(s)
+	config := SinkConfig{Type: typ, FlushInterval: 10 * time.Millisecond}
+
+	l1, err := NewAsyncLogger([]SinkConfig{config})
+	assert.NoError(t, err)
+	// the defaults are the same settings
+	withDefaults := config
+	withDefaults.QueueSize = DefaultQueueSize
+	l2, err := NewAsyncLogger([]SinkConfig{withDefaults})
+	assert.NoError(t, err)
+	assert.Same(t, l1.sinks[0], l2.sinks[0])
+
+	// the conflicting settings are rejected, and the opened sink is kept
+	conflicts := []SinkConfig{
+		{Type: typ, FlushInterval: 10 * time.Millisecond, QueueSize: 10},
+		{Type: typ, FlushInterval: 10 * time.Millisecond, OverflowPolicy: OverflowBlock},
+		{Type: typ, FlushInterval: 10 * time.Millisecond, BatchSize: 1},
+		{Type: typ, FlushInterval: time.Minute},
+	}
+	for _, c := range conflicts {
+		assert.Error(t, CheckSinkConfigs([]SinkConfig{c}))
+		_, err := NewAsyncLogger([]SinkConfig{c})
+		assert.Error(t, err)
+	}
+	assert.NoError(t, CheckSinkConfigs([]SinkConfig{config}))
+
+	// both loggers keep writing to the shared sink
+	for i := 0; i < 5; i++ {
+		l1.Write([]byte("1\n"))
+		l2.Write([]byte("2\n"))
+	}
+	assert.Eventually(t, func() bool {
+		return len(s.Records()) == 10
+	}, time.Second, 10*time.Millisecond)
+	assert.False(t, l1.sinks[0].closed.Load())
+
+	// the conflict in the same config
+	other := registerMemorySink(&memorySink{})
+	err = CheckSinkConfigs([]SinkConfig{{Type: other}, {Type: other, QueueSize: 10}})
+	assert.Error(t, err)
+	assert.NoError(t, l1.Close(time.Second))
+}
+
+func TestAsyncLoggerOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		reason  string
+		written []string
+	}{
+		{
+			name:    "drop newest",
+			policy:  OverflowDropNewest,
+			reason:  "queue_full",
+			written: []string{"1\n", "2\n"},
+		},
+		{
+			name:    "drop oldest",
+			policy:  OverflowDropOldest,
+			reason:  "queue_full",
+			written: []string{"1\n", "3\n"},
+		},
+		{
+			name:    "block",
+			policy:  OverflowBlock,
+			reason:  "block_timeout",
+			written: []string{"1\n", "2\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &memorySink{block: make(chan struct{})}
+			typ := registerMemorySink(s)
+			name := typ + ":"
+
+			l, err := NewAsyncLogger([]SinkConfig{{
+				Type:           typ,
+				QueueSize:      1,
+				BatchSize:      1,
+				OverflowPolicy: tt.policy,
+				BlockTimeout:   10 * time.Millisecond,
+			}})
+			assert.NoError(t, err)
+
+			before := testutil.ToFloat64(prom.LLMLogDroppedTotal.WithLabelValues(name, tt.reason))
+			// the first record is taken by the goroutine and blocked, the second one fills the queue
+			l.Write([]byte("1\n"))
+			assert.Eventually(t, func() bool {
+				return len(l.sinks[0].logQueue) == 0
+			}, time.Second, 10*time.Millisecond)
+			l.Write([]byte("2\n"))
+			l.Write([]byte("3\n"))
+			after := testutil.ToFloat64(prom.LLMLogDroppedTotal.WithLabelValues(name, tt.reason))
+			assert.Equal(t, 1.0, after-before)
+
+			close(s.block)
+			assert.NoError(t, l.Close(time.Second))
+			assert.Equal(t, tt.written, s.Records())
+		})
+	}
+}
+
+func TestAsyncLoggerClose(t *testing.T) {
+	s := &memorySink{}
+	typ := registerMemorySink(s)
+	name := typ + ":"
+
+	// records are only written when closing since the flush interval is long
+	l, err := NewAsyncLogger([]SinkConfig{{Type: typ, FlushInterval: time.Hour}})
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		l.Write([]byte("a\n"))
+	}
+	assert.Eventually(t, func() bool {
+		return len(l.sinks[0].logQueue) == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Empty(t, s.Records())
+
+	assert.NoError(t, Close(time.Second))
+	assert.Len(t, s.Records(), 10)
+
+	// closing again is fine
+	assert.NoError(t, l.Close(time.Second))
+
+	before := testutil.ToFloat64(prom.LLMLogDroppedTotal.WithLabelValues(name, "closed"))
+	l.Write([]byte("a\n"))
+	after := testutil.ToFloat64(prom.LLMLogDroppedTotal.WithLabelValues(name, "closed"))
+	assert.Equal(t, 1.0, after-before)
+
+	// a new sink is created for the same config after closing
+	l2, err := NewAsyncLogger([]SinkConfig{{Type: typ}})
+	assert.NoError(t, err)
+	assert.NotSame(t, l.sinks[0], l2.sinks[0])
+	assert.NoError(t, l2.Close(time.Second))
+}
+
+func TestAsyncLoggerCloseTimeout(t *testing.T) {
+	s := &memorySink{block: make(chan struct{})}
+	typ := registerMemorySink(s)
+
+	l, err := NewAsyncLogger([]SinkConfig{{Type: typ, BatchSize: 1}})
+	assert.NoError(t, err)
+	l.Write([]byte("a\n"))
+	assert.ErrorIs(t, l.Close(10*time.Millisecond), errCloseTimeout)
+	close(s.block)
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "llm.log")
+	s, err := newFileSink(SinkConfig{Type: SinkTypeFile, Path: path})
+	assert.NoError(t, err)
+	defer s.Close()
+
+	assert.NoError(t, s.Write([][]byte{[]byte("a\n"), []byte("b\n")}))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\n", string(data))
+}
+
+func TestSocketSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	s, err := newSocketSink(SinkConfig{Type: SinkTypeTCP, Address: ln.Addr().String()})
+	assert.NoError(t, err)
+	defer s.Close()
+
+	assert.NoError(t, s.Write([][]byte{[]byte("a\n"), []byte("b\n")}))
+	assert.Equal(t, "a", <-lines)
+	assert.Equal(t, "b", <-lines)
+
+	// write fails when the address is unreachable
+	s2, err := newSocketSink(SinkConfig{Type: SinkTypeUnix, Address: filepath.Join(t.TempDir(), "no.sock")})
+	assert.NoError(t, err)
+	assert.Error(t, s2.Write([][]byte{[]byte("a\n")}))
+}
+
+func TestOTLPSink(t *testing.T) {
+	var got otlpExportRequest
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s, err := newOTLPSink(SinkConfig{Type: SinkTypeOTLP, Address: srv.URL + "/v1/logs"})
+	assert.NoError(t, err)
+	defer s.Close()
+
+	assert.NoError(t, s.Write([][]byte{[]byte(`{"a":1}` + "\n"), []byte(`{"b":2}` + "\n")}))
+	if !assert.Len(t, got.ResourceLogs, 1) {
+		return
+	}
+	assert.Equal(t, "service.name", got.ResourceLogs[0].Resource["attributes"][0].Key)
+	records := got.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if !assert.Len(t, records, 2) {
+		return
+	}
+	assert.Equal(t, `{"a":1}`, records[0].Body.StringValue)
+	assert.Equal(t, `{"b":2}`, records[1].Body.StringValue)
+
+	status = http.StatusBadRequest
+	assert.Error(t, s.Write([][]byte{[]byte("a\n")}))
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"mosn.io/htnn/api/pkg/filtermanager/api"
@@ -53,6 +54,10 @@ type AsyncQueue struct {
 	cancel   context.CancelFunc
 	mu       sync.RWMutex
 	isClosed bool
+
+	// maxRetries and defaultTimeout can be changed at runtime, see SetRetryConfig
+	maxRetries     atomic.Int64
+	defaultTimeout atomic.Int64
 }
 
 func NewAsyncQueue(cfg Config, handler AsyncRequestHandler) *AsyncQueue {
@@ -65,6 +70,7 @@ func NewAsyncQueue(cfg Config, handler AsyncRequestHandler) *AsyncQueue {
 		ctx:     ctx,
 		cancel:  cancel,
 	}
+	aq.SetRetryConfig(cfg.MaxRetries, cfg.DefaultTimeout)
 
 	aq.wg.Add(cfg.WorkerCount)
 	for i := 0; i < cfg.WorkerCount; i++ {
@@ -74,6 +80,13 @@ func NewAsyncQueue(cfg Config, handler AsyncRequestHandler) *AsyncQueue {
 	return aq
 }
 
+// SetRetryConfig changes the retries and the default timeout of the tasks dispatched later,
+// while the queue size and the number of workers are fixed once created
+func (aq *AsyncQueue) SetRetryConfig(maxRetries int, defaultTimeout time.Duration) {
+	aq.maxRetries.Store(int64(maxRetries))
+	aq.defaultTimeout.Store(int64(defaultTimeout))
+}
+
 func (aq *AsyncQueue) Dispatch(task Task) error {
 	aq.mu.RLock()
 	defer aq.mu.RUnlock()
@@ -83,7 +96,7 @@ func (aq *AsyncQueue) Dispatch(task Task) error {
 	}
 
 	if task.Timeout == 0 {
-		task.Timeout = aq.config.DefaultTimeout
+		task.Timeout = time.Duration(aq.defaultTimeout.Load())
 	}
 
 	select {
@@ -105,13 +118,14 @@ func (aq *AsyncQueue) worker(id int) {
 		case task := <-aq.tasks:
 			startTime := time.Now()
 			var err error
-			for attempt := 0; attempt <= aq.config.MaxRetries; attempt++ {
+			maxRetries := int(aq.maxRetries.Load())
+			for attempt := 0; attempt <= maxRetries; attempt++ {
 				err = aq.handler.HandleRequest(aq.ctx, task)
 				if err == nil {
 					break
 				}
 				api.LogInfof("[TraceID: %s] worker attempt: %d, error: %v", task.TraceId, attempt+1, err)
-				if attempt < aq.config.MaxRetries {
+				if attempt < maxRetries {
 					time.Sleep(time.Duration(attempt+1) * 10 * time.Millisecond)
 				}
 			}
@@ -23,16 +23,20 @@ import (
 	"io"
 	"net"
 	"net/http"
-	"sync"
 	"time"
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
 
 	"github.com/aigw-project/aigw/pkg/async_request"
 	pkgcommon "github.com/aigw-project/aigw/pkg/common"
 	"github.com/aigw-project/aigw/pkg/metadata_center/servicediscovery"
 	"github.com/aigw-project/aigw/pkg/metadata_center/types"
 	"github.com/aigw-project/aigw/pkg/prom"
+	"github.com/aigw-project/aigw/pkg/runtimeconfig"
+	"github.com/aigw-project/aigw/pkg/trace"
 )
 
 const (
@@ -46,14 +50,15 @@ const (
 	MetaDataCenterCacheFetchPath   = "/v1/cache/query"
 	MetaDataCenterCacheSavePath    = "/v1/cache/save"
 
-	AigwMetaDataCenter_MaxFailoverRetry = "AIGW_META_MAX_FAILOVER_RETRY"
+	// the retries and the timeouts can be changed via the runtime config
+	AigwMetaDataCenter_MaxFailoverRetry = runtimeconfig.EnvMaxFailoverRetries
 	AigwMetaDataCenter_WorkerCount      = "AIGW_METADATA_CENTER_WORKER_COUNT"
-	AigwMetaDataCenter_MaxRetry         = "AIGW_METADATA_CENTER_MAX_RETRY"
+	AigwMetaDataCenter_MaxRetry         = runtimeconfig.EnvMaxRetries
 	AigwMetaDataCenter_QueueSize        = "AIGW_METADATA_CENTER_QUEUE_SIZE"
 
-	AigwMetaDataCenter_UpdateStatsTimeout = "AIGW_METADATA_CENTER_UPDATE_STATS_TIMEOUT"
-	AigwMetaDataCenter_FetchMetricTimeout = "AIGW_METADATA_CENTER_FETCH_METRIC_TIMEOUT"
-	AigwMetaDataCenter_FetchCacheTimeout  = "AIGW_META_DATA_CACHE_FETCH_TIMEOUT"
+	AigwMetaDataCenter_UpdateStatsTimeout = runtimeconfig.EnvUpdateStatsTimeout
+	AigwMetaDataCenter_FetchMetricTimeout = runtimeconfig.EnvFetchMetricTimeout
+	AigwMetaDataCenter_FetchCacheTimeout  = runtimeconfig.EnvFetchCacheTimeout
 
 	AigwMetaDataCenterClient_Timeout            = "AIGW_METADATA_CENTER_CLIENT_TIMEOUT"
 	AigwMetaDataCenterClient_IdelConnectTimeout = "AIGW_METADATA_CENTER_CLIENT_IDEL_CONNECT_TIMEOUT"
@@ -67,12 +72,6 @@ const (
 
 var (
 	metaDataCenter *MetaDataCenter
-
-	metaDataCenterFetchMetricTimeout     = 100 //ms
-	metaDataCenterFetchMetricTimeoutOnce sync.Once
-
-	metaDataCenterFetchCacheTimeout     = 100 //ms
-	metaDataCenterFetchCacheTimeoutOnce sync.Once
 )
 
 type ErrorInfo struct {
@@ -149,31 +148,30 @@ type MetaDataCenter struct {
 	dateCenterClient *MetaDataCenterClient
 }
 
+// GetMetaDataCenterFetchMetricTimeout returns the timeout in ms
 func GetMetaDataCenterFetchMetricTimeout() int {
-	metaDataCenterFetchMetricTimeoutOnce.Do(func() {
-		metaDataCenterFetchMetricTimeout = pkgcommon.GetIntFromEnv(AigwMetaDataCenter_FetchMetricTimeout, 100)
-	})
-	return metaDataCenterFetchMetricTimeout
+	return int(runtimeconfig.Get().FetchMetricTimeout.Milliseconds())
 }
 
+// GetMetaDataCenterFetchCacheTimeout returns the timeout in ms
 func GetMetaDataCenterFetchCacheTimeout() int {
-	metaDataCenterFetchCacheTimeoutOnce.Do(func() {
-		metaDataCenterFetchCacheTimeout = pkgcommon.GetIntFromEnv(AigwMetaDataCenter_FetchCacheTimeout, 100)
-	})
-	return metaDataCenterFetchCacheTimeout
+	return int(runtimeconfig.Get().FetchCacheTimeout.Milliseconds())
 }
 
 // GetMetaDataCenterInstance always return the instance of MetaDataCenter
 func NewMetaCenter() types.MetadataCenter {
 	client := NewMetaDataCenterClient()
-	timeout := pkgcommon.GetIntFromEnv(AigwMetaDataCenter_UpdateStatsTimeout, 100)
+	values := runtimeconfig.Get()
 	cfg := async_request.Config{
 		QueueSize:      pkgcommon.GetIntFromEnv(AigwMetaDataCenter_QueueSize, 1000),
 		WorkerCount:    pkgcommon.GetIntFromEnv(AigwMetaDataCenter_WorkerCount, 100),
-		MaxRetries:     pkgcommon.GetIntFromEnv(AigwMetaDataCenter_MaxRetry, 0),
-		DefaultTimeout: time.Duration(timeout) * time.Millisecond,
+		MaxRetries:     values.MaxRetries,
+		DefaultTimeout: values.UpdateStatsTimeout,
 	}
 	asyncQueue := async_request.NewAsyncQueue(cfg, client)
+	runtimeconfig.Subscribe(func(v runtimeconfig.Values) {
+		asyncQueue.SetRetryConfig(v.MaxRetries, v.UpdateStatsTimeout)
+	})
 	metaDataCenter = &MetaDataCenter{
 		asyncQueue:       asyncQueue,
 		dateCenterClient: client,
@@ -183,8 +181,22 @@ func NewMetaCenter() types.MetadataCenter {
 	return metaDataCenter
 }
 
+// getTraceId returns the trace id set by the load balancer or the caller, or the id of the trace in ctx
+func getTraceId(ctx context.Context) string {
+	if traceId := pkgcommon.GetValueFromCtx(ctx, MetaCenterTraceId, ""); traceId != "" {
+		return traceId
+	}
+	if traceId, ok := ctx.Value(types.CtxKeyTraceID).(string); ok && traceId != "" {
+		return traceId
+	}
+	if sc := oteltrace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
 func (mc *MetaDataCenter) AddRequest(ctx context.Context, requestId, cluster, ip string, promptLength int) error {
-	traceId := pkgcommon.GetValueFromCtx(ctx, MetaCenterTraceId, "")
+	traceId := getTraceId(ctx)
 	req := &InferenceRequest{
 		RequestId:    requestId,
 		Cluster:      cluster,
@@ -216,7 +228,7 @@ func (mc *MetaDataCenter) AddRequest(ctx context.Context, requestId, cluster, ip
 }
 
 func (mc *MetaDataCenter) DeleteRequest(ctx context.Context, requestId string) error {
-	traceId := pkgcommon.GetValueFromCtx(ctx, MetaCenterTraceId, "")
+	traceId := getTraceId(ctx)
 	req := &InferenceRequest{
 		RequestId: requestId,
 		TraceId:   traceId,
@@ -250,7 +262,7 @@ func updateMetacenterMetrics(instance, method, path string, duration int64) {
 }
 
 func (mc *MetaDataCenter) DeleteRequestPrompt(ctx context.Context, requestId string) error {
-	traceId := pkgcommon.GetValueFromCtx(ctx, MetaCenterTraceId, "")
+	traceId := getTraceId(ctx)
 	req := &InferenceRequest{
 		RequestId: requestId,
 		TraceId:   traceId,
@@ -278,7 +290,7 @@ func (mc *MetaDataCenter) DeleteRequestPrompt(ctx context.Context, requestId str
 }
 
 func (mc *MetaDataCenter) QueryLoad(ctx context.Context, cluster string) (map[string]*types.EndpointStats, error) {
-	traceId := pkgcommon.GetValueFromCtx(ctx, MetaCenterTraceId, "")
+	traceId := getTraceId(ctx)
 	body, err := mc.dateCenterClient.doRequestWithRetry(ctx, RequestParam{
 		TraceId: traceId,
 		HashKey: cluster,
@@ -327,7 +339,6 @@ func (mc *MetaDataCenter) QueryLoad(ctx context.Context, cluster string) (map[st
 
 type MetaDataCenterClient struct {
 	client           *http.Client
-	failoverRetry    int
 	serviceDiscovery *servicediscovery.ServiceDiscovery
 }
 
@@ -346,16 +357,17 @@ func NewMetaDataCenterClient() *MetaDataCenterClient {
 		},
 	}
 
-	retryTimes := pkgcommon.GetIntFromEnv(AigwMetaDataCenter_MaxFailoverRetry, 1)
-
 	return &MetaDataCenterClient{
-		client:        client,
-		failoverRetry: retryTimes,
+		client: client,
 	}
 }
 
 func (m *MetaDataCenterClient) doRequestWithRetry(ctx context.Context, reqParam RequestParam) ([]byte, error) {
-	candidates := service.GetHosts(reqParam.HashKey, m.failoverRetry+1)
+	ctx, span := trace.StartSpan(ctx, "metadata_center "+reqParam.Path, oteltrace.WithSpanKind(oteltrace.SpanKindClient))
+	defer span.End()
+	span.SetAttributes(attribute.String("http.method", reqParam.Method), attribute.String("metadata_center.hash_key", reqParam.HashKey))
+
+	candidates := service.GetHosts(reqParam.HashKey, runtimeconfig.Get().MaxFailoverRetries+1)
 	if len(candidates) == 0 {
 		api.LogErrorf("[TraceID: %s] no available host to send hash request method: %s, url: %s", reqParam.TraceId, reqParam.Method, reqParam.Path)
 		return nil, errors.New("no available host")
@@ -377,7 +389,10 @@ func (m *MetaDataCenterClient) doRequestWithRetry(ctx context.Context, reqParam
 		service.ReportFailure(host)
 		api.LogWarnf("[TraceID: %s] request attempt %d/%d to host %s failed, error: %v. Retrying...", reqParam.TraceId, attempt+1, len(candidates), host, err)
 	}
-	return nil, fmt.Errorf("all %d attempts failed for hosts %v, last error: %w", len(candidates), candidates, lastErr)
+	err := fmt.Errorf("all %d attempts failed for hosts %v, last error: %w", len(candidates), candidates, lastErr)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, "all attempts failed")
+	return nil, err
 }
 
 func (m *MetaDataCenterClient) HandleRequest(ctx context.Context, task async_request.Task) error {
@@ -412,6 +427,7 @@ func (m *MetaDataCenterClient) doSingleRequest(ctx context.Context, host string,
 
 	req.ContentLength = int64(bodyReader.Len())
 	req.Header.Set(TraceIdHeader, reqParam.TraceId)
+	trace.InjectHTTP(ctx, req.Header)
 
 	if reqParam.Query != nil {
 		for k, v := range reqParam.Query {
@@ -452,7 +468,7 @@ func (mc *MetaDataCenter) QueryKVCache(ctx context.Context, cluster string, prom
 	}
 
 	body, err := mc.dateCenterClient.doRequestWithRetry(ctx, RequestParam{
-		TraceId: pkgcommon.GetValueFromCtx(ctx, MetaCenterTraceId, ""),
+		TraceId: getTraceId(ctx),
 		HashKey: param.Cluster,
 		Method:  http.MethodPost,
 		Path:    MetaDataCenterCacheFetchPath,
@@ -494,7 +510,7 @@ func (mc *MetaDataCenter) QueryKVCache(ctx context.Context, cluster string, prom
 }
 
 func (mc *MetaDataCenter) SaveKVCache(ctx context.Context, cluster, ip string, promptHash []uint64) error {
-	traceId := pkgcommon.GetValueFromCtx(ctx, MetaCenterTraceId, "")
+	traceId := getTraceId(ctx)
 	req := &CacheSaveParam{
 		Cluster:    cluster,
 		Ip:         ip,
@@ -15,8 +15,10 @@
 package metrics_stats
 
 import (
+	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 
 	"github.com/aigw-project/aigw/pkg/prediction"
 )
@@ -92,25 +94,177 @@ func init() {
 	}
 }
 
+// modelPredictor holds the TTFT predictor of a model. Training updates the private predictor under the lock,
+// and publishes a clone of it after each update, so predicting in the request path never takes the lock.
+type modelPredictor struct {
+	kind      string
+	lock      sync.Mutex
+	training  prediction.TTFTPrediction
+	samples   int64
+	published atomic.Value // prediction.TTFTPrediction
+}
+
+// stateExporter is implemented by the predictors which can be persisted, e.g. prediction.RLS
+type stateExporter interface {
+	State() *prediction.RLSState
+}
+
+func newModelPredictor(kind string, base prediction.TTFTPrediction) *modelPredictor {
+	m := &modelPredictor{
+		kind:     kind,
+		training: base.Clone(),
+	}
+	m.published.Store(base.Clone())
+	return m
+}
+
+// newDefaultPredictor creates a predictor of the kind trained with the default data
+func newDefaultPredictor(kind string) (*modelPredictor, error) {
+	if kind == "" || kind == prediction.KindRLS {
+		return newModelPredictor(prediction.KindRLS, defaultModel), nil
+	}
+	predictor, err := prediction.New(kind)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range defaultTTFTTrainData {
+		predictor.Train(d.input, d.cached, d.ttft)
+	}
+	return newModelPredictor(kind, predictor), nil
+}
+
+func (m *modelPredictor) predictor() prediction.TTFTPrediction {
+	return m.published.Load().(prediction.TTFTPrediction)
+}
+
+func (m *modelPredictor) train(length, cached int, load prediction.Load, y float64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if p, ok := m.training.(prediction.LoadAwarePrediction); ok {
+		p.TrainWithLoad(length, cached, load, y)
+	} else {
+		m.training.Train(length, cached, y)
+	}
+	m.samples++
+	m.published.Store(m.training.Clone())
+}
+
+func (m *modelPredictor) state() *prediction.RLSState {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	exporter, ok := m.training.(stateExporter)
+	if !ok {
+		return nil
+	}
+	state := exporter.State()
+	state.Samples = m.samples
+	return state
+}
+
 func (p *PredictionModels) PredictTTFT(modelName string, length int) int64 {
+	return p.PredictTTFTWithLoad(modelName, length, prediction.Load{})
+}
+
+// PredictTTFTWithLoad predicts the TTFT, the load is only used by the load-aware predictors
+func (p *PredictionModels) PredictTTFTWithLoad(modelName string, length int, load prediction.Load) int64 {
 	// use default if not found model
 	predictor := defaultModel
 	if v, ok := p.models.Load(modelName); ok {
-		predictor = v.(prediction.TTFTPrediction)
+		predictor = v.(*modelPredictor).predictor()
 	}
 
 	// we don't use the cached length here, since it not accurate enough
-	t := predictor.Predict(length, 0)
+	var t float64
+	if lp, ok := predictor.(prediction.LoadAwarePrediction); ok {
+		t = lp.PredictWithLoad(length, 0, load)
+	} else {
+		t = predictor.Predict(length, 0)
+	}
 	return int64(math.Max(t, minTTFT))
 }
 
 func (p *PredictionModels) TrainTTFT(modelName string, length, cached int, y float64) {
-	var predictor prediction.TTFTPrediction
-	if value, ok := p.models.Load(modelName); ok {
-		predictor = value.(prediction.TTFTPrediction)
-	} else {
-		predictor = defaultModel.Clone()
-		p.models.Store(modelName, predictor)
+	p.TrainTTFTWithLoad(modelName, length, cached, prediction.Load{}, y)
+}
+
+// TrainTTFTWithLoad trains the predictor of the model, the load is only used by the load-aware predictors
+func (p *PredictionModels) TrainTTFTWithLoad(modelName string, length, cached int, load prediction.Load, y float64) {
+	v, ok := p.models.Load(modelName)
+	if !ok {
+		v, _ = p.models.LoadOrStore(modelName, newModelPredictor(prediction.KindRLS, defaultModel))
+	}
+	v.(*modelPredictor).train(length, cached, load, y)
+}
+
+// SetPredictor selects the kind of predictor for the model, see the kinds in package prediction.
+// The learned model is kept if the kind is not changed, otherwise it starts from the default data.
+func (p *PredictionModels) SetPredictor(modelName, kind string) error {
+	if kind == "" {
+		kind = prediction.KindRLS
+	}
+	if v, ok := p.models.Load(modelName); ok && v.(*modelPredictor).kind == kind {
+		return nil
+	}
+	m, err := newDefaultPredictor(kind)
+	if err != nil {
+		return err
+	}
+	p.models.Store(modelName, m)
+	return nil
+}
+
+// Range calls fn with a snapshot of the predictor of each trained model
+func (p *PredictionModels) Range(fn func(modelName string, predictor prediction.TTFTPrediction)) {
+	p.models.Range(func(key, value any) bool {
+		fn(key.(string), value.(*modelPredictor).predictor())
+		return true
+	})
+}
+
+// Snapshot returns the state of all trained models, the models which can't be persisted are skipped
+func (p *PredictionModels) Snapshot() *prediction.Snapshot {
+	snapshot := &prediction.Snapshot{
+		Version: prediction.SnapshotVersion,
+		Models:  map[string]*prediction.RLSState{},
+	}
+	p.models.Range(func(key, value any) bool {
+		if state := value.(*modelPredictor).state(); state != nil {
+			snapshot.Models[key.(string)] = state
+		}
+		return true
+	})
+	return snapshot
+}
+
+// Load restores the models from the snapshot. The existing models are kept unless overwrite is true.
+// The snapshot is validated before any model is loaded.
+func (p *PredictionModels) Load(snapshot *prediction.Snapshot, overwrite bool) error {
+	predictors := make(map[string]*modelPredictor, len(snapshot.Models))
+	for modelName, state := range snapshot.Models {
+		if state == nil {
+			continue
+		}
+		predictor, err := prediction.NewFromState(state)
+		if err != nil {
+			return fmt.Errorf("invalid model %s: %w", modelName, err)
+		}
+		kind := state.Kind
+		if kind == "" {
+			kind = prediction.KindRLS
+		}
+		m := newModelPredictor(kind, predictor)
+		m.samples = state.Samples
+		predictors[modelName] = m
+	}
+
+	for modelName, m := range predictors {
+		if overwrite {
+			p.models.Store(modelName, m)
+		} else {
+			p.models.LoadOrStore(modelName, m)
+		}
 	}
-	predictor.Train(length, cached, y)
+	return nil
 }
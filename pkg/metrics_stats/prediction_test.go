@@ -18,6 +18,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/aigw-project/aigw/pkg/prediction"
 )
 
 func TestPredictionModels_RecordTTFT(t *testing.T) {
@@ -55,3 +57,34 @@ func TestPredictionModels_RecordTTFT(t *testing.T) {
 		assert.LessOrEqual(t, int64(minTTFT), val)
 	}
 }
+
+func TestPredictionModels_SetPredictor(t *testing.T) {
+	models := NewPredictionModels()
+
+	assert.NoError(t, models.SetPredictor("load-model", prediction.KindLoadAware))
+	idle := models.PredictTTFTWithLoad("load-model", 8000, prediction.Load{})
+	for i := 0; i < 200; i++ {
+		models.TrainTTFTWithLoad("load-model", 8000, 0, prediction.Load{QueueDepth: 4, BatchSize: 20}, float64(idle+2000))
+		models.TrainTTFTWithLoad("load-model", 8000, 0, prediction.Load{}, float64(idle))
+	}
+	busy := models.PredictTTFTWithLoad("load-model", 8000, prediction.Load{QueueDepth: 4, BatchSize: 20})
+	assert.Greater(t, busy, models.PredictTTFTWithLoad("load-model", 8000, prediction.Load{}))
+
+	// the learned model is kept when the kind is not changed
+	assert.NoError(t, models.SetPredictor("load-model", prediction.KindLoadAware))
+	assert.Equal(t, busy, models.PredictTTFTWithLoad("load-model", 8000, prediction.Load{QueueDepth: 4, BatchSize: 20}))
+	// and the load-aware model can be persisted
+	assert.Equal(t, prediction.KindLoadAware, models.Snapshot().Models["load-model"].Kind)
+
+	// changing the kind starts from the default data
+	assert.NoError(t, models.SetPredictor("load-model", ""))
+	assert.Equal(t, models.PredictTTFT("unknown-model", 8000), models.PredictTTFT("load-model", 8000))
+
+	assert.NoError(t, models.SetPredictor("p90-model", prediction.KindP90))
+	assert.NoError(t, models.SetPredictor("isotonic-model", prediction.KindIsotonic))
+	assert.LessOrEqual(t, int64(minTTFT), models.PredictTTFT("isotonic-model", 8000))
+	// the models which can't be persisted are skipped
+	assert.NotContains(t, models.Snapshot().Models, "p90-model")
+
+	assert.Error(t, models.SetPredictor("model", "unknown"))
+}
@@ -0,0 +1,86 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_stats
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/aigw-project/aigw/pkg/prediction"
+)
+
+const (
+	// AIGW_TTFT_MODEL_SNAPSHOT_PATH is the file the learned TTFT models are periodically saved to,
+	// and loaded from at startup. Snapshotting is disabled when it's empty.
+	AIGW_TTFT_MODEL_SNAPSHOT_PATH     = "AIGW_TTFT_MODEL_SNAPSHOT_PATH"
+	AIGW_TTFT_MODEL_SNAPSHOT_INTERVAL = "AIGW_TTFT_MODEL_SNAPSHOT_INTERVAL"
+	// AIGW_TTFT_MODEL_IMPORT_PATH is a snapshot trained offline, e.g. by cmd/ttft-fit. It's loaded at startup
+	// for the models which are not in the snapshot of AIGW_TTFT_MODEL_SNAPSHOT_PATH, since that one has
+	// kept learning from the online traffic.
+	AIGW_TTFT_MODEL_IMPORT_PATH = "AIGW_TTFT_MODEL_IMPORT_PATH"
+
+	defaultSnapshotInterval = 5 * time.Minute
+)
+
+// LoadTTFTModels loads the persisted TTFT models, it should be called at startup.
+// A missing snapshot file is not an error, since it's not written before the first snapshot.
+func LoadTTFTModels() error {
+	return loadModels(predictionModels, os.Getenv(AIGW_TTFT_MODEL_SNAPSHOT_PATH), os.Getenv(AIGW_TTFT_MODEL_IMPORT_PATH))
+}
+
+// SaveTTFTModels saves the learned TTFT models to AIGW_TTFT_MODEL_SNAPSHOT_PATH, it does nothing if not set
+func SaveTTFTModels() error {
+	return saveModels(predictionModels, os.Getenv(AIGW_TTFT_MODEL_SNAPSHOT_PATH))
+}
+
+func loadModels(models *PredictionModels, snapshotPath, importPath string) error {
+	if snapshotPath != "" {
+		snapshot, err := prediction.LoadSnapshot(snapshotPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to load ttft model snapshot: %w", err)
+		}
+		if snapshot != nil {
+			if err := models.Load(snapshot, true); err != nil {
+				return fmt.Errorf("failed to load ttft model snapshot %s: %w", snapshotPath, err)
+			}
+		}
+	}
+
+	if importPath != "" {
+		snapshot, err := prediction.LoadSnapshot(importPath)
+		if err != nil {
+			return fmt.Errorf("failed to import ttft models: %w", err)
+		}
+		if err := models.Load(snapshot, false); err != nil {
+			return fmt.Errorf("failed to import ttft models %s: %w", importPath, err)
+		}
+	}
+	return nil
+}
+
+func saveModels(models *PredictionModels, path string) error {
+	if path == "" {
+		return nil
+	}
+	snapshot := models.Snapshot()
+	if len(snapshot.Models) == 0 {
+		// nothing learned yet, don't overwrite the previous snapshot
+		return nil
+	}
+	return prediction.SaveSnapshot(path, snapshot)
+}
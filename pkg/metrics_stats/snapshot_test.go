@@ -0,0 +1,89 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_stats
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aigw-project/aigw/pkg/prediction"
+)
+
+func TestPredictionModelsSnapshot(t *testing.T) {
+	models := NewPredictionModels()
+	models.TrainTTFT("model1", 1024, 0, 800)
+	models.TrainTTFT("model1", 2048, 0, 2000)
+
+	snapshot := models.Snapshot()
+	assert.Equal(t, prediction.SnapshotVersion, snapshot.Version)
+	if !assert.Contains(t, snapshot.Models, "model1") {
+		return
+	}
+	assert.Equal(t, int64(2), snapshot.Models["model1"].Samples)
+
+	restored := NewPredictionModels()
+	assert.NoError(t, restored.Load(snapshot, true))
+	assert.Equal(t, models.PredictTTFT("model1", 3000), restored.PredictTTFT("model1", 3000))
+	assert.Equal(t, snapshot, restored.Snapshot())
+}
+
+func TestPredictionModelsLoadInvalid(t *testing.T) {
+	models := NewPredictionModels()
+	state := prediction.NewRLS(1).State()
+	state.Params = state.Params[:1]
+
+	err := models.Load(&prediction.Snapshot{
+		Version: prediction.SnapshotVersion,
+		Models: map[string]*prediction.RLSState{
+			"valid":   prediction.NewRLS(1).State(),
+			"invalid": state,
+		},
+	}, true)
+	assert.ErrorContains(t, err, "invalid model invalid")
+	// nothing is loaded if any model is invalid
+	assert.Empty(t, models.Snapshot().Models)
+}
+
+func TestLoadModels(t *testing.T) {
+	dir := t.TempDir()
+	snapshotPath := filepath.Join(dir, "snapshot.json")
+	importPath := filepath.Join(dir, "import.json")
+
+	online := NewPredictionModels()
+	online.TrainTTFT("both", 1024, 0, 800)
+	assert.NoError(t, saveModels(online, snapshotPath))
+
+	offline := NewPredictionModels()
+	offline.TrainTTFT("both", 1024, 0, 5000)
+	offline.TrainTTFT("offline", 1024, 0, 5000)
+	assert.NoError(t, saveModels(offline, importPath))
+
+	models := NewPredictionModels()
+	assert.NoError(t, loadModels(models, snapshotPath, importPath))
+	// the online snapshot takes precedence over the imported one
+	assert.Equal(t, online.PredictTTFT("both", 1024), models.PredictTTFT("both", 1024))
+	assert.Equal(t, offline.PredictTTFT("offline", 1024), models.PredictTTFT("offline", 1024))
+
+	// the snapshot doesn't exist before the first save
+	assert.NoError(t, loadModels(NewPredictionModels(), filepath.Join(dir, "missing.json"), ""))
+	assert.Error(t, loadModels(NewPredictionModels(), "", filepath.Join(dir, "missing.json")))
+
+	// nothing to save
+	emptyPath := filepath.Join(dir, "empty.json")
+	assert.NoError(t, saveModels(NewPredictionModels(), emptyPath))
+	assert.NoFileExists(t, emptyPath)
+}
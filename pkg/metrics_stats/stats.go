@@ -14,38 +14,64 @@
 
 package metrics_stats
 
-import "os"
-
-var (
-	useMovingAverage = false
+import (
+	"github.com/aigw-project/aigw/pkg/prediction"
+	"github.com/aigw-project/aigw/pkg/runtimeconfig"
 )
 
-func init() {
-	if v := os.Getenv("AIGW_USE_MOVING_AVERAGE"); v == "enable" {
-		useMovingAverage = true
-	}
+// bytesPerToken is the average bytes of a token in the prompts, about 4 characters of English
+const bytesPerToken = 4
+
+// useMovingAverage can be changed via the runtime config. Only the models in use learn the samples,
+// so the others resume from what they learned before the switch.
+func useMovingAverage() bool {
+	return runtimeconfig.Get().UseMovingAverage
 }
 
 func MatchTTFT(modelName string, length int) int64 {
+	return MatchTTFTWithLoad(modelName, length, prediction.Load{})
+}
+
+// MatchTTFTWithLoad predicts the TTFT with the load of the chosen backend, the length is in tokens like the training.
+// The load is only used when the predictor of the model is load-aware
+func MatchTTFTWithLoad(modelName string, length int, load prediction.Load) int64 {
 	if length <= 0 || len(modelName) == 0 {
 		return defaultTTFT
 	}
 
-	if !useMovingAverage {
-		return predictionModels.PredictTTFT(modelName, length)
+	if !useMovingAverage() {
+		return predictionModels.PredictTTFTWithLoad(modelName, length, load)
 	}
 	return movingAverageModels.MatchTTFT(modelName, length)
 }
 
+// EstimateTokens estimates the tokens of the prompt by its length in bytes, so the TTFT could be predicted
+// before the prompt is tokenized by the inference server
+func EstimateTokens(length int) int {
+	if length <= 0 {
+		return 0
+	}
+	return (length + bytesPerToken - 1) / bytesPerToken
+}
+
 func RecordTTFT(modelName string, input, cached int, ttft int64) {
+	RecordTTFTWithLoad(modelName, input, cached, prediction.Load{}, ttft)
+}
+
+func RecordTTFTWithLoad(modelName string, input, cached int, load prediction.Load, ttft int64) {
 	// ignore invalid inputs
 	if input <= 0 || ttft <= 0 || len(modelName) == 0 {
 		return
 	}
 
-	if !useMovingAverage {
-		predictionModels.TrainTTFT(modelName, input, cached, float64(ttft))
+	if !useMovingAverage() {
+		predictionModels.TrainTTFTWithLoad(modelName, input, cached, load, float64(ttft))
 		return
 	}
 	movingAverageModels.RecordTTFT(modelName, input, ttft)
 }
+
+// SetTTFTPredictor selects the kind of the TTFT predictor for the model, see the kinds in package prediction
+func SetTTFTPredictor(modelName, kind string) error {
+	return predictionModels.SetPredictor(modelName, kind)
+}
@@ -0,0 +1,175 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_stats
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/common"
+	"github.com/aigw-project/aigw/pkg/prediction"
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+const (
+	// AIGW_TTFT_TRAIN_QUEUE_SIZE is the max samples waiting to be trained, the samples are dropped when it's full
+	AIGW_TTFT_TRAIN_QUEUE_SIZE = "AIGW_TTFT_TRAIN_QUEUE_SIZE"
+	// AIGW_TTFT_COEF_EXPORT_INTERVAL is the interval of exporting the coefficients of the predictors as metrics
+	AIGW_TTFT_COEF_EXPORT_INTERVAL = "AIGW_TTFT_COEF_EXPORT_INTERVAL"
+
+	defaultTrainQueueSize     = 4096
+	defaultCoefExportInterval = 30 * time.Second
+)
+
+type ttftSample struct {
+	modelName string
+	input     int
+	cached    int
+	load      prediction.Load
+	ttft      int64
+}
+
+// coefficientsExporter is implemented by the predictors which expose their coefficients, e.g. prediction.RLS
+type coefficientsExporter interface {
+	Terms() []string
+	Coefficients() []float64
+}
+
+// ttftTrainer trains the TTFT predictors in a background goroutine, so the request path only
+// pays for a non-blocking channel send. Samples are dropped when the queue is full.
+type ttftTrainer struct {
+	queue          chan ttftSample
+	models         *PredictionModels
+	record         func(modelName string, input, cached int, load prediction.Load, ttft int64)
+	exportInterval time.Duration
+	// save persists the models every snapshotInterval, snapshotting is disabled when it's nil
+	save             func() error
+	snapshotInterval time.Duration
+	startOnce        sync.Once
+}
+
+var (
+	defaultTrainer = newTTFTTrainer(
+		predictionModels,
+		RecordTTFTWithLoad,
+		common.GetIntFromEnv(AIGW_TTFT_TRAIN_QUEUE_SIZE, defaultTrainQueueSize),
+		common.GetDurationFromEnv(AIGW_TTFT_COEF_EXPORT_INTERVAL, defaultCoefExportInterval),
+	)
+)
+
+func init() {
+	if os.Getenv(AIGW_TTFT_MODEL_SNAPSHOT_PATH) != "" {
+		defaultTrainer.save = SaveTTFTModels
+		defaultTrainer.snapshotInterval = common.GetDurationFromEnv(AIGW_TTFT_MODEL_SNAPSHOT_INTERVAL, defaultSnapshotInterval)
+	}
+}
+
+func newTTFTTrainer(models *PredictionModels, record func(modelName string, input, cached int, load prediction.Load, ttft int64),
+	queueSize int, exportInterval time.Duration) *ttftTrainer {
+	if queueSize <= 0 {
+		queueSize = defaultTrainQueueSize
+	}
+	if exportInterval <= 0 {
+		exportInterval = defaultCoefExportInterval
+	}
+	return &ttftTrainer{
+		queue:          make(chan ttftSample, queueSize),
+		models:         models,
+		record:         record,
+		exportInterval: exportInterval,
+	}
+}
+
+func (t *ttftTrainer) start() {
+	t.startOnce.Do(func() {
+		go t.run()
+	})
+}
+
+// submit enqueues the sample without blocking, returns false if the sample is dropped
+func (t *ttftTrainer) submit(s ttftSample) bool {
+	select {
+	case t.queue <- s:
+		return true
+	default:
+		prom.TTFTTrainDroppedTotal.Inc()
+		return false
+	}
+}
+
+func (t *ttftTrainer) run() {
+	ticker := time.NewTicker(t.exportInterval)
+	defer ticker.Stop()
+
+	var snapshotC <-chan time.Time
+	if t.save != nil && t.snapshotInterval > 0 {
+		snapshotTicker := time.NewTicker(t.snapshotInterval)
+		defer snapshotTicker.Stop()
+		snapshotC = snapshotTicker.C
+	}
+
+	for {
+		select {
+		case s := <-t.queue:
+			t.record(s.modelName, s.input, s.cached, s.load, s.ttft)
+			prom.TTFTTrainSamplesTotal.WithLabelValues(s.modelName).Inc()
+		case <-ticker.C:
+			t.exportCoefficients()
+		case <-snapshotC:
+			if err := t.save(); err != nil {
+				api.LogErrorf("failed to save ttft model snapshot: %v", err)
+			}
+		}
+	}
+}
+
+// exportCoefficients exports the learned coefficients of each model as metrics
+func (t *ttftTrainer) exportCoefficients() {
+	t.models.Range(func(modelName string, predictor prediction.TTFTPrediction) {
+		exporter, ok := predictor.(coefficientsExporter)
+		if !ok {
+			return
+		}
+		terms := exporter.Terms()
+		for i, coef := range exporter.Coefficients() {
+			if i >= len(terms) {
+				break
+			}
+			prom.TTFTModelCoefficient.WithLabelValues(modelName, terms[i]).Set(coef)
+		}
+	})
+}
+
+// RecordTTFTAsync feeds the observed TTFT (in ms) of a successful request and the load of the backend
+// into the predictor of the model. Unlike RecordTTFT, it's safe to call it in the request path,
+// since training happens in the background.
+func RecordTTFTAsync(modelName string, input, cached int, load prediction.Load, ttft int64) {
+	// ignore invalid inputs
+	if input <= 0 || ttft <= 0 || len(modelName) == 0 {
+		return
+	}
+
+	defaultTrainer.start()
+	defaultTrainer.submit(ttftSample{
+		modelName: modelName,
+		input:     input,
+		cached:    cached,
+		load:      load,
+		ttft:      ttft,
+	})
+}
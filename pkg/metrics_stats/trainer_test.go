@@ -0,0 +1,101 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_stats
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aigw-project/aigw/pkg/prediction"
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+func TestTTFTTrainer(t *testing.T) {
+	models := NewPredictionModels()
+	trainer := newTTFTTrainer(models, func(modelName string, input, cached int, load prediction.Load, ttft int64) {
+		models.TrainTTFTWithLoad(modelName, input, cached, load, float64(ttft))
+	}, 2, time.Hour)
+
+	dropped := testutil.ToFloat64(prom.TTFTTrainDroppedTotal)
+	trained := testutil.ToFloat64(prom.TTFTTrainSamplesTotal.WithLabelValues("trainer-model"))
+	assert.True(t, trainer.submit(ttftSample{"trainer-model", 1024, 0, prediction.Load{}, 800}))
+	assert.True(t, trainer.submit(ttftSample{"trainer-model", 2048, 0, prediction.Load{}, 2000}))
+	// the queue is full before the trainer starts
+	assert.False(t, trainer.submit(ttftSample{"trainer-model", 3072, 0, prediction.Load{}, 3500}))
+	assert.Equal(t, dropped+1, testutil.ToFloat64(prom.TTFTTrainDroppedTotal))
+
+	trainer.start()
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(prom.TTFTTrainSamplesTotal.WithLabelValues("trainer-model")) == trained+2
+	}, time.Second, 10*time.Millisecond)
+
+	trainer.exportCoefficients()
+	var coefs []float64
+	models.Range(func(modelName string, predictor prediction.TTFTPrediction) {
+		if modelName == "trainer-model" {
+			coefs = predictor.(coefficientsExporter).Coefficients()
+		}
+	})
+	if !assert.Len(t, coefs, len(prediction.RLSTerms)) {
+		return
+	}
+	for i, term := range prediction.RLSTerms {
+		assert.Equal(t, coefs[i], testutil.ToFloat64(prom.TTFTModelCoefficient.WithLabelValues("trainer-model", term)))
+	}
+}
+
+func TestRecordTTFTAsyncIgnoreInvalid(t *testing.T) {
+	cases := []struct {
+		name      string
+		modelName string
+		input     int
+		ttft      int64
+	}{
+		{"empty model", "", 1024, 800},
+		{"zero input", "invalid-model", 0, 800},
+		{"zero ttft", "invalid-model", 1024, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			queued := len(defaultTrainer.queue)
+			RecordTTFTAsync(c.modelName, c.input, 0, prediction.Load{}, c.ttft)
+			assert.Equal(t, queued, len(defaultTrainer.queue))
+		})
+	}
+}
+
+func TestPredictionModelsConcurrent(t *testing.T) {
+	models := NewPredictionModels()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 1000; i++ {
+			models.TrainTTFT("concurrent-model", i*10, i, float64(i))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 1000; i++ {
+			assert.LessOrEqual(t, int64(minTTFT), models.PredictTTFT("concurrent-model", i*10))
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,163 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The evaluation harness replays the recorded datasets in testdata to the predictors, and reports the error.
+// The evaluation is prequential: each sample is predicted before it's learned, like the online training.
+//
+// Datasets, one JSON sample per line:
+//   - ttft_default.jsonl: the default training data of the TTFT predictor without load, it follows the quadratic
+//     polynomial of RLS closely
+//   - ttft_loaded.jsonl: synthesized from a prefill model with queueing and batching delay plus log-normal noise,
+//     since the LLM log doesn't record the load of the backend
+
+type evalSample struct {
+	Input      int     `json:"input"`
+	Cached     int     `json:"cached"`
+	QueueDepth int     `json:"queue_depth"`
+	BatchSize  int     `json:"batch_size"`
+	TTFTMs     float64 `json:"ttft_ms"`
+}
+
+type evalReport struct {
+	Samples int
+	// mean absolute error in ms
+	MAE float64
+	// mean absolute percentage error
+	MAPE float64
+	// p50 and p90 of the absolute percentage error
+	P50APE float64
+	P90APE float64
+	// ratio of the samples whose TTFT is not larger than the prediction, which should be ~0.9 for p90
+	Coverage float64
+}
+
+func loadEvalDataset(t *testing.T, name string) []evalSample {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer f.Close()
+
+	var samples []evalSample
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var s evalSample
+		if assert.NoError(t, json.Unmarshal(scanner.Bytes(), &s)) {
+			samples = append(samples, s)
+		}
+	}
+	assert.NoError(t, scanner.Err())
+	return samples
+}
+
+// evaluate learns the first warmup samples, then predicts and learns each of the rest
+func evaluate(p TTFTPrediction, samples []evalSample, warmup int) evalReport {
+	lp, loadAware := p.(LoadAwarePrediction)
+	predict := func(s evalSample) float64 {
+		if loadAware {
+			return lp.PredictWithLoad(s.Input, s.Cached, Load{QueueDepth: s.QueueDepth, BatchSize: s.BatchSize})
+		}
+		return p.Predict(s.Input, s.Cached)
+	}
+	train := func(s evalSample) {
+		if loadAware {
+			lp.TrainWithLoad(s.Input, s.Cached, Load{QueueDepth: s.QueueDepth, BatchSize: s.BatchSize}, s.TTFTMs)
+			return
+		}
+		p.Train(s.Input, s.Cached, s.TTFTMs)
+	}
+
+	var report evalReport
+	var apes []float64
+	covered := 0
+	for i, s := range samples {
+		if i >= warmup {
+			y := predict(s)
+			report.MAE += math.Abs(y - s.TTFTMs)
+			apes = append(apes, math.Abs(y-s.TTFTMs)/s.TTFTMs)
+			if s.TTFTMs <= y {
+				covered++
+			}
+		}
+		train(s)
+	}
+
+	report.Samples = len(apes)
+	if report.Samples == 0 {
+		return report
+	}
+	n := float64(report.Samples)
+	report.MAE /= n
+	for _, ape := range apes {
+		report.MAPE += ape
+	}
+	report.MAPE /= n
+	sort.Float64s(apes)
+	report.P50APE = apes[int(0.5*n)]
+	report.P90APE = apes[int(0.9*n)]
+	report.Coverage = float64(covered) / n
+	return report
+}
+
+func TestEvaluatePredictors(t *testing.T) {
+	cases := []struct {
+		dataset string
+		kind    string
+		warmup  int
+		// upper bound of MAPE, and the range of coverage. p90 is checked by the coverage only
+		maxMAPE     float64
+		minCoverage float64
+		maxCoverage float64
+	}{
+		{"ttft_default.jsonl", KindRLS, 10, 0.05, 0, 1},
+		{"ttft_default.jsonl", KindIsotonic, 10, 0.6, 0, 1},
+		{"ttft_default.jsonl", KindP90, 10, math.Inf(1), 0.7, 1},
+		{"ttft_default.jsonl", KindLoadAware, 10, 0.05, 0, 1},
+		{"ttft_loaded.jsonl", KindRLS, 50, 0.7, 0, 1},
+		{"ttft_loaded.jsonl", KindIsotonic, 50, 0.6, 0, 1},
+		{"ttft_loaded.jsonl", KindP90, 50, math.Inf(1), 0.85, 0.97},
+		// the load explains most of the error of the other predictors
+		{"ttft_loaded.jsonl", KindLoadAware, 50, 0.2, 0, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.dataset+"/"+c.kind, func(t *testing.T) {
+			samples := loadEvalDataset(t, c.dataset)
+			p, err := New(c.kind)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			r := evaluate(p, samples, c.warmup)
+			t.Logf("samples=%d mae=%.1fms mape=%.3f p50_ape=%.3f p90_ape=%.3f coverage=%.3f",
+				r.Samples, r.MAE, r.MAPE, r.P50APE, r.P90APE, r.Coverage)
+			assert.Less(t, r.MAPE, c.maxMAPE)
+			assert.GreaterOrEqual(t, r.Coverage, c.minCoverage)
+			assert.LessOrEqual(t, r.Coverage, c.maxCoverage)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import "sort"
+
+// upper bounds of the buckets of uncached prompt tokens, the last bucket is unbounded
+var isotonicBoundaries = []float64{256, 512, 1024, 2048, 4096, 8192, 16384, 32768, 65536, 131072}
+
+type isotonicBucket struct {
+	weight float64 // decayed number of samples
+	x      float64 // weighted mean of uncached tokens
+	y      float64 // weighted mean of TTFT
+}
+
+// Isotonic is a piecewise-linear isotonic regression of TTFT on the uncached prompt tokens.
+// The samples are averaged in log-spaced buckets, and the bucket means are pooled to be monotonically
+// non-decreasing, then the prediction interpolates linearly between them.
+// Unlike RLS, it doesn't assume the shape of the curve, e.g. the jump when the prompt exceeds the chunk size.
+type Isotonic struct {
+	lambda  float64 // forgetting factor of each bucket
+	buckets []isotonicBucket
+	// the fitted points, xs is increasing and ys is non-decreasing
+	xs []float64
+	ys []float64
+}
+
+func NewIsotonic(lambda float64) *Isotonic {
+	return &Isotonic{
+		lambda:  lambda,
+		buckets: make([]isotonicBucket, len(isotonicBoundaries)+1),
+	}
+}
+
+func uncachedTokens(input, cached int) float64 {
+	if cached >= input {
+		return 0
+	}
+	return float64(input - cached)
+}
+
+func (m *Isotonic) Predict(input, cached int) float64 {
+	n := len(m.xs)
+	if n == 0 {
+		return 0
+	}
+	if n == 1 {
+		return m.ys[0]
+	}
+
+	x := uncachedTokens(input, cached)
+	// index of the segment, extrapolate with the first or the last segment out of the range
+	i := sort.SearchFloat64s(m.xs, x)
+	if i == 0 {
+		i = 1
+	} else if i == n {
+		i = n - 1
+	}
+	slope := (m.ys[i] - m.ys[i-1]) / (m.xs[i] - m.xs[i-1])
+	y := m.ys[i-1] + slope*(x-m.xs[i-1])
+	if y < 0 {
+		return 0
+	}
+	return y
+}
+
+func (m *Isotonic) Train(input, cached int, y float64) {
+	x := uncachedTokens(input, cached)
+	b := &m.buckets[sort.SearchFloat64s(isotonicBoundaries, x)]
+	b.weight = b.weight*m.lambda + 1
+	b.x += (x - b.x) / b.weight
+	b.y += (y - b.y) / b.weight
+	m.fit()
+}
+
+// fit pools the adjacent violators, so that the TTFT is non-decreasing with the prompt tokens
+func (m *Isotonic) fit() {
+	type block struct {
+		weight float64
+		x      float64
+		y      float64
+	}
+	blocks := make([]block, 0, len(m.buckets))
+	for _, b := range m.buckets {
+		if b.weight == 0 {
+			continue
+		}
+		cur := block{weight: b.weight, x: b.x, y: b.y}
+		for len(blocks) > 0 && blocks[len(blocks)-1].y >= cur.y {
+			prev := blocks[len(blocks)-1]
+			blocks = blocks[:len(blocks)-1]
+			w := prev.weight + cur.weight
+			cur = block{
+				weight: w,
+				x:      (prev.x*prev.weight + cur.x*cur.weight) / w,
+				y:      (prev.y*prev.weight + cur.y*cur.weight) / w,
+			}
+		}
+		blocks = append(blocks, cur)
+	}
+
+	m.xs = m.xs[:0]
+	m.ys = m.ys[:0]
+	for _, b := range blocks {
+		m.xs = append(m.xs, b.x)
+		m.ys = append(m.ys, b.y)
+	}
+}
+
+func (m *Isotonic) Clone() TTFTPrediction {
+	clone := &Isotonic{
+		lambda:  m.lambda,
+		buckets: make([]isotonicBucket, len(m.buckets)),
+		xs:      make([]float64, len(m.xs)),
+		ys:      make([]float64, len(m.ys)),
+	}
+	copy(clone.buckets, m.buckets)
+	copy(clone.xs, m.xs)
+	copy(clone.ys, m.ys)
+	return clone
+}
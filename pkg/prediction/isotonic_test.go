@@ -0,0 +1,52 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsotonic(t *testing.T) {
+	m := NewIsotonic(1)
+	assert.Equal(t, 0.0, m.Predict(1000, 0))
+
+	m.Train(1000, 0, 500)
+	// a single point predicts constant
+	assert.Equal(t, 500.0, m.Predict(100, 0))
+	assert.Equal(t, 500.0, m.Predict(10000, 0))
+
+	m.Train(3000, 0, 1500)
+	// interpolate and extrapolate linearly
+	assert.InDelta(t, 1000, m.Predict(2000, 0), 1e-6)
+	assert.InDelta(t, 2500, m.Predict(5000, 0), 1e-6)
+	// the cached tokens are excluded
+	assert.InDelta(t, 1000, m.Predict(2500, 500), 1e-6)
+	assert.InDelta(t, 5, m.Predict(10, 0), 1e-6)
+
+	// the violator is pooled with the previous bucket, so the prediction is non-decreasing
+	m.Train(6000, 0, 1000)
+	prev := 0.0
+	for _, input := range []int{100, 1000, 2000, 3000, 4000, 6000, 10000, 100000} {
+		y := m.Predict(input, 0)
+		assert.GreaterOrEqual(t, y, prev, "input %d", input)
+		prev = y
+	}
+
+	clone := m.Clone()
+	m.Train(100000, 0, 50000)
+	assert.NotEqual(t, m.Predict(100000, 0), clone.Predict(100000, 0))
+}
@@ -0,0 +1,51 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import "fmt"
+
+// kinds of the predictors which can be selected for each model
+const (
+	// quadratic polynomial fitted by recursive least squares
+	KindRLS = "rls"
+	// piecewise-linear isotonic regression on the uncached prompt tokens
+	KindIsotonic = "isotonic"
+	// p90 of TTFT, for SLO use
+	KindP90 = "p90"
+	// RLS with the queue depth and the batch size of the backend as features
+	KindLoadAware = "load_aware"
+)
+
+const (
+	// forgetting factor of the isotonic buckets, roughly the last 1000 samples of each bucket are weighted
+	isotonicLambda = 0.999
+	// forgetting factor of the ratio counts of p90
+	quantileLambda = 0.999
+)
+
+// New creates an untrained predictor of the kind, empty kind means KindRLS
+func New(kind string) (TTFTPrediction, error) {
+	switch kind {
+	case "", KindRLS:
+		return NewRLS(1), nil
+	case KindIsotonic:
+		return NewIsotonic(isotonicLambda), nil
+	case KindP90:
+		return NewQuantile(NewRLS(1), 0.9, quantileLambda), nil
+	case KindLoadAware:
+		return NewLoadAwareRLS(1), nil
+	}
+	return nil, fmt.Errorf("unknown ttft predictor %s", kind)
+}
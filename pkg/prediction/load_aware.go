@@ -0,0 +1,150 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+// LoadAwareRLSTerms names the coefficients returned by LoadAwareRLS.Coefficients, in order
+var LoadAwareRLSTerms = append(append([]string{}, RLSTerms...), "queue", "batch", "queue_input", "batch_input")
+
+// LoadAwareRLS extends the polynomial of RLS with the load of the backend:
+// y = RLS(input, cached) + g*queue + h*batch + i*queue*input + j*batch*input
+// The requests in queue delay the prefill, and the running batch slows it down, more for longer prompts.
+type LoadAwareRLS struct {
+	params []float64
+	p      [][]float64
+	lambda float64
+}
+
+func NewLoadAwareRLS(lambda float64) *LoadAwareRLS {
+	n := len(LoadAwareRLSTerms)
+	p := make([][]float64, n)
+	for i := range p {
+		p[i] = make([]float64, n)
+		p[i][i] = 1e6
+	}
+	return &LoadAwareRLS{
+		params: make([]float64, n),
+		p:      p,
+		lambda: lambda,
+	}
+}
+
+func (rls *LoadAwareRLS) features(input, cached int, load Load) []float64 {
+	in := float64(input)
+	cac := float64(cached)
+	queue := float64(load.QueueDepth)
+	batch := float64(load.BatchSize)
+	return []float64{
+		in * in,
+		cac * cac,
+		in * cac,
+		in,
+		cac,
+		1.0,
+		queue,
+		batch,
+		queue * in,
+		batch * in,
+	}
+}
+
+func (rls *LoadAwareRLS) PredictWithLoad(input, cached int, load Load) float64 {
+	y := 0.0
+	for i, f := range rls.features(input, cached, load) {
+		y += rls.params[i] * f
+	}
+	return y
+}
+
+func (rls *LoadAwareRLS) TrainWithLoad(input, cached int, load Load, y float64) {
+	features := rls.features(input, cached, load)
+	n := len(features)
+
+	// phiTP = features^T * P, P is symmetric so P * features is the same
+	phiTP := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			phiTP[i] += features[j] * rls.p[j][i]
+		}
+	}
+	denominator := rls.lambda
+	for i := 0; i < n; i++ {
+		denominator += phiTP[i] * features[i]
+	}
+
+	k := make([]float64, n)
+	errVal := y
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			k[i] += rls.p[i][j] * features[j]
+		}
+		k[i] /= denominator
+		errVal -= features[i] * rls.params[i]
+	}
+
+	for i := 0; i < n; i++ {
+		rls.params[i] += k[i] * errVal
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			rls.p[i][j] = (rls.p[i][j] - k[i]*phiTP[j]) / rls.lambda
+		}
+	}
+}
+
+func (rls *LoadAwareRLS) Predict(input, cached int) float64 {
+	return rls.PredictWithLoad(input, cached, Load{})
+}
+
+func (rls *LoadAwareRLS) Train(input, cached int, y float64) {
+	rls.TrainWithLoad(input, cached, Load{}, y)
+}
+
+func (rls *LoadAwareRLS) Clone() TTFTPrediction {
+	n := len(rls.params)
+	clone := &LoadAwareRLS{
+		params: make([]float64, n),
+		p:      make([][]float64, n),
+		lambda: rls.lambda,
+	}
+	copy(clone.params, rls.params)
+	for i := range rls.p {
+		clone.p[i] = make([]float64, n)
+		copy(clone.p[i], rls.p[i])
+	}
+	return clone
+}
+
+// Terms names the coefficients
+func (rls *LoadAwareRLS) Terms() []string {
+	return LoadAwareRLSTerms
+}
+
+// Coefficients returns a copy of the coefficients, see Terms for their names
+func (rls *LoadAwareRLS) Coefficients() []float64 {
+	params := make([]float64, len(rls.params))
+	copy(params, rls.params)
+	return params
+}
+
+// State returns a copy of the parameters and the covariance matrix
+func (rls *LoadAwareRLS) State() *RLSState {
+	clone := rls.Clone().(*LoadAwareRLS)
+	return &RLSState{
+		Kind:       KindLoadAware,
+		Params:     clone.params,
+		Covariance: clone.p,
+		Lambda:     clone.lambda,
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAwareRLS(t *testing.T) {
+	m := NewLoadAwareRLS(1)
+	r := rand.New(rand.NewSource(1))
+
+	ttft := func(input int, load Load) float64 {
+		return 50 + 0.2*float64(input) + 100*float64(load.QueueDepth) + 0.01*float64(load.BatchSize*input)
+	}
+	for i := 0; i < 500; i++ {
+		input := 100 + r.Intn(20000)
+		load := Load{QueueDepth: r.Intn(5), BatchSize: r.Intn(30)}
+		m.TrainWithLoad(input, 0, load, ttft(input, load))
+	}
+
+	for _, load := range []Load{{}, {QueueDepth: 2, BatchSize: 10}, {QueueDepth: 4, BatchSize: 25}} {
+		assert.InDelta(t, ttft(8000, load), m.PredictWithLoad(8000, 0, load), 1, "load %+v", load)
+	}
+	assert.Equal(t, m.PredictWithLoad(8000, 0, Load{}), m.Predict(8000, 0))
+	assert.Len(t, m.Coefficients(), len(m.Terms()))
+
+	restored, err := NewFromState(m.State())
+	assert.NoError(t, err)
+	assert.Equal(t, m, restored)
+
+	_, err = NewRLSFromState(m.State())
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	for _, kind := range []string{"", KindRLS, KindIsotonic, KindP90, KindLoadAware} {
+		p, err := New(kind)
+		assert.NoError(t, err, kind)
+		assert.NotNil(t, p, kind)
+	}
+	_, err := New("unknown")
+	assert.Error(t, err)
+}
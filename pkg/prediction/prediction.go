@@ -20,6 +20,22 @@ type TTFTPrediction interface {
 	Clone() TTFTPrediction
 }
 
+// Load is the load of the backend when the request is sent to it
+type Load struct {
+	// requests waiting in queue or prefilling
+	QueueDepth int
+	// requests being served, including the ones in queue
+	BatchSize int
+}
+
+// LoadAwarePrediction is implemented by the predictors which take the load of the backend into account.
+// Predict and Train of them are the same as calling PredictWithLoad and TrainWithLoad with zero load.
+type LoadAwarePrediction interface {
+	TTFTPrediction
+	PredictWithLoad(input, cached int, load Load) float64
+	TrainWithLoad(input, cached int, load Load, y float64)
+}
+
 type RLS struct {
 	params []float64   // Polynomial coefficients: [a, b, c, d, e, f]
 	p      [][]float64 // Covariance matrix
@@ -155,3 +171,18 @@ func (rls *RLS) Clone() TTFTPrediction {
 	}
 	return newRLS
 }
+
+// RLSTerms names the coefficients returned by RLS.Coefficients, in order
+var RLSTerms = []string{"input2", "cached2", "input_cached", "input", "cached", "const"}
+
+// Terms names the coefficients
+func (rls *RLS) Terms() []string {
+	return RLSTerms
+}
+
+// Coefficients returns a copy of the polynomial coefficients, see Terms for their names
+func (rls *RLS) Coefficients() []float64 {
+	params := make([]float64, rls.n)
+	copy(params, rls.params)
+	return params
+}
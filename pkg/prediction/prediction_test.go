@@ -183,3 +183,16 @@ func TestTTFTPrediction_Clone(t *testing.T) {
 		assert.Equal(t, v1, v2)
 	}
 }
+
+func TestCoefficients(t *testing.T) {
+	rls := NewRLS(1)
+	rls.params = []float64{1.0, 2.0, 3.0, 4.0, 5.0, 6.0}
+
+	coefs := rls.Coefficients()
+	assert.Equal(t, []float64{1.0, 2.0, 3.0, 4.0, 5.0, 6.0}, coefs)
+	assert.Len(t, RLSTerms, len(coefs))
+
+	// the returned slice is a copy
+	coefs[0] = 100
+	assert.Equal(t, 1.0, rls.params[0])
+}
@@ -0,0 +1,110 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import "math"
+
+const (
+	// the ratios between the TTFT and the prediction of the base predictor are counted
+	// in log-spaced buckets in [minRatio, maxRatio]
+	minRatio     = 0.1
+	maxRatio     = 10.0
+	ratioBuckets = 64
+	// the ratios are not counted before the base predictor learns enough samples, since it's far from the mean
+	quantileWarmup = 20
+)
+
+// Quantile predicts the q-quantile of TTFT, e.g. p90 for SLO use. It scales the prediction of the base
+// predictor, which predicts the mean, by the q-quantile of the observed ratios between TTFT and the mean.
+type Quantile struct {
+	base   TTFTPrediction
+	q      float64
+	lambda float64   // forgetting factor of the ratio counts
+	counts []float64 // decayed counts of the ratios
+	total  float64
+	// samples learned by the base predictor
+	samples int
+}
+
+func NewQuantile(base TTFTPrediction, q, lambda float64) *Quantile {
+	return &Quantile{
+		base:   base,
+		q:      q,
+		lambda: lambda,
+		counts: make([]float64, ratioBuckets),
+	}
+}
+
+func ratioBucket(ratio float64) int {
+	if ratio <= minRatio {
+		return 0
+	}
+	idx := int(math.Log(ratio/minRatio) / math.Log(maxRatio/minRatio) * ratioBuckets)
+	return min(idx, ratioBuckets-1)
+}
+
+// ratioUpperBound returns the upper bound of the bucket
+func ratioUpperBound(idx int) float64 {
+	return minRatio * math.Pow(maxRatio/minRatio, float64(idx+1)/ratioBuckets)
+}
+
+// ratio returns the q-quantile of the observed ratios, 1 if nothing observed
+func (m *Quantile) ratio() float64 {
+	if m.total == 0 {
+		return 1
+	}
+	target := m.q * m.total
+	sum := 0.0
+	for i, c := range m.counts {
+		sum += c
+		if sum >= target {
+			return ratioUpperBound(i)
+		}
+	}
+	return maxRatio
+}
+
+func (m *Quantile) Predict(input, cached int) float64 {
+	return m.base.Predict(input, cached) * m.ratio()
+}
+
+func (m *Quantile) Train(input, cached int, y float64) {
+	// the ratio is observed against the prediction before learning the sample
+	if mean := m.base.Predict(input, cached); mean > 0 && m.samples >= quantileWarmup {
+		if m.lambda < 1 {
+			for i := range m.counts {
+				m.counts[i] *= m.lambda
+			}
+			m.total *= m.lambda
+		}
+		m.counts[ratioBucket(y/mean)]++
+		m.total++
+	}
+	m.base.Train(input, cached, y)
+	m.samples++
+}
+
+func (m *Quantile) Clone() TTFTPrediction {
+	clone := &Quantile{
+		base:    m.base.Clone(),
+		q:       m.q,
+		lambda:  m.lambda,
+		counts:  make([]float64, len(m.counts)),
+		total:   m.total,
+		samples: m.samples,
+	}
+	copy(clone.counts, m.counts)
+	return clone
+}
@@ -0,0 +1,57 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantile(t *testing.T) {
+	m := NewQuantile(NewRLS(1), 0.9, 1)
+	r := rand.New(rand.NewSource(1))
+
+	// TTFT = 0.3 * input, with uniform noise in [0.8, 1.2]
+	for i := 0; i < 2000; i++ {
+		input := 1000 + r.Intn(10000)
+		m.Train(input, 0, 0.3*float64(input)*(0.8+0.4*r.Float64()))
+	}
+
+	mean := m.base.Predict(5000, 0)
+	assert.InDelta(t, 1500, mean, 50)
+	// the p90 of the noise is 1.16, the upper bound of the ratio bucket is at most 7.5% larger
+	ratio := m.Predict(5000, 0) / mean
+	assert.GreaterOrEqual(t, ratio, 1.15)
+	assert.LessOrEqual(t, ratio, 1.16*1.08)
+
+	clone := m.Clone().(*Quantile)
+	assert.Equal(t, m.Predict(5000, 0), clone.Predict(5000, 0))
+	clone.Train(5000, 0, 100000)
+	assert.NotEqual(t, m.total, clone.total)
+}
+
+func TestQuantileRatioBucket(t *testing.T) {
+	assert.Equal(t, 0, ratioBucket(0))
+	assert.Equal(t, 0, ratioBucket(minRatio))
+	assert.Equal(t, ratioBuckets-1, ratioBucket(maxRatio))
+	assert.Equal(t, ratioBuckets-1, ratioBucket(1000))
+	for _, ratio := range []float64{0.5, 1.5, 3} {
+		idx := ratioBucket(ratio)
+		assert.Less(t, ratio, ratioUpperBound(idx))
+		assert.GreaterOrEqual(t, ratio, ratioUpperBound(idx-1))
+	}
+}
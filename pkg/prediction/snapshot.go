@@ -0,0 +1,151 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const SnapshotVersion = 1
+
+// RLSState is the serializable state of RLS and LoadAwareRLS
+type RLSState struct {
+	// KindRLS or KindLoadAware, empty is treated as KindRLS
+	Kind       string      `json:"kind,omitempty"`
+	Params     []float64   `json:"params"`
+	Covariance [][]float64 `json:"covariance"`
+	Lambda     float64     `json:"lambda"`
+	// number of samples the model is trained with, for information only
+	Samples int64 `json:"samples,omitempty"`
+}
+
+// Snapshot is the file format of the persisted TTFT models, keyed by model name
+type Snapshot struct {
+	Version int                  `json:"version"`
+	Models  map[string]*RLSState `json:"models"`
+}
+
+// State returns a copy of the parameters and the covariance matrix
+func (rls *RLS) State() *RLSState {
+	clone := rls.Clone().(*RLS)
+	return &RLSState{
+		Kind:       KindRLS,
+		Params:     clone.params,
+		Covariance: clone.p,
+		Lambda:     clone.lambda,
+	}
+}
+
+// NewRLSFromState restores RLS from the state, the state is copied
+func NewRLSFromState(state *RLSState) (*RLS, error) {
+	if state.Kind != "" && state.Kind != KindRLS {
+		return nil, fmt.Errorf("invalid kind %s, expected %s", state.Kind, KindRLS)
+	}
+	if err := validateState(state, len(RLSTerms)); err != nil {
+		return nil, err
+	}
+	rls := NewRLS(state.Lambda)
+	restoreState(state, rls.params, rls.p)
+	return rls, nil
+}
+
+// NewLoadAwareRLSFromState restores LoadAwareRLS from the state, the state is copied
+func NewLoadAwareRLSFromState(state *RLSState) (*LoadAwareRLS, error) {
+	if state.Kind != KindLoadAware {
+		return nil, fmt.Errorf("invalid kind %s, expected %s", state.Kind, KindLoadAware)
+	}
+	if err := validateState(state, len(LoadAwareRLSTerms)); err != nil {
+		return nil, err
+	}
+	rls := NewLoadAwareRLS(state.Lambda)
+	restoreState(state, rls.params, rls.p)
+	return rls, nil
+}
+
+// NewFromState restores the predictor of the kind of the state
+func NewFromState(state *RLSState) (TTFTPrediction, error) {
+	if state.Kind == KindLoadAware {
+		return NewLoadAwareRLSFromState(state)
+	}
+	return NewRLSFromState(state)
+}
+
+func validateState(state *RLSState, n int) error {
+	if state.Lambda <= 0 || state.Lambda > 1 {
+		return fmt.Errorf("invalid lambda %v, should be in (0, 1]", state.Lambda)
+	}
+	if len(state.Params) != n {
+		return fmt.Errorf("invalid params length %d, expected %d", len(state.Params), n)
+	}
+	if len(state.Covariance) != n {
+		return fmt.Errorf("invalid covariance rows %d, expected %d", len(state.Covariance), n)
+	}
+	for i, row := range state.Covariance {
+		if len(row) != n {
+			return fmt.Errorf("invalid covariance row %d length %d, expected %d", i, len(row), n)
+		}
+	}
+	return nil
+}
+
+func restoreState(state *RLSState, params []float64, p [][]float64) {
+	copy(params, state.Params)
+	for i, row := range state.Covariance {
+		copy(p[i], row)
+	}
+}
+
+// LoadSnapshot reads the snapshot from the file
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d in %s", snapshot.Version, path)
+	}
+	return &snapshot, nil
+}
+
+// SaveSnapshot writes the snapshot to the file. It writes a temporary file and renames it,
+// so a crash during writing never leaves a truncated snapshot.
+func SaveSnapshot(path string, snapshot *Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,92 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prediction
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRLSState(t *testing.T) {
+	rls := NewRLS(0.99)
+	rls.Train(1024, 512, 300)
+	rls.Train(4096, 0, 1200)
+
+	restored, err := NewRLSFromState(rls.State())
+	assert.NoError(t, err)
+	assert.Equal(t, rls, restored)
+
+	// the restored model keeps learning the same way
+	rls.Train(2048, 1024, 600)
+	restored.Train(2048, 1024, 600)
+	assert.Equal(t, rls.Predict(3000, 1000), restored.Predict(3000, 1000))
+}
+
+func TestNewRLSFromStateInvalid(t *testing.T) {
+	valid := func() *RLSState {
+		return NewRLS(1).State()
+	}
+	cases := []struct {
+		name   string
+		modify func(s *RLSState)
+	}{
+		{"zero lambda", func(s *RLSState) { s.Lambda = 0 }},
+		{"lambda larger than 1", func(s *RLSState) { s.Lambda = 1.1 }},
+		{"short params", func(s *RLSState) { s.Params = s.Params[:5] }},
+		{"short covariance", func(s *RLSState) { s.Covariance = s.Covariance[:5] }},
+		{"short covariance row", func(s *RLSState) { s.Covariance[2] = s.Covariance[2][:5] }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			state := valid()
+			c.modify(state)
+			_, err := NewRLSFromState(state)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSnapshotFile(t *testing.T) {
+	rls := NewRLS(1)
+	rls.Train(1024, 0, 300)
+	state := rls.State()
+	state.Samples = 1
+
+	path := filepath.Join(t.TempDir(), "ttft.json")
+	snapshot := &Snapshot{
+		Version: SnapshotVersion,
+		Models:  map[string]*RLSState{"model1": state},
+	}
+	assert.NoError(t, SaveSnapshot(path, snapshot))
+
+	loaded, err := LoadSnapshot(path)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, loaded)
+
+	// no temporary file is left
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = LoadSnapshot(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"version":2,"models":{}}`), 0644))
+	_, err = LoadSnapshot(path)
+	assert.ErrorContains(t, err, "unsupported snapshot version")
+}
@@ -0,0 +1,143 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prom
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	llmLabels = []string{"model", "cluster", "backend", "stream"}
+
+	// LLMRequestsTotal is a prometheus metric that counts the LLM requests
+	LLMRequestsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_llm_requests_total",
+			Help: "Total number of LLM requests",
+		},
+		llmLabels,
+	)
+
+	// LLMErrorsTotal is a prometheus metric that counts the failed LLM requests by error type
+	LLMErrorsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_llm_errors_total",
+			Help: "Total number of failed LLM requests",
+		},
+		[]string{"model", "cluster", "backend", "stream", "type"},
+	)
+
+	// LLMTTFT is a prometheus metric that counts the time to first token
+	LLMTTFT = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "aigw_llm_ttft_ms",
+			Help: "Histogram of the time from sending the request to receiving the first token",
+			// [10ms, 20ms, 40ms, ..., 81.92s]
+			Buckets: prometheus.ExponentialBuckets(10, 2.0, 14),
+		},
+		llmLabels,
+	)
+
+	// LLMTPOT is a prometheus metric that counts the time per output token of the streaming requests
+	LLMTPOT = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "aigw_llm_tpot_ms",
+			Help: "Histogram of the time per output token after the first token",
+			// [1ms, 2ms, 4ms, ..., 2.048s]
+			Buckets: prometheus.ExponentialBuckets(1, 2.0, 12),
+		},
+		[]string{"model", "cluster", "backend"},
+	)
+
+	// LLMRequestDuration is a prometheus metric that counts the time from sending the request to the last token
+	LLMRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "aigw_llm_request_duration_ms",
+			Help: "Histogram of the time from sending the request to receiving the last token",
+			// [50ms, 100ms, 200ms, ..., 409.6s]
+			Buckets: prometheus.ExponentialBuckets(50, 2.0, 14),
+		},
+		llmLabels,
+	)
+
+	// LLMTokensTotal is a prometheus metric that counts the tokens by type: prompt, completion and cached
+	LLMTokensTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_llm_tokens_total",
+			Help: "Total number of tokens of LLM requests",
+		},
+		[]string{"model", "cluster", "backend", "type"},
+	)
+)
+
+// LLMRequest is the result of an LLM request, the timestamps are in microseconds and 0 if not received
+type LLMRequest struct {
+	Model   string
+	Cluster string
+	Backend string
+	Stream  bool
+	// empty if the request succeeded
+	ErrorType string
+
+	SendFinishTimestamp int64
+	FirstTokenTimestamp int64
+	LastTokenTimestamp  int64
+
+	PromptTokens     int64
+	CompletionTokens int64
+	CachedTokens     int64
+}
+
+func microsToMillis(us int64) float64 {
+	return float64(us) / 1000
+}
+
+// ObserveLLMRequest records the metrics of a finished LLM request
+func ObserveLLMRequest(r *LLMRequest) {
+	stream := strconv.FormatBool(r.Stream)
+	LLMRequestsTotal.WithLabelValues(r.Model, r.Cluster, r.Backend, stream).Inc()
+	if r.ErrorType != "" {
+		LLMErrorsTotal.WithLabelValues(r.Model, r.Cluster, r.Backend, stream, r.ErrorType).Inc()
+	}
+
+	if r.SendFinishTimestamp > 0 {
+		if r.FirstTokenTimestamp >= r.SendFinishTimestamp {
+			LLMTTFT.WithLabelValues(r.Model, r.Cluster, r.Backend, stream).
+				Observe(microsToMillis(r.FirstTokenTimestamp - r.SendFinishTimestamp))
+		}
+		if r.LastTokenTimestamp >= r.SendFinishTimestamp {
+			LLMRequestDuration.WithLabelValues(r.Model, r.Cluster, r.Backend, stream).
+				Observe(microsToMillis(r.LastTokenTimestamp - r.SendFinishTimestamp))
+		}
+	}
+	// the first token is excluded since it's the prefill time, and the non-streaming response only has one chunk
+	if r.Stream && r.CompletionTokens > 1 && r.FirstTokenTimestamp > 0 && r.LastTokenTimestamp > r.FirstTokenTimestamp {
+		LLMTPOT.WithLabelValues(r.Model, r.Cluster, r.Backend).
+			Observe(microsToMillis(r.LastTokenTimestamp-r.FirstTokenTimestamp) / float64(r.CompletionTokens-1))
+	}
+
+	if r.PromptTokens > 0 {
+		LLMTokensTotal.WithLabelValues(r.Model, r.Cluster, r.Backend, "prompt").Add(float64(r.PromptTokens))
+	}
+	if r.CompletionTokens > 0 {
+		LLMTokensTotal.WithLabelValues(r.Model, r.Cluster, r.Backend, "completion").Add(float64(r.CompletionTokens))
+	}
+	if r.CachedTokens > 0 {
+		LLMTokensTotal.WithLabelValues(r.Model, r.Cluster, r.Backend, "cached").Add(float64(r.CachedTokens))
+	}
+}
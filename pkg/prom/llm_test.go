@@ -0,0 +1,115 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prom
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// histogram returns the sample count and sum of the histogram
+func histogram(t *testing.T, h *prometheus.HistogramVec, labels ...string) (uint64, float64) {
+	m := &dto.Metric{}
+	assert.NoError(t, h.WithLabelValues(labels...).(prometheus.Metric).Write(m))
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
+
+func TestObserveLLMRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       LLMRequest
+		wantTTFT  float64
+		wantTPOT  float64
+		wantDur   float64
+		wantError float64
+	}{
+		{
+			name: "streaming",
+			req: LLMRequest{
+				Model:               "stream-model",
+				Stream:              true,
+				SendFinishTimestamp: 1_000_000,
+				FirstTokenTimestamp: 1_200_000,
+				LastTokenTimestamp:  2_200_000,
+				PromptTokens:        100,
+				CompletionTokens:    11,
+				CachedTokens:        50,
+			},
+			wantTTFT: 200,
+			wantTPOT: 100,
+			wantDur:  1200,
+		},
+		{
+			name: "non-streaming",
+			req: LLMRequest{
+				Model:               "non-stream-model",
+				SendFinishTimestamp: 1_000_000,
+				FirstTokenTimestamp: 2_000_000,
+				LastTokenTimestamp:  2_000_000,
+				PromptTokens:        100,
+				CompletionTokens:    10,
+			},
+			wantTTFT: 1000,
+			wantDur:  1000,
+		},
+		{
+			name: "upstream reset",
+			req: LLMRequest{
+				Model:               "reset-model",
+				Stream:              true,
+				ErrorType:           "upstream_reset",
+				SendFinishTimestamp: 1_000_000,
+			},
+			wantError: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.req
+			ObserveLLMRequest(&r)
+			stream := strconv.FormatBool(r.Stream)
+
+			assert.Equal(t, 1.0, testutil.ToFloat64(LLMRequestsTotal.WithLabelValues(r.Model, "", "", stream)))
+			assert.Equal(t, tt.wantError, testutil.ToFloat64(LLMErrorsTotal.WithLabelValues(r.Model, "", "", stream, "upstream_reset")))
+
+			for _, c := range []struct {
+				h    *prometheus.HistogramVec
+				lbs  []string
+				want float64
+			}{
+				{LLMTTFT, []string{r.Model, "", "", stream}, tt.wantTTFT},
+				{LLMRequestDuration, []string{r.Model, "", "", stream}, tt.wantDur},
+				{LLMTPOT, []string{r.Model, "", ""}, tt.wantTPOT},
+			} {
+				count, sum := histogram(t, c.h, c.lbs...)
+				if c.want == 0 {
+					assert.Equal(t, uint64(0), count)
+				} else {
+					assert.Equal(t, uint64(1), count)
+					assert.InDelta(t, c.want, sum, 0.001)
+				}
+			}
+
+			assert.Equal(t, float64(r.PromptTokens), testutil.ToFloat64(LLMTokensTotal.WithLabelValues(r.Model, "", "", "prompt")))
+			assert.Equal(t, float64(r.CompletionTokens), testutil.ToFloat64(LLMTokensTotal.WithLabelValues(r.Model, "", "", "completion")))
+			assert.Equal(t, float64(r.CachedTokens), testutil.ToFloat64(LLMTokensTotal.WithLabelValues(r.Model, "", "", "cached")))
+		})
+	}
+}
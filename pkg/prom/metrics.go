@@ -86,6 +86,150 @@ var (
 		[]string{"host"},
 	)
 
+	// OutlierEjectedHosts is a prometheus metric that shows whether the host is ejected by outlier detection
+	OutlierEjectedHosts = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aigw_outlier_ejected_host",
+			Help: "Whether the host is ejected by outlier detection. 0: Not ejected, 1: Ejected.",
+		},
+		[]string{"cluster", "host"},
+	)
+
+	// OutlierEjectionsTotal is a prometheus metric that counts the ejections of outlier detection
+	OutlierEjectionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_outlier_ejections_total",
+			Help: "Total number of host ejections by outlier detection",
+		},
+		[]string{"cluster", "reason"},
+	)
+
+	// HealthCheckHostHealthy is a prometheus metric that shows the active health check result of each host
+	HealthCheckHostHealthy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aigw_healthcheck_host_healthy",
+			Help: "The active health check result of each host. 0: Unhealthy, 1: Healthy.",
+		},
+		[]string{"cluster", "host"},
+	)
+
+	// HealthCheckTransitionsTotal is a prometheus metric that counts the health transitions of the hosts
+	HealthCheckTransitionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_healthcheck_transitions_total",
+			Help: "Total number of host health transitions by active health check",
+		},
+		[]string{"cluster", "state"},
+	)
+
+	// AdmissionQueueSize is a prometheus metric that counts the requests waiting in the admission queue of each cluster
+	AdmissionQueueSize = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aigw_admission_queue_size",
+			Help: "Number of requests waiting in the admission queue",
+		},
+		[]string{"cluster"},
+	)
+
+	// AdmissionRejectedTotal is a prometheus metric that counts the requests rejected by admission control
+	AdmissionRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_admission_rejected_total",
+			Help: "Total number of requests rejected by admission control",
+		},
+		[]string{"cluster", "reason"},
+	)
+
+	// AdmissionWaitDuration is a prometheus metric that counts the time requests wait in the admission queue
+	AdmissionWaitDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "aigw_admission_wait_duration_ms",
+			Help: "Histogram of the time requests wait in the admission queue",
+			// [10ms, 20ms, 40ms, ..., 20.48s]
+			Buckets: prometheus.ExponentialBuckets(10, 2.0, 12),
+		},
+		[]string{"cluster"},
+	)
+
+	// TokenRateLimitRejectedTotal is a prometheus metric that counts the requests rejected by token rate limit
+	TokenRateLimitRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_token_ratelimit_rejected_total",
+			Help: "Total number of requests rejected by token rate limit",
+		},
+		[]string{"type"},
+	)
+
+	// LLMLogDroppedTotal is a prometheus metric that counts the LLM log records dropped by each sink
+	LLMLogDroppedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_llm_log_dropped_total",
+			Help: "Total number of LLM log records dropped",
+		},
+		[]string{"sink", "reason"},
+	)
+
+	// LLMLogQueueSize is a prometheus metric that counts the LLM log records waiting in the queue of each sink
+	LLMLogQueueSize = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aigw_llm_log_queue_size",
+			Help: "Number of LLM log records waiting in the queue",
+		},
+		[]string{"sink"},
+	)
+
+	// LLMLogWriteDuration is a prometheus metric that counts the duration of writing a batch to the sink
+	LLMLogWriteDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "aigw_llm_log_write_duration_ms",
+			Help: "Histogram of the time writing a batch of LLM log records to the sink",
+			// [0.1ms, 0.2ms, 0.4ms, ..., 3.2768s]
+			Buckets: prometheus.ExponentialBuckets(0.1, 2.0, 16),
+		},
+		[]string{"sink"},
+	)
+
+	// LBCandidateCount is a prometheus metric that counts the hosts considered by the load balancer
+	LBCandidateCount = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "aigw_lb_candidate_count",
+			Help: "Histogram of the number of hosts considered by the load balancer",
+			// [1, 2, 4, ..., 512]
+			Buckets: prometheus.ExponentialBuckets(1, 2.0, 10),
+		},
+		[]string{"cluster"},
+	)
+
+	// LBScoreSpread is a prometheus metric that counts the difference between the highest and lowest host score
+	LBScoreSpread = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "aigw_lb_score_spread",
+			Help: "Histogram of the difference between the highest and the lowest host score",
+			// [0.1, 0.2, 0.4, ..., 51.2]
+			Buckets: prometheus.ExponentialBuckets(0.1, 2.0, 10),
+		},
+		[]string{"cluster"},
+	)
+
+	// LBChosenCacheHitRatio is a prometheus metric that counts the kv cache hit ratio of the chosen host
+	LBChosenCacheHitRatio = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "aigw_lb_chosen_cache_hit_ratio",
+			Help:    "Histogram of the kv cache hit ratio of the chosen host",
+			Buckets: prometheus.LinearBuckets(0, 0.1, 11),
+		},
+		[]string{"cluster"},
+	)
+
+	// LBFallbackRandomTotal is a prometheus metric that counts the random choices due to the load metrics unavailable
+	LBFallbackRandomTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_lb_fallback_random_total",
+			Help: "Total number of random choices since the load metrics from metadata center are unavailable",
+		},
+		[]string{"cluster"},
+	)
+
 	// MetacenterRequestDuration is a prometheus metric that counts the duration of requests to aigwmetacenter
 	MetacenterRequestDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -109,6 +253,32 @@ var (
 		},
 		[]string{"instance", "method", "path"},
 	)
+
+	// TTFTModelCoefficient is a prometheus metric that exports the learned coefficients of the TTFT predictor of each model
+	TTFTModelCoefficient = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "aigw_ttft_model_coefficient",
+			Help: "Coefficients of the TTFT predictor trained from the observed latencies",
+		},
+		[]string{"model", "term"},
+	)
+
+	// TTFTTrainSamplesTotal is a prometheus metric that counts the samples used to train the TTFT predictor
+	TTFTTrainSamplesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aigw_ttft_train_samples_total",
+			Help: "Total number of samples used to train the TTFT predictor",
+		},
+		[]string{"model"},
+	)
+
+	// TTFTTrainDroppedTotal is a prometheus metric that counts the samples dropped because the training queue is full
+	TTFTTrainDroppedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "aigw_ttft_train_dropped_total",
+			Help: "Total number of TTFT samples dropped because the training queue is full",
+		},
+	)
 )
 
 func UpdateBreakerState(host, currentState string) {
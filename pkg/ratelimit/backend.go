@@ -0,0 +1,90 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit provides the quota backends used by the rate limit plugins.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const LocalBackendName = "local"
+
+// Result is the result of taking quota from a bucket
+type Result struct {
+	Allowed bool
+	// RetryAfter is the estimated duration until the quota is enough, only set when not allowed
+	RetryAfter time.Duration
+}
+
+// Backend stores the per-minute quotas. Each bucket is identified by the key, and holds up to limit quota
+// which is refilled continuously in a minute. The local backend only limits the requests of the current
+// gateway replica, a shared backend is required to enforce the quotas across replicas.
+type Backend interface {
+	// Take takes n quota from the bucket, nothing is taken when not allowed
+	Take(ctx context.Context, key string, limit uint64, n uint64) (Result, error)
+	// Adjust consumes delta quota from the bucket without checking the limit, negative delta gives the quota back.
+	// It's used to reconcile the estimated quota with the actual usage.
+	Adjust(ctx context.Context, key string, limit uint64, delta int64) error
+}
+
+type BackendFactory func() (Backend, error)
+
+var (
+	backendLock      sync.Mutex
+	backendFactories = map[string]BackendFactory{}
+	backends         = map[string]Backend{}
+)
+
+func init() {
+	RegisterBackend(LocalBackendName, func() (Backend, error) {
+		return NewLocalBackend(), nil
+	})
+}
+
+// RegisterBackend registers a backend factory, the backend is created once when it's used at the first time
+func RegisterBackend(name string, factory BackendFactory) {
+	backendLock.Lock()
+	defer backendLock.Unlock()
+
+	backendFactories[name] = factory
+	delete(backends, name)
+}
+
+// GetBackend returns the backend with the given name, the local backend is returned when name is empty
+func GetBackend(name string) (Backend, error) {
+	if name == "" {
+		name = LocalBackendName
+	}
+
+	backendLock.Lock()
+	defer backendLock.Unlock()
+
+	if b, ok := backends[name]; ok {
+		return b, nil
+	}
+	factory, ok := backendFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown rate limit backend: %s", name)
+	}
+	b, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate limit backend %s: %w", name, err)
+	}
+	backends[name] = b
+	return b, nil
+}
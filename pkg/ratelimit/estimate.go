@@ -0,0 +1,98 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// tokens of the role and separators in each chat message
+const messageOverheadTokens = 4
+
+type estimateMessage struct {
+	Content json.RawMessage `json:"content"`
+}
+
+type estimateContentPart struct {
+	Text string `json:"text"`
+}
+
+type estimateRequest struct {
+	Model    string            `json:"model"`
+	Messages []estimateMessage `json:"messages"`
+	Prompt   json.RawMessage   `json:"prompt"`
+}
+
+// EstimateTextTokens estimates the tokens of the text without tokenizer:
+// about 4 ASCII characters per token, and 1 token per non-ASCII character, e.g. CJK.
+func EstimateTextTokens(text string) uint64 {
+	var ascii, others uint64
+	for i := 0; i < len(text); {
+		if text[i] < utf8.RuneSelf {
+			ascii++
+			i++
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		others++
+		i += size
+	}
+	return (ascii+3)/4 + others
+}
+
+// estimateRawTokens estimates the tokens of a string, an array of strings or an array of content parts
+func estimateRawTokens(raw json.RawMessage) uint64 {
+	if len(raw) == 0 {
+		return 0
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return EstimateTextTokens(s)
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return 0
+	}
+	var tokens uint64
+	for _, part := range parts {
+		var p estimateContentPart
+		if err := json.Unmarshal(part, &p); err == nil && p.Text != "" {
+			tokens += EstimateTextTokens(p.Text)
+			continue
+		}
+		if err := json.Unmarshal(part, &s); err == nil {
+			tokens += EstimateTextTokens(s)
+		}
+	}
+	return tokens
+}
+
+// EstimateRequest parses the model and estimates the input tokens of the OpenAI chat completion or completion request
+func EstimateRequest(body []byte) (string, uint64, error) {
+	var req estimateRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", 0, err
+	}
+
+	var tokens uint64
+	for _, msg := range req.Messages {
+		tokens += messageOverheadTokens + estimateRawTokens(msg.Content)
+	}
+	tokens += estimateRawTokens(req.Prompt)
+	return req.Model, tokens, nil
+}
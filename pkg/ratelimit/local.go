@@ -0,0 +1,107 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// idle buckets are full after a minute, they are removed after bucketIdleTimeout to bound the memory
+const bucketIdleTimeout = 2 * time.Minute
+
+type bucket struct {
+	tokens   float64
+	lastTime time.Time
+}
+
+// refill must be called with lock held
+func (b *bucket) refill(limit uint64, now time.Time) {
+	rate := float64(limit) / 60
+	b.tokens = math.Min(float64(limit), b.tokens+rate*now.Sub(b.lastTime).Seconds())
+	b.lastTime = now
+}
+
+// LocalBackend is the token bucket backend in memory
+type LocalBackend struct {
+	now func() time.Time
+
+	lock      sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewLocalBackend() *LocalBackend {
+	return &LocalBackend{
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// getBucket must be called with lock held
+func (l *LocalBackend) getBucket(key string, limit uint64, now time.Time) *bucket {
+	if now.Sub(l.lastSweep) > bucketIdleTimeout {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastTime) > bucketIdleTimeout {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{
+			tokens:   float64(limit),
+			lastTime: now,
+		}
+		l.buckets[key] = b
+		return b
+	}
+	b.refill(limit, now)
+	return b
+}
+
+func (l *LocalBackend) Take(_ context.Context, key string, limit uint64, n uint64) (Result, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	b := l.getBucket(key, limit, now)
+	need := float64(n)
+	// a request larger than the limit is allowed when the bucket is full, otherwise it will never be allowed
+	if b.tokens >= need || (need > float64(limit) && b.tokens >= float64(limit)) {
+		b.tokens -= need
+		return Result{Allowed: true}, nil
+	}
+
+	rate := float64(limit) / 60
+	wait := math.Min(need, float64(limit)) - b.tokens
+	return Result{
+		Allowed:    false,
+		RetryAfter: time.Duration(wait / rate * float64(time.Second)),
+	}, nil
+}
+
+func (l *LocalBackend) Adjust(_ context.Context, key string, limit uint64, delta int64) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	b := l.getBucket(key, limit, l.now())
+	b.tokens = math.Min(float64(limit), b.tokens-float64(delta))
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalBackend(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewLocalBackend()
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 60 per minute, 1 per second
+	for i := 0; i < 60; i++ {
+		res, err := l.Take(ctx, "k", 60, 1)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+	}
+	res, _ := l.Take(ctx, "k", 60, 1)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+
+	// other key has its own bucket
+	res, _ = l.Take(ctx, "other", 60, 1)
+	assert.True(t, res.Allowed)
+
+	now = now.Add(2 * time.Second)
+	res, _ = l.Take(ctx, "k", 60, 2)
+	assert.True(t, res.Allowed)
+
+	// give back the quota
+	assert.NoError(t, l.Adjust(ctx, "k", 60, -10))
+	res, _ = l.Take(ctx, "k", 60, 10)
+	assert.True(t, res.Allowed)
+
+	// consume more than estimated, the bucket is in debt
+	assert.NoError(t, l.Adjust(ctx, "k", 60, 30))
+	res, _ = l.Take(ctx, "k", 60, 1)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 31*time.Second, res.RetryAfter)
+}
+
+func TestLocalBackendLargeRequest(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewLocalBackend()
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	res, _ := l.Take(ctx, "k", 100, 200)
+	assert.True(t, res.Allowed)
+	res, _ = l.Take(ctx, "k", 100, 200)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 120*time.Second, res.RetryAfter)
+}
+
+func TestLocalBackendSweep(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewLocalBackend()
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, _ = l.Take(ctx, "a", 10, 1)
+	now = now.Add(3 * time.Minute)
+	_, _ = l.Take(ctx, "b", 10, 1)
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestGetBackend(t *testing.T) {
+	b, err := GetBackend("")
+	assert.NoError(t, err)
+	local, err := GetBackend(LocalBackendName)
+	assert.NoError(t, err)
+	assert.Same(t, b, local)
+
+	_, err = GetBackend("unknown")
+	assert.Error(t, err)
+}
+
+func TestEstimateRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		model  string
+		tokens uint64
+		err    bool
+	}{
+		{
+			name:   "chat",
+			body:   `{"model":"qwen3","messages":[{"role":"user","content":"hello world!"}]}`,
+			model:  "qwen3",
+			tokens: messageOverheadTokens + 3,
+		},
+		{
+			name:   "content parts",
+			body:   `{"model":"qwen3","messages":[{"role":"user","content":[{"type":"text","text":"abcd"},{"type":"image_url","image_url":{"url":"x"}}]}]}`,
+			model:  "qwen3",
+			tokens: messageOverheadTokens + 1,
+		},
+		{
+			name:   "non ascii",
+			body:   `{"model":"qwen3","messages":[{"role":"user","content":"你好"}]}`,
+			model:  "qwen3",
+			tokens: messageOverheadTokens + 2,
+		},
+		{
+			name:   "completion",
+			body:   `{"model":"qwen3","prompt":["abcdefgh","abcd"]}`,
+			model:  "qwen3",
+			tokens: 3,
+		},
+		{
+			name: "invalid",
+			body: `{"model":`,
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, tokens, err := EstimateRequest([]byte(tt.body))
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.model, model)
+			assert.Equal(t, tt.tokens, tokens)
+		})
+	}
+}
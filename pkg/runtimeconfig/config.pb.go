@@ -0,0 +1,551 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v4.24.4
+// source: pkg/runtimeconfig/config.proto
+
+package runtimeconfig
+
+import (
+	_ "github.com/envoyproxy/protoc-gen-validate/validate"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	wrapperspb "google.golang.org/protobuf/types/known/wrapperspb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RuntimeConfig is the gateway-wide settings which can be changed without restarting Envoy.
+// The unset fields fall back to the env variables, then the defaults.
+// The settings which are not here are read from the env variables at startup.
+type RuntimeConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	LoadBalancer   *LoadBalancer   `protobuf:"bytes,1,opt,name=load_balancer,json=loadBalancer,proto3" json:"load_balancer,omitempty"`
+	MetadataCenter *MetadataCenter `protobuf:"bytes,2,opt,name=metadata_center,json=metadataCenter,proto3" json:"metadata_center,omitempty"`
+	Transcoder     *Transcoder     `protobuf:"bytes,3,opt,name=transcoder,proto3" json:"transcoder,omitempty"`
+	Prediction     *Prediction     `protobuf:"bytes,4,opt,name=prediction,proto3" json:"prediction,omitempty"`
+}
+
+func (x *RuntimeConfig) Reset() {
+	*x = RuntimeConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_runtimeconfig_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RuntimeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuntimeConfig) ProtoMessage() {}
+
+func (x *RuntimeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_runtimeconfig_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuntimeConfig.ProtoReflect.Descriptor instead.
+func (*RuntimeConfig) Descriptor() ([]byte, []int) {
+	return file_pkg_runtimeconfig_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RuntimeConfig) GetLoadBalancer() *LoadBalancer {
+	if x != nil {
+		return x.LoadBalancer
+	}
+	return nil
+}
+
+func (x *RuntimeConfig) GetMetadataCenter() *MetadataCenter {
+	if x != nil {
+		return x.MetadataCenter
+	}
+	return nil
+}
+
+func (x *RuntimeConfig) GetTranscoder() *Transcoder {
+	if x != nil {
+		return x.Transcoder
+	}
+	return nil
+}
+
+func (x *RuntimeConfig) GetPrediction() *Prediction {
+	if x != nil {
+		return x.Prediction
+	}
+	return nil
+}
+
+type LoadBalancer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// percentage of the top ranked hosts to choose from, env HTNN_AIGW_INFER_LB_CANDIDATE_PERCENT, default to 5
+	CandidatePercent *wrapperspb.UInt32Value `protobuf:"bytes,1,opt,name=candidate_percent,json=candidatePercent,proto3" json:"candidate_percent,omitempty"`
+}
+
+func (x *LoadBalancer) Reset() {
+	*x = LoadBalancer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_runtimeconfig_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LoadBalancer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoadBalancer) ProtoMessage() {}
+
+func (x *LoadBalancer) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_runtimeconfig_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoadBalancer.ProtoReflect.Descriptor instead.
+func (*LoadBalancer) Descriptor() ([]byte, []int) {
+	return file_pkg_runtimeconfig_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LoadBalancer) GetCandidatePercent() *wrapperspb.UInt32Value {
+	if x != nil {
+		return x.CandidatePercent
+	}
+	return nil
+}
+
+type MetadataCenter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// timeout of querying the load, env AIGW_METADATA_CENTER_FETCH_METRIC_TIMEOUT in ms, default to 100ms
+	FetchMetricTimeout *durationpb.Duration `protobuf:"bytes,1,opt,name=fetch_metric_timeout,json=fetchMetricTimeout,proto3" json:"fetch_metric_timeout,omitempty"`
+	// timeout of querying the KV cache, env AIGW_META_DATA_CACHE_FETCH_TIMEOUT in ms, default to 100ms
+	FetchCacheTimeout *durationpb.Duration `protobuf:"bytes,2,opt,name=fetch_cache_timeout,json=fetchCacheTimeout,proto3" json:"fetch_cache_timeout,omitempty"`
+	// timeout of updating the load in the async queue, env AIGW_METADATA_CENTER_UPDATE_STATS_TIMEOUT in ms, default to 100ms
+	UpdateStatsTimeout *durationpb.Duration `protobuf:"bytes,3,opt,name=update_stats_timeout,json=updateStatsTimeout,proto3" json:"update_stats_timeout,omitempty"`
+	// retries of the tasks in the async queue, env AIGW_METADATA_CENTER_MAX_RETRY, default to 0
+	MaxRetries *wrapperspb.UInt32Value `protobuf:"bytes,4,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	// other hosts to try when the request to a host fails, env AIGW_META_MAX_FAILOVER_RETRY, default to 1
+	MaxFailoverRetries *wrapperspb.UInt32Value `protobuf:"bytes,5,opt,name=max_failover_retries,json=maxFailoverRetries,proto3" json:"max_failover_retries,omitempty"`
+}
+
+func (x *MetadataCenter) Reset() {
+	*x = MetadataCenter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_runtimeconfig_config_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetadataCenter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataCenter) ProtoMessage() {}
+
+func (x *MetadataCenter) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_runtimeconfig_config_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataCenter.ProtoReflect.Descriptor instead.
+func (*MetadataCenter) Descriptor() ([]byte, []int) {
+	return file_pkg_runtimeconfig_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MetadataCenter) GetFetchMetricTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.FetchMetricTimeout
+	}
+	return nil
+}
+
+func (x *MetadataCenter) GetFetchCacheTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.FetchCacheTimeout
+	}
+	return nil
+}
+
+func (x *MetadataCenter) GetUpdateStatsTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.UpdateStatsTimeout
+	}
+	return nil
+}
+
+func (x *MetadataCenter) GetMaxRetries() *wrapperspb.UInt32Value {
+	if x != nil {
+		return x.MaxRetries
+	}
+	return nil
+}
+
+func (x *MetadataCenter) GetMaxFailoverRetries() *wrapperspb.UInt32Value {
+	if x != nil {
+		return x.MaxFailoverRetries
+	}
+	return nil
+}
+
+type Transcoder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// return the reasoning content in a separate field, env AIGW_AI_PROXY_SPLIT_REASONING, default to false
+	SplitReasoning *wrapperspb.BoolValue `protobuf:"bytes,1,opt,name=split_reasoning,json=splitReasoning,proto3" json:"split_reasoning,omitempty"`
+}
+
+func (x *Transcoder) Reset() {
+	*x = Transcoder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_runtimeconfig_config_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Transcoder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transcoder) ProtoMessage() {}
+
+func (x *Transcoder) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_runtimeconfig_config_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transcoder.ProtoReflect.Descriptor instead.
+func (*Transcoder) Descriptor() ([]byte, []int) {
+	return file_pkg_runtimeconfig_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Transcoder) GetSplitReasoning() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.SplitReasoning
+	}
+	return nil
+}
+
+type Prediction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// predict TTFT by the moving average instead of the predictors, env AIGW_USE_MOVING_AVERAGE, default to false
+	UseMovingAverage *wrapperspb.BoolValue `protobuf:"bytes,1,opt,name=use_moving_average,json=useMovingAverage,proto3" json:"use_moving_average,omitempty"`
+}
+
+func (x *Prediction) Reset() {
+	*x = Prediction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_runtimeconfig_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Prediction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prediction) ProtoMessage() {}
+
+func (x *Prediction) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_runtimeconfig_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prediction.ProtoReflect.Descriptor instead.
+func (*Prediction) Descriptor() ([]byte, []int) {
+	return file_pkg_runtimeconfig_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Prediction) GetUseMovingAverage() *wrapperspb.BoolValue {
+	if x != nil {
+		return x.UseMovingAverage
+	}
+	return nil
+}
+
+var File_pkg_runtimeconfig_config_proto protoreflect.FileDescriptor
+
+var file_pkg_runtimeconfig_config_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x70, 0x6b, 0x67, 0x2f, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x12, 0x61, 0x69, 0x67, 0x77, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x77, 0x72, 0x61, 0x70, 0x70, 0x65, 0x72, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76,
+	0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa3, 0x02,
+	0x0a, 0x0d, 0x52, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x45, 0x0a, 0x0d, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x61, 0x69, 0x67, 0x77, 0x2e, 0x72, 0x75,
+	0x6e, 0x74, 0x69, 0x6d, 0x65, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4c, 0x6f, 0x61, 0x64,
+	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x52, 0x0c, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x12, 0x4b, 0x0a, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x5f, 0x63, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x61, 0x69, 0x67, 0x77, 0x2e, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x43, 0x65, 0x6e,
+	0x74, 0x65, 0x72, 0x52, 0x0e, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x43, 0x65, 0x6e,
+	0x74, 0x65, 0x72, 0x12, 0x3e, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x6f, 0x64, 0x65,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x61, 0x69, 0x67, 0x77, 0x2e, 0x72,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x6f,
+	0x64, 0x65, 0x72, 0x12, 0x3e, 0x0a, 0x0a, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x61, 0x69, 0x67, 0x77, 0x2e, 0x72,
+	0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x72, 0x65,
+	0x64, 0x69, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x64, 0x0a, 0x0c, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x72, 0x12, 0x54, 0x0a, 0x11, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x55, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x42, 0x09, 0xfa, 0x42,
+	0x06, 0x2a, 0x04, 0x18, 0x64, 0x28, 0x01, 0x52, 0x10, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x22, 0xb4, 0x03, 0x0a, 0x0e, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x43, 0x65, 0x6e, 0x74, 0x65, 0x72, 0x12, 0x55, 0x0a, 0x14,
+	0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0xfa, 0x42, 0x05, 0xaa, 0x01, 0x02, 0x2a, 0x00, 0x52,
+	0x12, 0x66, 0x65, 0x74, 0x63, 0x68, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x12, 0x53, 0x0a, 0x13, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x63, 0x61, 0x63,
+	0x68, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0xfa, 0x42, 0x05,
+	0xaa, 0x01, 0x02, 0x2a, 0x00, 0x52, 0x11, 0x66, 0x65, 0x74, 0x63, 0x68, 0x43, 0x61, 0x63, 0x68,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x55, 0x0a, 0x14, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x42, 0x08, 0xfa, 0x42, 0x05, 0xaa, 0x01, 0x02, 0x2a, 0x00, 0x52, 0x12, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12,
+	0x46, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x55, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x2a, 0x02, 0x18, 0x0a, 0x52, 0x0a, 0x6d, 0x61, 0x78,
+	0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x12, 0x57, 0x0a, 0x14, 0x6d, 0x61, 0x78, 0x5f, 0x66,
+	0x61, 0x69, 0x6c, 0x6f, 0x76, 0x65, 0x72, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x55, 0x49, 0x6e, 0x74, 0x33, 0x32, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x2a, 0x02, 0x18, 0x0a, 0x52, 0x12, 0x6d, 0x61,
+	0x78, 0x46, 0x61, 0x69, 0x6c, 0x6f, 0x76, 0x65, 0x72, 0x52, 0x65, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x22, 0x51, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x12, 0x43,
+	0x0a, 0x0f, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x69, 0x6e,
+	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x42, 0x6f, 0x6f, 0x6c, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x52, 0x0e, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x69, 0x6e, 0x67, 0x22, 0x56, 0x0a, 0x0a, 0x50, 0x72, 0x65, 0x64, 0x69, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x48, 0x0a, 0x12, 0x75, 0x73, 0x65, 0x5f, 0x6d, 0x6f, 0x76, 0x69, 0x6e, 0x67, 0x5f,
+	0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x42, 0x6f, 0x6f, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x10, 0x75, 0x73, 0x65, 0x4d, 0x6f,
+	0x76, 0x69, 0x6e, 0x67, 0x41, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x42, 0x30, 0x5a, 0x2e, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x69, 0x67, 0x77, 0x2d, 0x70,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x61, 0x69, 0x67, 0x77, 0x2f, 0x70, 0x6b, 0x67, 0x2f,
+	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_runtimeconfig_config_proto_rawDescOnce sync.Once
+	file_pkg_runtimeconfig_config_proto_rawDescData = file_pkg_runtimeconfig_config_proto_rawDesc
+)
+
+func file_pkg_runtimeconfig_config_proto_rawDescGZIP() []byte {
+	file_pkg_runtimeconfig_config_proto_rawDescOnce.Do(func() {
+		file_pkg_runtimeconfig_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_runtimeconfig_config_proto_rawDescData)
+	})
+	return file_pkg_runtimeconfig_config_proto_rawDescData
+}
+
+var file_pkg_runtimeconfig_config_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_pkg_runtimeconfig_config_proto_goTypes = []interface{}{
+	(*RuntimeConfig)(nil),          // 0: aigw.runtimeconfig.RuntimeConfig
+	(*LoadBalancer)(nil),           // 1: aigw.runtimeconfig.LoadBalancer
+	(*MetadataCenter)(nil),         // 2: aigw.runtimeconfig.MetadataCenter
+	(*Transcoder)(nil),             // 3: aigw.runtimeconfig.Transcoder
+	(*Prediction)(nil),             // 4: aigw.runtimeconfig.Prediction
+	(*wrapperspb.UInt32Value)(nil), // 5: google.protobuf.UInt32Value
+	(*durationpb.Duration)(nil),    // 6: google.protobuf.Duration
+	(*wrapperspb.BoolValue)(nil),   // 7: google.protobuf.BoolValue
+}
+var file_pkg_runtimeconfig_config_proto_depIdxs = []int32{
+	1,  // 0: aigw.runtimeconfig.RuntimeConfig.load_balancer:type_name -> aigw.runtimeconfig.LoadBalancer
+	2,  // 1: aigw.runtimeconfig.RuntimeConfig.metadata_center:type_name -> aigw.runtimeconfig.MetadataCenter
+	3,  // 2: aigw.runtimeconfig.RuntimeConfig.transcoder:type_name -> aigw.runtimeconfig.Transcoder
+	4,  // 3: aigw.runtimeconfig.RuntimeConfig.prediction:type_name -> aigw.runtimeconfig.Prediction
+	5,  // 4: aigw.runtimeconfig.LoadBalancer.candidate_percent:type_name -> google.protobuf.UInt32Value
+	6,  // 5: aigw.runtimeconfig.MetadataCenter.fetch_metric_timeout:type_name -> google.protobuf.Duration
+	6,  // 6: aigw.runtimeconfig.MetadataCenter.fetch_cache_timeout:type_name -> google.protobuf.Duration
+	6,  // 7: aigw.runtimeconfig.MetadataCenter.update_stats_timeout:type_name -> google.protobuf.Duration
+	5,  // 8: aigw.runtimeconfig.MetadataCenter.max_retries:type_name -> google.protobuf.UInt32Value
+	5,  // 9: aigw.runtimeconfig.MetadataCenter.max_failover_retries:type_name -> google.protobuf.UInt32Value
+	7,  // 10: aigw.runtimeconfig.Transcoder.split_reasoning:type_name -> google.protobuf.BoolValue
+	7,  // 11: aigw.runtimeconfig.Prediction.use_moving_average:type_name -> google.protobuf.BoolValue
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_pkg_runtimeconfig_config_proto_init() }
+func file_pkg_runtimeconfig_config_proto_init() {
+	if File_pkg_runtimeconfig_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pkg_runtimeconfig_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RuntimeConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_runtimeconfig_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_runtimeconfig_config_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetadataCenter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_runtimeconfig_config_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Transcoder); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_runtimeconfig_config_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Prediction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pkg_runtimeconfig_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pkg_runtimeconfig_config_proto_goTypes,
+		DependencyIndexes: file_pkg_runtimeconfig_config_proto_depIdxs,
+		MessageInfos:      file_pkg_runtimeconfig_config_proto_msgTypes,
+	}.Build()
+	File_pkg_runtimeconfig_config_proto = out.File
+	file_pkg_runtimeconfig_config_proto_rawDesc = nil
+	file_pkg_runtimeconfig_config_proto_goTypes = nil
+	file_pkg_runtimeconfig_config_proto_depIdxs = nil
+}
@@ -0,0 +1,842 @@
+// Code generated by protoc-gen-validate. DO NOT EDIT.
+// source: pkg/runtimeconfig/config.proto
+
+package runtimeconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ensure the imports are used
+var (
+	_ = bytes.MinRead
+	_ = errors.New("")
+	_ = fmt.Print
+	_ = utf8.UTFMax
+	_ = (*regexp.Regexp)(nil)
+	_ = (*strings.Reader)(nil)
+	_ = net.IPv4len
+	_ = time.Duration(0)
+	_ = (*url.URL)(nil)
+	_ = (*mail.Address)(nil)
+	_ = anypb.Any{}
+	_ = sort.Sort
+)
+
+// Validate checks the field values on RuntimeConfig with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *RuntimeConfig) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RuntimeConfig with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in RuntimeConfigMultiError, or
+// nil if none found.
+func (m *RuntimeConfig) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RuntimeConfig) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetLoadBalancer()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RuntimeConfigValidationError{
+					field:  "LoadBalancer",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RuntimeConfigValidationError{
+					field:  "LoadBalancer",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetLoadBalancer()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RuntimeConfigValidationError{
+				field:  "LoadBalancer",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetMetadataCenter()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RuntimeConfigValidationError{
+					field:  "MetadataCenter",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RuntimeConfigValidationError{
+					field:  "MetadataCenter",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetMetadataCenter()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RuntimeConfigValidationError{
+				field:  "MetadataCenter",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetTranscoder()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RuntimeConfigValidationError{
+					field:  "Transcoder",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RuntimeConfigValidationError{
+					field:  "Transcoder",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTranscoder()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RuntimeConfigValidationError{
+				field:  "Transcoder",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetPrediction()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, RuntimeConfigValidationError{
+					field:  "Prediction",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, RuntimeConfigValidationError{
+					field:  "Prediction",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetPrediction()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return RuntimeConfigValidationError{
+				field:  "Prediction",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return RuntimeConfigMultiError(errors)
+	}
+
+	return nil
+}
+
+// RuntimeConfigMultiError is an error wrapping multiple validation errors
+// returned by RuntimeConfig.ValidateAll() if the designated constraints
+// aren't met.
+type RuntimeConfigMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RuntimeConfigMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RuntimeConfigMultiError) AllErrors() []error { return m }
+
+// RuntimeConfigValidationError is the validation error returned by
+// RuntimeConfig.Validate if the designated constraints aren't met.
+type RuntimeConfigValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RuntimeConfigValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RuntimeConfigValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RuntimeConfigValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RuntimeConfigValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RuntimeConfigValidationError) ErrorName() string { return "RuntimeConfigValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RuntimeConfigValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sRuntimeConfig.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = RuntimeConfigValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RuntimeConfigValidationError{}
+
+// Validate checks the field values on LoadBalancer with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *LoadBalancer) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on LoadBalancer with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in LoadBalancerMultiError, or
+// nil if none found.
+func (m *LoadBalancer) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *LoadBalancer) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if wrapper := m.GetCandidatePercent(); wrapper != nil {
+
+		if val := wrapper.GetValue(); val < 1 || val > 100 {
+			err := LoadBalancerValidationError{
+				field:  "CandidatePercent",
+				reason: "value must be inside range [1, 100]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return LoadBalancerMultiError(errors)
+	}
+
+	return nil
+}
+
+// LoadBalancerMultiError is an error wrapping multiple validation errors
+// returned by LoadBalancer.ValidateAll() if the designated constraints aren't met.
+type LoadBalancerMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m LoadBalancerMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m LoadBalancerMultiError) AllErrors() []error { return m }
+
+// LoadBalancerValidationError is the validation error returned by
+// LoadBalancer.Validate if the designated constraints aren't met.
+type LoadBalancerValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LoadBalancerValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LoadBalancerValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LoadBalancerValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LoadBalancerValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LoadBalancerValidationError) ErrorName() string { return "LoadBalancerValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LoadBalancerValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLoadBalancer.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LoadBalancerValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LoadBalancerValidationError{}
+
+// Validate checks the field values on MetadataCenter with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *MetadataCenter) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on MetadataCenter with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in MetadataCenterMultiError,
+// or nil if none found.
+func (m *MetadataCenter) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *MetadataCenter) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if d := m.GetFetchMetricTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = MetadataCenterValidationError{
+				field:  "FetchMetricTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := MetadataCenterValidationError{
+					field:  "FetchMetricTimeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if d := m.GetFetchCacheTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = MetadataCenterValidationError{
+				field:  "FetchCacheTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := MetadataCenterValidationError{
+					field:  "FetchCacheTimeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if d := m.GetUpdateStatsTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = MetadataCenterValidationError{
+				field:  "UpdateStatsTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := MetadataCenterValidationError{
+					field:  "UpdateStatsTimeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if wrapper := m.GetMaxRetries(); wrapper != nil {
+
+		if wrapper.GetValue() > 10 {
+			err := MetadataCenterValidationError{
+				field:  "MaxRetries",
+				reason: "value must be less than or equal to 10",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if wrapper := m.GetMaxFailoverRetries(); wrapper != nil {
+
+		if wrapper.GetValue() > 10 {
+			err := MetadataCenterValidationError{
+				field:  "MaxFailoverRetries",
+				reason: "value must be less than or equal to 10",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return MetadataCenterMultiError(errors)
+	}
+
+	return nil
+}
+
+// MetadataCenterMultiError is an error wrapping multiple validation errors
+// returned by MetadataCenter.ValidateAll() if the designated constraints
+// aren't met.
+type MetadataCenterMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m MetadataCenterMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m MetadataCenterMultiError) AllErrors() []error { return m }
+
+// MetadataCenterValidationError is the validation error returned by
+// MetadataCenter.Validate if the designated constraints aren't met.
+type MetadataCenterValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e MetadataCenterValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e MetadataCenterValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e MetadataCenterValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e MetadataCenterValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e MetadataCenterValidationError) ErrorName() string { return "MetadataCenterValidationError" }
+
+// Error satisfies the builtin error interface
+func (e MetadataCenterValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sMetadataCenter.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = MetadataCenterValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = MetadataCenterValidationError{}
+
+// Validate checks the field values on Transcoder with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Transcoder) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Transcoder with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in TranscoderMultiError, or
+// nil if none found.
+func (m *Transcoder) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Transcoder) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetSplitReasoning()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, TranscoderValidationError{
+					field:  "SplitReasoning",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, TranscoderValidationError{
+					field:  "SplitReasoning",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetSplitReasoning()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return TranscoderValidationError{
+				field:  "SplitReasoning",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return TranscoderMultiError(errors)
+	}
+
+	return nil
+}
+
+// TranscoderMultiError is an error wrapping multiple validation errors
+// returned by Transcoder.ValidateAll() if the designated constraints aren't met.
+type TranscoderMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m TranscoderMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m TranscoderMultiError) AllErrors() []error { return m }
+
+// TranscoderValidationError is the validation error returned by
+// Transcoder.Validate if the designated constraints aren't met.
+type TranscoderValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e TranscoderValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e TranscoderValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e TranscoderValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e TranscoderValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e TranscoderValidationError) ErrorName() string { return "TranscoderValidationError" }
+
+// Error satisfies the builtin error interface
+func (e TranscoderValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sTranscoder.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = TranscoderValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = TranscoderValidationError{}
+
+// Validate checks the field values on Prediction with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Prediction) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Prediction with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in PredictionMultiError, or
+// nil if none found.
+func (m *Prediction) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Prediction) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if all {
+		switch v := interface{}(m.GetUseMovingAverage()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, PredictionValidationError{
+					field:  "UseMovingAverage",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, PredictionValidationError{
+					field:  "UseMovingAverage",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetUseMovingAverage()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return PredictionValidationError{
+				field:  "UseMovingAverage",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return PredictionMultiError(errors)
+	}
+
+	return nil
+}
+
+// PredictionMultiError is an error wrapping multiple validation errors
+// returned by Prediction.ValidateAll() if the designated constraints aren't met.
+type PredictionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PredictionMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PredictionMultiError) AllErrors() []error { return m }
+
+// PredictionValidationError is the validation error returned by
+// Prediction.Validate if the designated constraints aren't met.
+type PredictionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PredictionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PredictionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PredictionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PredictionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PredictionValidationError) ErrorName() string { return "PredictionValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PredictionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPrediction.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PredictionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PredictionValidationError{}
@@ -0,0 +1,81 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimeconfig
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const DebugPath = "/debug/runtime_config"
+
+type dumpValues struct {
+	CandidatePercent   int    `json:"candidate_percent"`
+	FetchMetricTimeout string `json:"fetch_metric_timeout"`
+	FetchCacheTimeout  string `json:"fetch_cache_timeout"`
+	UpdateStatsTimeout string `json:"update_stats_timeout"`
+	MaxRetries         int    `json:"max_retries"`
+	MaxFailoverRetries int    `json:"max_failover_retries"`
+	SplitReasoning     bool   `json:"split_reasoning"`
+	UseMovingAverage   bool   `json:"use_moving_average"`
+}
+
+type dump struct {
+	// where the runtime config comes from, "default" means only the env variables and the defaults are used
+	Source string          `json:"source"`
+	Config json.RawMessage `json:"config,omitempty"`
+	// Effective values after merging the runtime config, the env variables and the defaults
+	Effective dumpValues `json:"effective"`
+	Env       dumpValues `json:"env"`
+}
+
+func toDump(v Values) dumpValues {
+	return dumpValues{
+		CandidatePercent:   v.CandidatePercent,
+		FetchMetricTimeout: v.FetchMetricTimeout.String(),
+		FetchCacheTimeout:  v.FetchCacheTimeout.String(),
+		UpdateStatsTimeout: v.UpdateStatsTimeout.String(),
+		MaxRetries:         v.MaxRetries,
+		MaxFailoverRetries: v.MaxFailoverRetries,
+		SplitReasoning:     v.SplitReasoning,
+		UseMovingAverage:   v.UseMovingAverage,
+	}
+}
+
+// DebugHandler dumps the runtime config and the effective values
+func (s *Store) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cur := s.current.Load()
+		res := dump{
+			Source:    cur.source,
+			Effective: toDump(cur.values),
+			Env:       toDump(s.base),
+		}
+		if cur.config != nil {
+			// protojson is used to print the durations and the wrappers like in the config
+			if js, err := marshalConfig(cur.config); err == nil {
+				res.Config = js
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(res)
+	})
+}
+
+// DebugHandler dumps the runtime config of the gateway
+func DebugHandler() http.Handler {
+	return defaultStore.DebugHandler()
+}
@@ -0,0 +1,137 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimeconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/yaml.v3"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+)
+
+const (
+	AIGW_RUNTIME_CONFIG_FILE           = "AIGW_RUNTIME_CONFIG_FILE"
+	AIGW_RUNTIME_CONFIG_WATCH_INTERVAL = "AIGW_RUNTIME_CONFIG_WATCH_INTERVAL"
+
+	defaultWatchInterval = 10 * time.Second
+)
+
+// Parse parses the config in YAML or JSON, the field names are the same as the JSON mapping of the proto,
+// e.g. metadata_center.fetch_metric_timeout: 0.2s
+func Parse(data []byte) (*RuntimeConfig, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	cfg := &RuntimeConfig{}
+	if raw == nil {
+		// empty file
+		return cfg, nil
+	}
+	js, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := protojson.Unmarshal(js, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func marshalConfig(cfg *RuntimeConfig) ([]byte, error) {
+	return protojson.MarshalOptions{UseProtoNames: true}.Marshal(cfg)
+}
+
+// applyFile applies the config in the file, nothing is changed if the file is invalid
+func (s *Store) applyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := s.Apply(SourceFile+":"+path, cfg); err != nil {
+		return fmt.Errorf("invalid runtime config in %s: %w", path, err)
+	}
+	return nil
+}
+
+// WatchFile applies the config in the file, and polls the file every interval to apply the changes.
+// The invalid config is logged and skipped, the previous one stays effective. Call the returned func to stop watching.
+func (s *Store) WatchFile(path string, interval time.Duration) (stop func(), err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyFile(path); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := data
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			// the file may be replaced by a symlink swap, e.g. a ConfigMap volume, so compare the content
+			// instead of the modification time
+			cur, err := os.ReadFile(path)
+			if err != nil {
+				api.LogErrorf("failed to read runtime config %s: %v", path, err)
+				continue
+			}
+			if bytes.Equal(cur, last) {
+				continue
+			}
+			last = cur
+			if err := s.applyFile(path); err != nil {
+				api.LogErrorf("failed to reload runtime config: %v", err)
+			}
+		}
+	}()
+	return func() { close(done) }, nil
+}
+
+// WatchFileFromEnv watches the file in AIGW_RUNTIME_CONFIG_FILE if it's set
+func WatchFileFromEnv() error {
+	path := os.Getenv(AIGW_RUNTIME_CONFIG_FILE)
+	if path == "" {
+		return nil
+	}
+	interval := defaultWatchInterval
+	if env := os.Getenv(AIGW_RUNTIME_CONFIG_WATCH_INTERVAL); env != "" {
+		d, err := time.ParseDuration(env)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid %s: %s", AIGW_RUNTIME_CONFIG_WATCH_INTERVAL, env)
+		}
+		interval = d
+	}
+	_, err := defaultStore.WatchFile(path, interval)
+	return err
+}
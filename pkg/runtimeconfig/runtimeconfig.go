@@ -0,0 +1,236 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runtimeconfig holds the gateway-wide settings in Values, which can be changed without restarting Envoy.
+// The effective values are resolved by the precedence: RuntimeConfig > env variables > defaults.
+// The RuntimeConfig can be delivered by a watched file or the HTNN plugin "aigwruntime",
+// the last applied one takes effect.
+//
+// Only the settings in Values are covered. The other env variables, like the connections of the metadata center,
+// the service discovery, the outlier detection, the host drain timeout and the TTFT trainer, are read at startup.
+package runtimeconfig
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	pkgcommon "github.com/aigw-project/aigw/pkg/common"
+)
+
+const (
+	EnvCandidatePercent   = "HTNN_AIGW_INFER_LB_CANDIDATE_PERCENT"
+	EnvFetchMetricTimeout = "AIGW_METADATA_CENTER_FETCH_METRIC_TIMEOUT"
+	EnvFetchCacheTimeout  = "AIGW_META_DATA_CACHE_FETCH_TIMEOUT"
+	EnvUpdateStatsTimeout = "AIGW_METADATA_CENTER_UPDATE_STATS_TIMEOUT"
+	EnvMaxRetries         = "AIGW_METADATA_CENTER_MAX_RETRY"
+	EnvMaxFailoverRetries = "AIGW_META_MAX_FAILOVER_RETRY"
+	EnvSplitReasoning     = "AIGW_AI_PROXY_SPLIT_REASONING"
+	EnvUseMovingAverage   = "AIGW_USE_MOVING_AVERAGE"
+
+	SourceDefault = "default"
+	SourceHTNN    = "htnn"
+	SourceFile    = "file"
+)
+
+// Values are the effective settings
+type Values struct {
+	// percentage of the top ranked hosts to choose from in inferencelb
+	CandidatePercent int
+	// timeouts of the metadata center requests
+	FetchMetricTimeout time.Duration
+	FetchCacheTimeout  time.Duration
+	UpdateStatsTimeout time.Duration
+	// retries of the tasks in the async queue of the metadata center
+	MaxRetries int
+	// other metadata center hosts to try when the request to a host fails
+	MaxFailoverRetries int
+	// return the reasoning content in a separate field
+	SplitReasoning bool
+	// predict TTFT by the moving average instead of the predictors
+	UseMovingAverage bool
+}
+
+// Defaults returns the hardcoded defaults
+func Defaults() Values {
+	return Values{
+		CandidatePercent:   5,
+		FetchMetricTimeout: 100 * time.Millisecond,
+		FetchCacheTimeout:  100 * time.Millisecond,
+		UpdateStatsTimeout: 100 * time.Millisecond,
+		MaxRetries:         0,
+		MaxFailoverRetries: 1,
+	}
+}
+
+// envValues overrides the defaults with the env variables, the formats are kept as before for compatibility
+func envValues() Values {
+	v := Defaults()
+	if env := os.Getenv(EnvCandidatePercent); env != "" {
+		if d, err := strconv.Atoi(env); err == nil {
+			v.CandidatePercent = d
+		}
+	}
+	// the timeouts are in ms
+	v.FetchMetricTimeout = time.Duration(pkgcommon.GetIntFromEnv(EnvFetchMetricTimeout, 100)) * time.Millisecond
+	v.FetchCacheTimeout = time.Duration(pkgcommon.GetIntFromEnv(EnvFetchCacheTimeout, 100)) * time.Millisecond
+	v.UpdateStatsTimeout = time.Duration(pkgcommon.GetIntFromEnv(EnvUpdateStatsTimeout, 100)) * time.Millisecond
+	v.MaxRetries = pkgcommon.GetIntFromEnv(EnvMaxRetries, v.MaxRetries)
+	v.MaxFailoverRetries = pkgcommon.GetIntFromEnv(EnvMaxFailoverRetries, v.MaxFailoverRetries)
+	v.SplitReasoning = os.Getenv(EnvSplitReasoning) == "true"
+	v.UseMovingAverage = os.Getenv(EnvUseMovingAverage) == "enable"
+	return v
+}
+
+// merge overrides the base with the fields set in the config
+func merge(base Values, cfg *RuntimeConfig) Values {
+	v := base
+	if lb := cfg.GetLoadBalancer(); lb != nil {
+		if lb.CandidatePercent != nil {
+			v.CandidatePercent = int(lb.CandidatePercent.GetValue())
+		}
+	}
+	if mc := cfg.GetMetadataCenter(); mc != nil {
+		if mc.FetchMetricTimeout != nil {
+			v.FetchMetricTimeout = mc.FetchMetricTimeout.AsDuration()
+		}
+		if mc.FetchCacheTimeout != nil {
+			v.FetchCacheTimeout = mc.FetchCacheTimeout.AsDuration()
+		}
+		if mc.UpdateStatsTimeout != nil {
+			v.UpdateStatsTimeout = mc.UpdateStatsTimeout.AsDuration()
+		}
+		if mc.MaxRetries != nil {
+			v.MaxRetries = int(mc.MaxRetries.GetValue())
+		}
+		if mc.MaxFailoverRetries != nil {
+			v.MaxFailoverRetries = int(mc.MaxFailoverRetries.GetValue())
+		}
+	}
+	if tc := cfg.GetTranscoder(); tc != nil && tc.SplitReasoning != nil {
+		v.SplitReasoning = tc.SplitReasoning.GetValue()
+	}
+	if p := cfg.GetPrediction(); p != nil && p.UseMovingAverage != nil {
+		v.UseMovingAverage = p.UseMovingAverage.GetValue()
+	}
+	return v
+}
+
+type state struct {
+	source string
+	config *RuntimeConfig
+	values Values
+}
+
+// Store keeps the effective values and notifies the subscribers on change
+type Store struct {
+	base    Values
+	current atomic.Pointer[state]
+
+	// lock serializes Apply, so the subscribers see the changes in order
+	lock        sync.Mutex
+	subscribers []func(Values)
+}
+
+func NewStore(base Values) *Store {
+	s := &Store{base: base}
+	s.current.Store(&state{source: SourceDefault, values: base})
+	return s
+}
+
+// Get returns the effective values, it's lock-free and cheap enough for the request path
+func (s *Store) Get() Values {
+	return s.current.Load().values
+}
+
+// Apply validates the config and makes it effective, the unset fields fall back to the env variables.
+// A nil config resets to the env variables.
+func (s *Store) Apply(source string, cfg *RuntimeConfig) error {
+	if cfg != nil {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.applyLocked(source, cfg)
+	return nil
+}
+
+// Remove resets to the env variables when the effective config is applied from the source,
+// e.g. the HTNN plugin config is removed. The config applied from the other sources is kept.
+func (s *Store) Remove(source string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.current.Load().source != source {
+		return
+	}
+	s.applyLocked(source, nil)
+}
+
+func (s *Store) applyLocked(source string, cfg *RuntimeConfig) {
+	values := s.base
+	if cfg != nil {
+		values = merge(s.base, cfg)
+		// the config of the caller may be released, e.g. the plugin config removed from HTNN
+		cfg = proto.Clone(cfg).(*RuntimeConfig)
+	} else {
+		source = SourceDefault
+	}
+	prev := s.current.Swap(&state{source: source, config: cfg, values: values})
+	if prev.values == values {
+		return
+	}
+
+	api.LogInfof("runtime config from %s applied: %+v", source, values)
+	for _, fn := range s.subscribers {
+		fn(values)
+	}
+}
+
+// Subscribe registers fn to be called with the new values after they change,
+// for the components which can't read the values at use time
+func (s *Store) Subscribe(fn func(Values)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+var defaultStore = NewStore(envValues())
+
+// Get returns the effective values of the gateway
+func Get() Values {
+	return defaultStore.Get()
+}
+
+// Apply makes the config effective for the gateway, see Store.Apply
+func Apply(source string, cfg *RuntimeConfig) error {
+	return defaultStore.Apply(source, cfg)
+}
+
+// Remove resets the values of the gateway when the effective config is applied from the source, see Store.Remove
+func Remove(source string) {
+	defaultStore.Remove(source)
+}
+
+// Subscribe registers fn to be called when the values of the gateway change
+func Subscribe(fn func(Values)) {
+	defaultStore.Subscribe(fn)
+}
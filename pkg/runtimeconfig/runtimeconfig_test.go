@@ -0,0 +1,201 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtimeconfig
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+)
+
+func TestEnvValues(t *testing.T) {
+	assert.Equal(t, Defaults(), envValues())
+
+	t.Setenv(EnvCandidatePercent, "20")
+	t.Setenv(EnvFetchMetricTimeout, "50")
+	t.Setenv(EnvMaxFailoverRetries, "0")
+	t.Setenv(EnvSplitReasoning, "true")
+	t.Setenv(EnvUseMovingAverage, "enable")
+	v := envValues()
+	assert.Equal(t, 20, v.CandidatePercent)
+	assert.Equal(t, 50*time.Millisecond, v.FetchMetricTimeout)
+	assert.Equal(t, 100*time.Millisecond, v.FetchCacheTimeout)
+	assert.Equal(t, 0, v.MaxFailoverRetries)
+	assert.True(t, v.SplitReasoning)
+	assert.True(t, v.UseMovingAverage)
+}
+
+func TestApply(t *testing.T) {
+	base := Defaults()
+	base.MaxFailoverRetries = 3
+	s := NewStore(base)
+
+	var notified []Values
+	s.Subscribe(func(v Values) {
+		notified = append(notified, v)
+	})
+
+	// unset fields fall back to the base, zero in the wrappers is kept
+	cfg := &RuntimeConfig{
+		LoadBalancer: &LoadBalancer{CandidatePercent: wrapperspb.UInt32(10)},
+		MetadataCenter: &MetadataCenter{
+			FetchCacheTimeout:  durationpb.New(time.Second),
+			MaxFailoverRetries: wrapperspb.UInt32(0),
+		},
+		Transcoder: &Transcoder{SplitReasoning: wrapperspb.Bool(true)},
+	}
+	assert.NoError(t, s.Apply(SourceHTNN, cfg))
+	v := s.Get()
+	assert.Equal(t, 10, v.CandidatePercent)
+	assert.Equal(t, time.Second, v.FetchCacheTimeout)
+	assert.Equal(t, 100*time.Millisecond, v.FetchMetricTimeout)
+	assert.Equal(t, 0, v.MaxFailoverRetries)
+	assert.True(t, v.SplitReasoning)
+	assert.False(t, v.UseMovingAverage)
+	assert.Equal(t, []Values{v}, notified)
+
+	// no notification if nothing changed
+	assert.NoError(t, s.Apply(SourceHTNN, cfg))
+	assert.Len(t, notified, 1)
+
+	// invalid config is rejected, the previous one stays
+	err := s.Apply(SourceHTNN, &RuntimeConfig{
+		LoadBalancer: &LoadBalancer{CandidatePercent: wrapperspb.UInt32(101)},
+	})
+	assert.ErrorContains(t, err, "CandidatePercent")
+	err = s.Apply(SourceHTNN, &RuntimeConfig{
+		MetadataCenter: &MetadataCenter{FetchMetricTimeout: durationpb.New(0)},
+	})
+	assert.ErrorContains(t, err, "FetchMetricTimeout")
+	assert.Equal(t, v, s.Get())
+
+	// reset
+	assert.NoError(t, s.Apply(SourceHTNN, nil))
+	assert.Equal(t, base, s.Get())
+	assert.Len(t, notified, 2)
+}
+
+func TestRemove(t *testing.T) {
+	base := Defaults()
+	s := NewStore(base)
+	fromFile := &RuntimeConfig{LoadBalancer: &LoadBalancer{CandidatePercent: wrapperspb.UInt32(10)}}
+	fromHTNN := &RuntimeConfig{LoadBalancer: &LoadBalancer{CandidatePercent: wrapperspb.UInt32(20)}}
+
+	// the config from the other source is kept
+	assert.NoError(t, s.Apply(SourceFile, fromFile))
+	s.Remove(SourceHTNN)
+	assert.Equal(t, 10, s.Get().CandidatePercent)
+
+	assert.NoError(t, s.Apply(SourceHTNN, fromHTNN))
+	// the applied config is not affected by the changes of the caller
+	fromHTNN.LoadBalancer.CandidatePercent = wrapperspb.UInt32(30)
+	assert.Equal(t, uint32(20), s.current.Load().config.GetLoadBalancer().GetCandidatePercent().GetValue())
+
+	s.Remove(SourceHTNN)
+	assert.Equal(t, base, s.Get())
+	assert.Equal(t, SourceDefault, s.current.Load().source)
+}
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(`
+load_balancer:
+  candidate_percent: 30
+metadata_center:
+  fetchMetricTimeout: 0.2s
+  max_retries: 2
+prediction:
+  use_moving_average: true
+`))
+	assert.NoError(t, err)
+	v := merge(Defaults(), cfg)
+	assert.Equal(t, 30, v.CandidatePercent)
+	assert.Equal(t, 200*time.Millisecond, v.FetchMetricTimeout)
+	assert.Equal(t, 2, v.MaxRetries)
+	assert.True(t, v.UseMovingAverage)
+
+	cfg, err = Parse([]byte(`{"transcoder": {"split_reasoning": true}}`))
+	assert.NoError(t, err)
+	assert.True(t, cfg.GetTranscoder().GetSplitReasoning().GetValue())
+
+	cfg, err = Parse(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Defaults(), merge(Defaults(), cfg))
+
+	_, err = Parse([]byte(`load_balancer: {unknown: 1}`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`metadata_center: {fetch_metric_timeout: 200ms}`))
+	assert.Error(t, err)
+}
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.yaml")
+	s := NewStore(Defaults())
+
+	_, err := s.WatchFile(path, time.Millisecond)
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte("load_balancer: {candidate_percent: 10}"), 0o644))
+	stop, err := s.WatchFile(path, 10*time.Millisecond)
+	assert.NoError(t, err)
+	defer stop()
+	assert.Equal(t, 10, s.Get().CandidatePercent)
+
+	assert.NoError(t, os.WriteFile(path, []byte("load_balancer: {candidate_percent: 20}"), 0o644))
+	assert.Eventually(t, func() bool {
+		return s.Get().CandidatePercent == 20
+	}, time.Second, 10*time.Millisecond)
+
+	// invalid config is skipped
+	assert.NoError(t, os.WriteFile(path, []byte("load_balancer: {candidate_percent: 200}"), 0o644))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 20, s.Get().CandidatePercent)
+
+	assert.NoError(t, os.WriteFile(path, []byte("transcoder: {split_reasoning: true}"), 0o644))
+	assert.Eventually(t, func() bool {
+		v := s.Get()
+		return v.SplitReasoning && v.CandidatePercent == Defaults().CandidatePercent
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestDebugHandler(t *testing.T) {
+	s := NewStore(Defaults())
+	assert.NoError(t, s.Apply(SourceHTNN, &RuntimeConfig{
+		MetadataCenter: &MetadataCenter{FetchMetricTimeout: durationpb.New(200 * time.Millisecond)},
+	}))
+
+	rec := httptest.NewRecorder()
+	s.DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", DebugPath, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var res map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, SourceHTNN, res["source"])
+	assert.Equal(t, map[string]interface{}{
+		"metadata_center": map[string]interface{}{"fetch_metric_timeout": "0.200s"},
+	}, res["config"])
+	effective := res["effective"].(map[string]interface{})
+	assert.Equal(t, "200ms", effective["fetch_metric_timeout"])
+	assert.Equal(t, float64(5), effective["candidate_percent"])
+	env := res["env"].(map[string]interface{})
+	assert.Equal(t, "100ms", env["fetch_metric_timeout"])
+}
@@ -0,0 +1,143 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/common"
+)
+
+const (
+	ExporterNone     = "none"
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterOTLPHTTP = "otlp-http"
+
+	tracerName  = "github.com/aigw-project/aigw"
+	serviceName = "aigw"
+)
+
+const (
+	// AIGW_TRACE_EXPORTER is one of "none" (default), "otlp-grpc" and "otlp-http"
+	AIGW_TRACE_EXPORTER = "AIGW_TRACE_EXPORTER"
+	// AIGW_TRACE_ENDPOINT is the host:port of the collector, the OTEL_EXPORTER_OTLP_* envs are used when it's empty
+	AIGW_TRACE_ENDPOINT = "AIGW_TRACE_ENDPOINT"
+	// AIGW_TRACE_INSECURE disables TLS to the collector, defaults to true
+	AIGW_TRACE_INSECURE = "AIGW_TRACE_INSECURE"
+	// AIGW_TRACE_SAMPLE_PERCENT is the percentage of the new traces to sample, defaults to 100.
+	// The requests with traceparent follow the sampling decision of the caller.
+	AIGW_TRACE_SAMPLE_PERCENT = "AIGW_TRACE_SAMPLE_PERCENT"
+)
+
+var (
+	tracer     trace.Tracer = noop.NewTracerProvider().Tracer(tracerName)
+	provider   *sdktrace.TracerProvider
+	tracerOnce sync.Once
+)
+
+// Tracer returns the tracer of aigw, which is a noop tracer when the exporter is not configured
+func Tracer() trace.Tracer {
+	tracerOnce.Do(func() {
+		p, err := newProvider(os.Getenv(AIGW_TRACE_EXPORTER))
+		if err != nil {
+			api.LogErrorf("failed to init tracing, tracing is disabled: %v", err)
+			return
+		}
+		if p == nil {
+			return
+		}
+		provider = p
+		otel.SetTracerProvider(p)
+		tracer = p.Tracer(tracerName)
+		api.LogInfof("tracing is enabled, exporter: %s", os.Getenv(AIGW_TRACE_EXPORTER))
+	})
+	return tracer
+}
+
+func newProvider(exporterType string) (*sdktrace.TracerProvider, error) {
+	endpoint := os.Getenv(AIGW_TRACE_ENDPOINT)
+	insecure := true
+	if v := os.Getenv(AIGW_TRACE_INSECURE); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			insecure = b
+		}
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	ctx := context.Background()
+	switch exporterType {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterOTLPGRPC:
+		var opts []otlptracegrpc.Option
+		if endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ExporterOTLPHTTP:
+		var opts []otlptracehttp.Option
+		if endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter: %s", exporterType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("service.name", serviceName)}
+	if hostname := os.Getenv("HOSTNAME"); hostname != "" {
+		attrs = append(attrs, attribute.String("host.name", hostname))
+	}
+	percent := common.GetIntFromEnv(AIGW_TRACE_SAMPLE_PERCENT, 100)
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(float64(percent)/100))),
+	), nil
+}
+
+// Shutdown flushes the pending spans, it's called on shutdown
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
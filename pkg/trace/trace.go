@@ -12,17 +12,108 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package trace propagates the W3C trace context and records the spans of the requests.
 package trace
 
 import (
+	"context"
+	crand "crypto/rand"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 )
 
+const (
+	PluginStateNamespace = "trace"
+	PluginStateTraceID   = "trace_id"
+)
+
+var propagator = propagation.TraceContext{}
+
+// headerCarrier adapts the Envoy request headers to propagation.TextMapCarrier
+type headerCarrier struct {
+	headers api.RequestHeaderMap
+}
+
+func (c headerCarrier) Get(key string) string {
+	v, _ := c.headers.Get(key)
+	return v
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c.headers.Set(key, value)
+}
+
+func (c headerCarrier) Keys() []string {
+	var keys []string
+	c.headers.Range(func(key, value string) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
 // GetTraceID returns the trace ID of the current request
 func GetTraceID(callbacks api.FilterCallbackHandler, headers api.RequestHeaderMap) string {
-	id := callbacks.PluginState().Get("TODO", "trace_id")
-	if id != nil {
-		return id.(string)
+	if id, ok := callbacks.PluginState().Get(PluginStateNamespace, PluginStateTraceID).(string); ok {
+		return id
+	}
+	if headers != nil {
+		sc := trace.SpanContextFromContext(propagator.Extract(context.Background(), headerCarrier{headers}))
+		if sc.IsValid() {
+			return sc.TraceID().String()
+		}
 	}
 	return ""
 }
+
+// StartRequest extracts the trace context from the traceparent header and starts the server span of the request.
+// A new trace is generated when the request doesn't carry a valid traceparent, so that the request could be
+// correlated even if the tracing is disabled. The trace ID is saved in the plugin state for GetTraceID.
+func StartRequest(callbacks api.FilterCallbackHandler, headers api.RequestHeaderMap, name string) (context.Context, trace.Span) {
+	ctx := propagator.Extract(context.Background(), headerCarrier{headers})
+	ctx, span := Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer))
+	if !span.SpanContext().IsValid() {
+		sc := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: newTraceID(),
+			SpanID:  newSpanID(),
+		})
+		ctx = trace.ContextWithSpanContext(ctx, sc)
+		span = trace.SpanFromContext(ctx)
+	}
+	callbacks.PluginState().Set(PluginStateNamespace, PluginStateTraceID, span.SpanContext().TraceID().String())
+	return ctx, span
+}
+
+// StartSpan starts a child span of the span in ctx. No span is recorded when ctx doesn't belong to a trace,
+// e.g. the background tasks, to avoid creating orphan traces.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// Inject writes the traceparent of the span in ctx to the request headers
+func Inject(ctx context.Context, headers api.RequestHeaderMap) {
+	propagator.Inject(ctx, headerCarrier{headers})
+}
+
+// InjectHTTP writes the traceparent of the span in ctx to the http headers
+func InjectHTTP(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func newTraceID() trace.TraceID {
+	var id trace.TraceID
+	_, _ = crand.Read(id[:])
+	return id
+}
+
+func newSpanID() trace.SpanID {
+	var id trace.SpanID
+	_, _ = crand.Read(id[:])
+	return id
+}
@@ -0,0 +1,109 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
+)
+
+const (
+	incomingTraceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	incomingTraceparent = "00-" + incomingTraceID + "-00f067aa0ba902b7-01"
+)
+
+func TestStartRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		wantTraceID string
+	}{
+		{
+			name:        "with traceparent",
+			header:      http.Header{"Traceparent": []string{incomingTraceparent}},
+			wantTraceID: incomingTraceID,
+		},
+		{
+			name:   "without traceparent",
+			header: http.Header{},
+		},
+		{
+			name:   "invalid traceparent",
+			header: http.Header{"Traceparent": []string{"00-invalid"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := envoy.NewFilterCallbackHandler()
+			headers := envoy.NewRequestHeaderMap(tt.header)
+
+			ctx, span := StartRequest(cb, headers, "test")
+			defer span.End()
+
+			traceID := GetTraceID(cb, headers)
+			assert.Len(t, traceID, 32)
+			assert.Equal(t, span.SpanContext().TraceID().String(), traceID)
+			if tt.wantTraceID != "" {
+				assert.Equal(t, tt.wantTraceID, traceID)
+			}
+
+			// the trace context is propagated to upstream
+			upstream := envoy.NewRequestHeaderMap(http.Header{})
+			Inject(ctx, upstream)
+			traceparent, ok := upstream.Get("traceparent")
+			assert.True(t, ok)
+			assert.True(t, strings.HasPrefix(traceparent, "00-"+traceID+"-"), traceparent)
+
+			header := http.Header{}
+			InjectHTTP(ctx, header)
+			assert.Equal(t, traceparent, header.Get("traceparent"))
+		})
+	}
+}
+
+func TestGetTraceIDFromHeader(t *testing.T) {
+	cb := envoy.NewFilterCallbackHandler()
+	assert.Equal(t, "", GetTraceID(cb, nil))
+
+	headers := envoy.NewRequestHeaderMap(http.Header{"Traceparent": []string{incomingTraceparent}})
+	assert.Equal(t, incomingTraceID, GetTraceID(cb, headers))
+}
+
+func TestStartSpanWithoutTrace(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "orphan")
+	defer span.End()
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+	assert.False(t, span.IsRecording())
+}
+
+func TestNewProvider(t *testing.T) {
+	p, err := newProvider("")
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = newProvider("unknown")
+	assert.Error(t, err)
+
+	p, err = newProvider(ExporterOTLPHTTP)
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
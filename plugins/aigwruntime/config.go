@@ -0,0 +1,82 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package aigwruntime delivers the runtime config of the gateway through HTNN/Istio.
+// It doesn't process the requests, the config takes effect for the whole gateway once it's initialized,
+// so it should be configured only once, e.g. in a FilterPolicy targeting the gateway.
+// The values are reset to the env variables when the last config is destroyed. HTNN doesn't call Destroy
+// of the plugin configs yet, so until then an empty config should be applied to reset the values.
+package aigwruntime
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+
+	"github.com/aigw-project/aigw/pkg/runtimeconfig"
+)
+
+const (
+	Name = "aigwruntime"
+)
+
+func init() {
+	plugins.RegisterPlugin(Name, &plugin{})
+}
+
+type plugin struct {
+	plugins.PluginMethodDefaultImpl
+}
+
+func (p *plugin) Config() api.PluginConfig {
+	return &config{}
+}
+
+func filterFactory(interface{}, api.FilterCallbackHandler) api.Filter {
+	return &api.PassThroughFilter{}
+}
+
+func (p *plugin) Factory() api.FilterFactory {
+	return filterFactory
+}
+
+// live is the number of the configs initialized and not destroyed, the values are kept until all of them
+// are destroyed, since a config may be destroyed after its replacement is initialized
+var live atomic.Int64
+
+type config struct {
+	runtimeconfig.RuntimeConfig
+
+	destroyOnce sync.Once
+}
+
+func (c *config) Init(cb api.ConfigCallbackHandler) error {
+	if err := runtimeconfig.Apply(runtimeconfig.SourceHTNN, &c.RuntimeConfig); err != nil {
+		return err
+	}
+	live.Add(1)
+	return nil
+}
+
+// Destroy is called when the config is removed or replaced
+func (c *config) Destroy() {
+	c.destroyOnce.Do(func() {
+		if live.Add(-1) == 0 {
+			api.LogInfof("runtime config of %s is removed, reset to the env variables", Name)
+			runtimeconfig.Remove(runtimeconfig.SourceHTNN)
+		}
+	})
+}
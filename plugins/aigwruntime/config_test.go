@@ -0,0 +1,73 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aigwruntime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/runtimeconfig"
+)
+
+func newConfig(t *testing.T, percent uint32) *config {
+	c := &config{}
+	c.LoadBalancer = &runtimeconfig.LoadBalancer{CandidatePercent: wrapperspb.UInt32(percent)}
+	assert.NoError(t, c.Init(nil))
+	return c
+}
+
+func TestConfigRemoved(t *testing.T) {
+	defaults := runtimeconfig.Get().CandidatePercent
+
+	c := newConfig(t, 42)
+	assert.Equal(t, 42, runtimeconfig.Get().CandidatePercent)
+
+	c.Destroy()
+	assert.Equal(t, defaults, runtimeconfig.Get().CandidatePercent)
+	// destroyed twice
+	c.Destroy()
+	assert.Equal(t, int64(0), live.Load())
+}
+
+func TestConfigReplaced(t *testing.T) {
+	defaults := runtimeconfig.Get().CandidatePercent
+
+	old := newConfig(t, 10)
+	current := newConfig(t, 20)
+
+	// destroying the replaced config doesn't reset the values
+	old.Destroy()
+	old.Destroy()
+	assert.Equal(t, 20, runtimeconfig.Get().CandidatePercent)
+
+	current.Destroy()
+	assert.Equal(t, defaults, runtimeconfig.Get().CandidatePercent)
+}
+
+func TestConfigRemovedByOtherSource(t *testing.T) {
+	t.Cleanup(func() { runtimeconfig.Remove(runtimeconfig.SourceFile) })
+
+	c := newConfig(t, 30)
+	assert.NoError(t, runtimeconfig.Apply(runtimeconfig.SourceFile, &runtimeconfig.RuntimeConfig{
+		LoadBalancer: &runtimeconfig.LoadBalancer{CandidatePercent: wrapperspb.UInt32(40)},
+	}))
+
+	// the config applied from the other source is kept
+	c.Destroy()
+	assert.Equal(t, 40, runtimeconfig.Get().CandidatePercent)
+}
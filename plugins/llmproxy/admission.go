@@ -0,0 +1,184 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway"
+	"github.com/aigw-project/aigw/pkg/aigateway/admission"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/locality"
+	"github.com/aigw-project/aigw/pkg/errcode"
+	"github.com/aigw-project/aigw/pkg/request"
+)
+
+const (
+	defaultPriorityHeader   = "x-aigw-priority"
+	defaultAdmissionMaxWait = 5 * time.Second
+	defaultRetryAfter       = time.Second
+)
+
+// requestPriority returns the priority by consumer first, and then the priority header.
+// The requests without consumer can only lower their priority by the header, since anyone can set it
+func (f *filter) requestPriority(headers api.RequestHeaderMap) uint32 {
+	conf := f.config.GetAdmission()
+	consumer := f.callbacks.GetConsumer()
+	if consumer != nil {
+		if p, ok := conf.GetTenantPriorities()[consumer.Name()]; ok {
+			return p
+		}
+	}
+
+	name := conf.GetPriorityHeader()
+	if name == "" {
+		name = defaultPriorityHeader
+	}
+	if v, ok := headers.Get(name); ok && v != "" {
+		if p, err := strconv.ParseUint(v, 10, 32); err == nil {
+			if consumer == nil {
+				return min(uint32(p), conf.GetDefaultPriority())
+			}
+			return uint32(p)
+		}
+		api.LogDebugf("invalid priority header %s: %s, trace_id: %s", name, v, f.traceId)
+	}
+	return conf.GetDefaultPriority()
+}
+
+// isClusterSaturated returns whether all the serving clusters are saturated, so the request can't spill over.
+// The load is unknown when any cluster has no recent load
+func isClusterSaturated(clusters []locality.Cluster, maxRequestsPerHost uint32) func() (bool, bool) {
+	return func() (bool, bool) {
+		for _, c := range clusters {
+			load, ok := inferencelb.GetClusterLoad(c.Name)
+			if !ok || load.Hosts == 0 {
+				return false, false
+			}
+			if load.TotalReqs < load.Hosts*int(maxRequestsPerHost) {
+				return false, true
+			}
+		}
+		return true, true
+	}
+}
+
+// refreshClusterLoad queries the stale load of the serving clusters, so that the request isn't held
+// only because no request is admitted to refresh the load
+func refreshClusterLoad(ctx context.Context, clusters []locality.Cluster) {
+	for _, c := range clusters {
+		inferencelb.QueryClusterLoad(ctx, c.Name)
+	}
+}
+
+// admit applies the admission control, returns nil when the request is admitted
+func (f *filter) admit(headers api.RequestHeaderMap) api.ResultAction {
+	conf := f.config.GetAdmission()
+	if conf == nil {
+		return nil
+	}
+
+	maxWait := defaultAdmissionMaxWait
+	if conf.GetMaxWait() != nil {
+		maxWait = conf.GetMaxWait().AsDuration()
+	}
+	limits := admission.Limits{
+		MaxQueueSize: int(conf.GetMaxQueueSize()),
+		MaxWait:      maxWait,
+		Saturated:    isClusterSaturated(f.servingClusters, conf.GetMaxRequestsPerHost()),
+		FailClosed:   conf.GetFailClosedOnUnknownLoad(),
+	}
+
+	// the waiting request is canceled when the stream ends, e.g. the downstream resets
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	priority := f.requestPriority(headers)
+	start := time.Now()
+	controller := admission.GetController(f.cluster)
+	err := context.Canceled
+	if f.startAdmission(cancel) {
+		if limits.FailClosed {
+			refreshClusterLoad(ctx, f.servingClusters)
+		}
+		err = controller.Admit(ctx, priority, limits)
+		if err == nil && !f.finishAdmission(controller) {
+			controller.Release()
+			err = context.Canceled
+		}
+	}
+	if wait := time.Since(start); wait > time.Millisecond {
+		request.SetLogField(f.callbacks, "admission_wait", wait.Milliseconds())
+	}
+	if err != nil {
+		api.LogInfof("admission rejects request to cluster %s, priority: %d, err: %v, trace_id: %s", f.cluster, priority, err, f.traceId)
+		request.SetLogField(f.callbacks, "admission_rejected", err.Error())
+
+		retryAfter := defaultRetryAfter
+		if conf.GetRetryAfter() != nil {
+			retryAfter = conf.GetRetryAfter().AsDuration()
+		}
+		header := http.Header{}
+		header.Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return aigateway.NewGatewayErrorResponseWithMsg(f.traceId, header, http.StatusTooManyRequests, &errcode.RateLimitError, err.Error())
+	}
+	return nil
+}
+
+// startAdmission registers the cancel of the admission, returns false when the stream has ended
+func (f *filter) startAdmission(cancel context.CancelFunc) bool {
+	f.admissionLock.Lock()
+	defer f.admissionLock.Unlock()
+	if f.streamEnded {
+		return false
+	}
+	f.admissionCancel = cancel
+	return true
+}
+
+// finishAdmission records the controller which admitted the request, returns false when the stream has ended
+func (f *filter) finishAdmission(controller *admission.Controller) bool {
+	f.admissionLock.Lock()
+	defer f.admissionLock.Unlock()
+	f.admissionCancel = nil
+	if f.streamEnded {
+		return false
+	}
+	f.admissionController = controller
+	return true
+}
+
+// endAdmission is called when the stream ends, it cancels the waiting request or frees the slot of the admitted one
+func (f *filter) endAdmission() {
+	f.admissionLock.Lock()
+	f.streamEnded = true
+	if f.admissionCancel != nil {
+		f.admissionCancel()
+		f.admissionCancel = nil
+	}
+	controller := f.admissionController
+	f.admissionController = nil
+	f.admissionLock.Unlock()
+
+	if controller != nil {
+		controller.Release()
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/admission"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/locality"
+	cfg "github.com/aigw-project/aigw/plugins/llmproxy/config"
+)
+
+// newAdmissionFilter returns a filter of the cluster without load
+func newAdmissionFilter(cluster string, failClosed bool) *filter {
+	return &filter{
+		callbacks: envoy.NewFilterCallbackHandler(),
+		config: &cfg.LLMProxyConfig{
+			Config: cfg.Config{Admission: &cfg.AdmissionConfig{
+				MaxRequestsPerHost:      1,
+				MaxQueueSize:            1,
+				MaxWait:                 durationpb.New(time.Minute),
+				FailClosedOnUnknownLoad: failClosed,
+			}},
+		},
+		cluster:         cluster,
+		servingClusters: []locality.Cluster{{Name: cluster}},
+	}
+}
+
+func TestAdmitUnknownLoad(t *testing.T) {
+	// fail open by default
+	f := newAdmissionFilter("admission-fail-open", false)
+	headers := envoy.NewRequestHeaderMap(http.Header{})
+	assert.Nil(t, f.admit(headers))
+	assert.NotNil(t, f.admissionController)
+
+	f.endAdmission()
+	assert.Nil(t, f.admissionController)
+	// the request is rejected after the stream ends
+	assert.NotNil(t, f.admit(headers))
+	assert.Nil(t, f.admissionController)
+}
+
+func TestAdmitCanceledWhenStreamEnds(t *testing.T) {
+	cluster := "admission-fail-closed"
+	f := newAdmissionFilter(cluster, true)
+
+	done := make(chan api.ResultAction)
+	go func() {
+		done <- f.admit(envoy.NewRequestHeaderMap(http.Header{}))
+	}()
+	controller := admission.GetController(cluster)
+	assert.Eventually(t, func() bool {
+		return controller.QueueSize() == 1
+	}, time.Second, time.Millisecond)
+
+	f.endAdmission()
+	select {
+	case res := <-done:
+		assert.NotNil(t, res)
+	case <-time.After(time.Second):
+		t.Fatal("waiting request is not canceled")
+	}
+	assert.Equal(t, 0, controller.QueueSize())
+	assert.Nil(t, f.admissionController)
+}
+
+type testConsumer struct {
+	name string
+}
+
+func (c *testConsumer) Name() string {
+	return c.name
+}
+
+func (c *testConsumer) PluginConfig(string) api.PluginConsumerConfig {
+	return nil
+}
+
+func TestRequestPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		consumer string
+		header   string
+		want     uint32
+	}{
+		{name: "default", want: 5},
+		{name: "tenant", consumer: "vip", header: "1", want: 10},
+		{name: "consumer header", consumer: "alice", header: "8", want: 8},
+		{name: "invalid header", consumer: "alice", header: "high", want: 5},
+		// the requests without consumer can lower their priority, but not raise it
+		{name: "anonymous lower", header: "1", want: 1},
+		{name: "anonymous higher", header: "100", want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callbacks := envoy.NewFilterCallbackHandler()
+			if tt.consumer != "" {
+				callbacks.SetConsumer(&testConsumer{name: tt.consumer})
+			}
+			f := &filter{
+				callbacks: callbacks,
+				config: &cfg.LLMProxyConfig{
+					Config: cfg.Config{Admission: &cfg.AdmissionConfig{
+						TenantPriorities: map[string]uint32{"vip": 10},
+						DefaultPriority:  5,
+					}},
+				},
+			}
+			hdr := http.Header{}
+			if tt.header != "" {
+				hdr.Set(defaultPriorityHeader, tt.header)
+			}
+			assert.Equal(t, tt.want, f.requestPriority(envoy.NewRequestHeaderMap(hdr)))
+		})
+	}
+}
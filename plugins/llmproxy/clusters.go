@@ -0,0 +1,87 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"context"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/locality"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
+	"github.com/aigw-project/aigw/pkg/request"
+	"github.com/aigw-project/aigw/plugins/llmproxy/transcoder"
+)
+
+// servingClusters returns the clusters serving the request in the preferred order
+func servingClusters(reqData *transcoder.RequestData) []locality.Cluster {
+	if len(reqData.Clusters) == 0 {
+		return []locality.Cluster{{Name: reqData.Cluster}}
+	}
+	clusters := make([]locality.Cluster, 0, len(reqData.Clusters))
+	for _, c := range reqData.Clusters {
+		clusters = append(clusters, locality.Cluster{
+			Name:               c.GetName(),
+			Priority:           c.GetPriority(),
+			Locality:           locality.Locality{Region: c.GetRegion(), Zone: c.GetZone()},
+			MaxRequestsPerHost: c.GetMaxRequestsPerHost(),
+		})
+	}
+	return locality.Order(clusters, locality.Local())
+}
+
+// clusterLoad returns the load of the clusters in metadata center
+func clusterLoad(ctx context.Context) locality.LoadFunc {
+	return func(cluster string) (locality.Load, bool) {
+		load, ok := inferencelb.QueryClusterLoad(ctx, cluster)
+		return locality.Load{Hosts: load.Hosts, TotalReqs: load.TotalReqs}, ok
+	}
+}
+
+// chooseServer chooses the host from the serving clusters, the request spills over to the next cluster when the
+// preferred one is saturated or has no available host, and then to the backup cluster
+func (f *filter) chooseServer(ctx context.Context, headers api.RequestHeaderMap) (types.Host, error) {
+	algorithm := types.LoadBalancerType(f.config.GetAlgorithm())
+	candidates := []locality.Cluster{{Name: f.cluster}}
+	if len(f.servingClusters) > 1 {
+		candidates = locality.Spillover(f.servingClusters, clusterLoad(ctx))
+	}
+	if f.backupCluster != "" {
+		candidates = append(candidates, locality.Cluster{Name: f.backupCluster})
+	}
+
+	preferred := f.cluster
+	var err error
+	for _, c := range candidates {
+		var host types.Host
+		host, err = loadbalancer.ChooseServer(ctx, f.callbacks, headers, c.Name, algorithm)
+		if err != nil {
+			api.LogInfof("no available host in cluster %s, try the next cluster, err: %v, trace_id: %s", c.Name, err, f.traceId)
+			continue
+		}
+		if c.Name == f.backupCluster {
+			api.LogInfof("fallback from cluster %s to the backup cluster %s, trace_id: %s", preferred, c.Name, f.traceId)
+			request.SetLogField(f.callbacks, "backup_cluster", c.Name)
+		} else if c.Name != preferred {
+			api.LogInfof("spill over from cluster %s to %s, trace_id: %s", preferred, c.Name, f.traceId)
+			request.SetLogField(f.callbacks, "spillover_cluster", c.Name)
+		}
+		f.cluster = c.Name
+		return host, nil
+	}
+	return nil, err
+}
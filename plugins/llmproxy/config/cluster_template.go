@@ -0,0 +1,120 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+func toClusterTemplate(t *ClusterTemplate) *common.ClusterTemplate {
+	tmpl := &common.ClusterTemplate{
+		ConnectTimeout:     t.GetConnectTimeout().AsDuration(),
+		HTTP2:              t.GetHttp2(),
+		MaxConnections:     t.GetConnectionPool().GetMaxConnections(),
+		MaxPendingRequests: t.GetConnectionPool().GetMaxPendingRequests(),
+		MaxRequests:        t.GetConnectionPool().GetMaxRequests(),
+		MaxRetries:         t.GetConnectionPool().GetMaxRetries(),
+		IdleTimeout:        t.GetConnectionPool().GetIdleTimeout().AsDuration(),
+		PerTryTimeout:      t.GetPerTryTimeout().AsDuration(),
+	}
+	if k := t.GetHttp2Keepalive(); k != nil {
+		tmpl.HTTP2Keepalive = &common.HTTP2Keepalive{
+			Interval: k.GetInterval().AsDuration(),
+			Timeout:  k.GetTimeout().AsDuration(),
+		}
+	}
+	if tls := t.GetTls(); tls != nil {
+		tmpl.TLS = &common.UpstreamTLS{
+			SNI:    tls.GetSni(),
+			CAFile: tls.GetCaFile(),
+			ALPN:   tls.GetAlpnProtocols(),
+		}
+	}
+	if o := t.GetOutlierDetection(); o != nil {
+		tmpl.OutlierDetection = &common.OutlierDetection{
+			Consecutive5xx:            o.GetConsecutive_5Xx(),
+			ConsecutiveGatewayFailure: o.GetConsecutiveGatewayFailure(),
+			Interval:                  o.GetInterval().AsDuration(),
+			BaseEjectionTime:          o.GetBaseEjectionTime().AsDuration(),
+			MaxEjectionPercent:        o.GetMaxEjectionPercent(),
+		}
+	}
+	if s := t.GetSlowStart(); s != nil {
+		tmpl.SlowStart = &common.SlowStart{
+			Window:           s.GetWindow().AsDuration(),
+			Aggression:       s.GetAggression(),
+			MinWeightPercent: s.GetMinWeightPercent(),
+		}
+	}
+	for _, s := range t.GetSubsetSelectors() {
+		tmpl.SubsetKeys = append(tmpl.SubsetKeys, s.GetKeys())
+	}
+	return tmpl
+}
+
+// retryBudget returns the retry budget of the generated clusters, nil when the retry is disabled
+func retryBudget(policy *RetryPolicy) *common.RetryBudget {
+	if policy.GetNumRetries() == 0 {
+		return nil
+	}
+	return &common.RetryBudget{
+		Percent:        policy.GetBudgetPercent(),
+		MinConcurrency: policy.GetMinRetryConcurrency(),
+	}
+}
+
+// buildClusterTemplates converts and validates the templates of the clusters owned by the config, which are the
+// clusters referred by the rules and the ones named in the templates. The template "*" is applied to the owned
+// clusters without their own template, and the retry budget is applied to all of them. The cluster without any
+// template is mapped to nil, so its previous template is removed.
+func buildClusterTemplates(templates map[string]*ClusterTemplate, clusters map[string]struct{},
+	budget *common.RetryBudget) (map[string]*common.ClusterTemplate, error) {
+	owned := make(map[string]struct{}, len(clusters)+len(templates))
+	for name := range clusters {
+		owned[name] = struct{}{}
+	}
+	for name := range templates {
+		if name == common.DefaultClusterTemplate {
+			continue
+		}
+		if _, ok := clusters[name]; !ok {
+			api.LogWarnf("cluster template %s doesn't match any cluster in the rules", name)
+		}
+		owned[name] = struct{}{}
+	}
+
+	result := make(map[string]*common.ClusterTemplate, len(owned))
+	for name := range owned {
+		t, ok := templates[name]
+		if !ok {
+			t = templates[common.DefaultClusterTemplate]
+		}
+		if t == nil && budget == nil {
+			result[name] = nil
+			continue
+		}
+		tmpl := toClusterTemplate(t)
+		tmpl.RetryBudget = budget
+		if err := tmpl.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid cluster template %s: %w", name, err)
+		}
+		result[name] = tmpl
+	}
+	return result, nil
+}
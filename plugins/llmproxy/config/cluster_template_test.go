@@ -0,0 +1,104 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
+)
+
+func TestBuildClusterTemplatesRetryBudget(t *testing.T) {
+	clusters := map[string]struct{}{"qwen": {}, "deepseek": {}}
+	templates := map[string]*ClusterTemplate{"qwen": {Http2: true}}
+
+	// retry disabled, the clusters without templates are removed
+	result, err := buildClusterTemplates(templates, clusters, retryBudget(&RetryPolicy{BudgetPercent: 50}))
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Nil(t, result["qwen"].RetryBudget)
+	assert.Nil(t, result["deepseek"])
+
+	budget := retryBudget(&RetryPolicy{NumRetries: 2, BudgetPercent: 50})
+	assert.Equal(t, &common.RetryBudget{Percent: 50}, budget)
+	result, err = buildClusterTemplates(templates, clusters, budget)
+	assert.NoError(t, err)
+	assert.True(t, result["qwen"].HTTP2)
+	assert.Equal(t, budget, result["qwen"].RetryBudget)
+	// the clusters without templates
+	assert.Equal(t, &common.ClusterTemplate{RetryBudget: budget}, result["deepseek"])
+
+	// the default template is applied to the clusters of the config only
+	templates[common.DefaultClusterTemplate] = &ClusterTemplate{Http2: true}
+	templates["glm"] = &ClusterTemplate{}
+	result, err = buildClusterTemplates(templates, clusters, budget)
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.True(t, result["deepseek"].HTTP2)
+	assert.Equal(t, budget, result["deepseek"].RetryBudget)
+	assert.False(t, result["glm"].HTTP2)
+	assert.NotContains(t, result, common.DefaultClusterTemplate)
+}
+
+func TestClusterTemplatesMergedInInit(t *testing.T) {
+	t.Cleanup(func() { assert.NoError(t, common.SetClusterTemplates(nil)) })
+
+	newConfig := func(model, cluster string, templates map[string]*ClusterTemplate) *LLMProxyConfig {
+		return &LLMProxyConfig{Config: Config{
+			ModelMappingRule: map[string]*Rules{model: {Rules: []*Rule{{Cluster: cluster}}}},
+			ClusterTemplates: templates,
+		}}
+	}
+
+	qwen := newConfig("qwen", "qwen", map[string]*ClusterTemplate{"qwen": {Http2: true}})
+	assert.NoError(t, qwen.Parse(nil))
+	// parsing has no side effect
+	assert.Nil(t, common.GetClusterTemplate("qwen"))
+	assert.NoError(t, qwen.Init(nil))
+	assert.True(t, common.GetClusterTemplate("qwen").HTTP2)
+
+	// the config of the other clusters doesn't change the template
+	deepseek := newConfig("deepseek", "deepseek", map[string]*ClusterTemplate{
+		common.DefaultClusterTemplate: {ConnectTimeout: durationpb.New(time.Second)},
+	})
+	assert.NoError(t, deepseek.Parse(nil))
+	assert.NoError(t, deepseek.Init(nil))
+	assert.True(t, common.GetClusterTemplate("qwen").HTTP2)
+	assert.Equal(t, time.Second, common.GetClusterTemplate("deepseek").ConnectTimeout)
+	// the default template of the config is not applied to the other clusters
+	assert.Nil(t, common.GetClusterTemplate("glm"))
+
+	empty := &LLMProxyConfig{}
+	assert.NoError(t, empty.Parse(nil))
+	assert.NoError(t, empty.Init(nil))
+	assert.True(t, common.GetClusterTemplate("qwen").HTTP2)
+
+	// the invalid templates are rejected in parsing
+	invalid := newConfig("qwen", "qwen", map[string]*ClusterTemplate{"qwen": {ConnectTimeout: durationpb.New(-time.Second)}})
+	assert.Error(t, invalid.Parse(nil))
+	assert.True(t, common.GetClusterTemplate("qwen").HTTP2)
+
+	// the template is removed when the config of the cluster doesn't have it
+	qwen = newConfig("qwen", "qwen", nil)
+	assert.NoError(t, qwen.Parse(nil))
+	assert.NoError(t, qwen.Init(nil))
+	assert.Nil(t, common.GetClusterTemplate("qwen"))
+	assert.Equal(t, time.Second, common.GetClusterTemplate("deepseek").ConnectTimeout)
+}
@@ -17,14 +17,18 @@ package config
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 
+	"google.golang.org/protobuf/proto"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 
+	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
 	"github.com/aigw-project/aigw/pkg/async_log"
 	mc "github.com/aigw-project/aigw/pkg/metadata_center"
 	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
+	"github.com/aigw-project/aigw/pkg/metrics_stats"
 	"github.com/aigw-project/aigw/pkg/prom"
 )
 
@@ -49,9 +53,14 @@ type LLMProxyConfig struct {
 	Config
 	ModelMappings    map[string]*Mapping
 	LbMappingConfigs map[string]*LBConfig
+	// templates of the generated clusters owned by the config, built in Parse and merged in Init.
+	// A nil template removes the previous one of the cluster
+	ClusterTemplates map[string]*common.ClusterTemplate
 
 	AsyncLogger *async_log.AsyncLogger
-	MC          mctypes.MetadataCenter
+	// fields to redact in the LLM log
+	RedactFields map[string]struct{}
+	MC           mctypes.MetadataCenter
 }
 
 func buildModelMappings(mappingRules map[string]*Rules) map[string]*Mapping {
@@ -85,7 +94,19 @@ func (c *LLMProxyConfig) Init(cb api.ConfigCallbackHandler) error {
 	if len(LbMappingConfigs) > 0 {
 		c.LbMappingConfigs = LbMappingConfigs
 	}
-	c.initLogger()
+	for model, lbConfig := range LbMappingConfigs {
+		if err := metrics_stats.SetTTFTPredictor(model, lbConfig.GetTtftPredictor()); err != nil {
+			return fmt.Errorf("failed to set ttft predictor of model %s: %w", model, err)
+		}
+	}
+	// only the templates of the clusters owned by the config are changed,
+	// the generated clusters are pushed to Envoy again when the templates change
+	if err := common.UpdateClusterTemplates(c.ClusterTemplates); err != nil {
+		return fmt.Errorf("failed to set cluster templates: %w", err)
+	}
+	if err := c.initLogger(); err != nil {
+		return err
+	}
 
 	c.MC = mc.GetMetadataCenter()
 
@@ -122,54 +143,122 @@ func (m *ModelMappingManager) SetMappingRules(mappingRules map[string]*Rules) {
 
 // validateRules verifies the rules array:
 // All rules under the same model must meet the following conditions:
-// 1. cluster must be consistent
+// 1. cluster or clusters must be consistent, and only one of them is set
 // 2. backend can be empty, if empty, it defaults to triton
 // 3. backend must be consistent
-func validateRules(rules []*Rule) (string, string, error) {
+// 4. backup cluster must be consistent and different from the clusters
+// It returns the clusters and the backend of the model.
+func validateRules(rules []*Rule) ([]string, string, error) {
 	if len(rules) == 0 {
-		return "", "", errors.New("rules is empty")
+		return nil, "", errors.New("rules is empty")
 	}
-	expectedCluster := rules[0].Cluster
+	expected := rules[0]
 	expectedBackend := rules[0].Backend
+	expectedBackupCluster := rules[0].BackupCluster
 	if expectedBackend == "" {
 		expectedBackend = "triton"
 	}
 
 	for i := range rules {
-		if rules[i].Cluster != expectedCluster {
-			return "", "", fmt.Errorf("mismatched cluster, current=%s, expected=%s", rules[i].Cluster, expectedCluster)
+		if rules[i].Cluster != expected.Cluster {
+			return nil, "", fmt.Errorf("mismatched cluster, current=%s, expected=%s", rules[i].Cluster, expected.Cluster)
+		}
+		if !slices.EqualFunc(rules[i].Clusters, expected.Clusters, func(a, b *ServingCluster) bool { return proto.Equal(a, b) }) {
+			return nil, "", fmt.Errorf("mismatched clusters, current=%v, expected=%v", rules[i].Clusters, expected.Clusters)
 		}
 		if rules[i].Backend == "" {
 			rules[i].Backend = "triton"
 		}
 		if rules[i].Backend != expectedBackend {
-			return "", "", fmt.Errorf("mismatched backend, current=%s, expected=%s", rules[i].Backend, expectedBackend)
+			return nil, "", fmt.Errorf("mismatched backend, current=%s, expected=%s", rules[i].Backend, expectedBackend)
+		}
+		if rules[i].BackupCluster != expectedBackupCluster {
+			return nil, "", fmt.Errorf("mismatched backup cluster, current=%s, expected=%s", rules[i].BackupCluster, expectedBackupCluster)
+		}
+	}
+
+	clusters, err := ruleClusters(expected)
+	if err != nil {
+		return nil, "", err
+	}
+	if expectedBackupCluster != "" && slices.Contains(clusters, expectedBackupCluster) {
+		return nil, "", fmt.Errorf("backup cluster should be different from cluster %s", expectedBackupCluster)
+	}
+
+	return clusters, expectedBackend, nil
+}
+
+// ruleClusters returns the names of the clusters serving the rule
+func ruleClusters(rule *Rule) ([]string, error) {
+	if len(rule.Clusters) == 0 {
+		if rule.Cluster == "" {
+			return nil, errors.New("cluster is required")
 		}
+		return []string{rule.Cluster}, nil
+	}
+	if rule.Cluster != "" {
+		return nil, errors.New("only one of cluster and clusters could be set")
 	}
 
-	return expectedCluster, expectedBackend, nil
+	names := make([]string, 0, len(rule.Clusters))
+	for _, c := range rule.Clusters {
+		if slices.Contains(names, c.Name) {
+			return nil, fmt.Errorf("duplicate cluster %s", c.Name)
+		}
+		names = append(names, c.Name)
+	}
+	return names, nil
 }
 
 func (c *LLMProxyConfig) Parse(cb api.ConfigParsingCallbackHandler) error {
+	clusters := make(map[string]struct{})
 	mappingRules := c.GetModelMappingRule()
 	if len(mappingRules) > 0 {
 		var clusterToBackend = make(map[string]string)
-		var modelToCluster = make(map[string]string)
+		var modelToCluster = make(map[string][]string)
 		for key, rule := range mappingRules {
-			cluster, backend, err := validateRules(rule.Rules)
+			names, backend, err := validateRules(rule.Rules)
 			if err != nil {
 				api.LogCriticalf("rules validation error, model=%s, err=%+v", key, err)
 				return err
 			}
 
-			clusterToBackend[cluster] = backend
-			modelToCluster[key] = cluster
+			for _, cluster := range names {
+				clusterToBackend[cluster] = backend
+				clusters[cluster] = struct{}{}
+			}
+			modelToCluster[key] = names
+			if backup := rule.Rules[0].BackupCluster; backup != "" {
+				clusters[backup] = struct{}{}
+			}
 		}
 		api.LogInfof("sync cluster config success, config=%+v", clusterToBackend)
 
 		// TODO: we can refactor the lookup logic in OpenAI transcoder to use the new model mapping manager
 		modelMappingManager.SetMappingRules(mappingRules)
 	}
+
+	for model, lbConfig := range c.GetLbMappingRule() {
+		if err := validateSessionAffinity(lbConfig.GetSessionAffinity()); err != nil {
+			api.LogCriticalf("session affinity validation error, model=%s, err=%+v", model, err)
+			return fmt.Errorf("invalid session affinity of model %s: %w", model, err)
+		}
+	}
+
+	if c.GetLog().GetEnabled() {
+		// reject the config instead of failing in Init, so that the previous config keeps working
+		if err := async_log.CheckSinkConfigs(c.sinkConfigs()); err != nil {
+			api.LogCriticalf("llm log sinks validation error, err=%+v", err)
+			return err
+		}
+	}
+
+	templates, err := buildClusterTemplates(c.GetClusterTemplates(), clusters, retryBudget(c.GetRetryPolicy()))
+	if err != nil {
+		api.LogCriticalf("cluster templates validation error, err=%+v", err)
+		return err
+	}
+	c.ClusterTemplates = templates
 	return nil
 }
 
@@ -224,8 +313,49 @@ func GetCandidateRule(targetModelTuple []Tuple, headers api.RequestHeaderMap) *R
 	return nil
 }
 
-func (c *LLMProxyConfig) initLogger() {
-	if c.Config.GetLog().GetEnabled() {
-		c.AsyncLogger = async_log.GetAsyncLoggerInstance(c.Config.GetLog().GetPath(), 1000)
+// sinkConfigs returns the configs of the LLM log sinks, nil when the log is written to the default file
+func (c *LLMProxyConfig) sinkConfigs() []async_log.SinkConfig {
+	sinks := c.Config.GetLog().GetSinks()
+	if len(sinks) == 0 {
+		return nil
+	}
+	configs := make([]async_log.SinkConfig, 0, len(sinks))
+	for _, sink := range sinks {
+		configs = append(configs, async_log.SinkConfig{
+			Type:           sink.GetType(),
+			Path:           sink.GetPath(),
+			Address:        sink.GetAddress(),
+			QueueSize:      int(sink.GetQueueSize()),
+			OverflowPolicy: sink.GetOverflowPolicy(),
+			BlockTimeout:   sink.GetBlockTimeout().AsDuration(),
+			BatchSize:      int(sink.GetBatchSize()),
+			FlushInterval:  sink.GetFlushInterval().AsDuration(),
+		})
+	}
+	return configs
+}
+
+func (c *LLMProxyConfig) initLogger() error {
+	logConfig := c.Config.GetLog()
+	if !logConfig.GetEnabled() {
+		return nil
+	}
+
+	if configs := c.sinkConfigs(); len(configs) > 0 {
+		logger, err := async_log.NewAsyncLogger(configs)
+		if err != nil {
+			return fmt.Errorf("failed to create llm log sinks: %w", err)
+		}
+		c.AsyncLogger = logger
+	} else {
+		c.AsyncLogger = async_log.GetAsyncLoggerInstance(logConfig.GetPath(), async_log.DefaultQueueSize)
 	}
+
+	if fields := logConfig.GetRedactFields(); len(fields) > 0 {
+		c.RedactFields = make(map[string]struct{}, len(fields))
+		for _, field := range fields {
+			c.RedactFields[field] = struct{}{}
+		}
+	}
+	return nil
 }
@@ -199,6 +199,114 @@ func (m *Config) validate(all bool) error {
 		}
 	}
 
+	if all {
+		switch v := interface{}(m.GetRetryPolicy()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ConfigValidationError{
+					field:  "RetryPolicy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ConfigValidationError{
+					field:  "RetryPolicy",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetRetryPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConfigValidationError{
+				field:  "RetryPolicy",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetAdmission()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ConfigValidationError{
+					field:  "Admission",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ConfigValidationError{
+					field:  "Admission",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAdmission()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ConfigValidationError{
+				field:  "Admission",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for EnableLbExplain
+
+	{
+		sorted_keys := make([]string, len(m.GetClusterTemplates()))
+		i := 0
+		for key := range m.GetClusterTemplates() {
+			sorted_keys[i] = key
+			i++
+		}
+		sort.Slice(sorted_keys, func(i, j int) bool { return sorted_keys[i] < sorted_keys[j] })
+		for _, key := range sorted_keys {
+			val := m.GetClusterTemplates()[key]
+			_ = val
+
+			// no validation rules for ClusterTemplates[key]
+
+			if all {
+				switch v := interface{}(val).(type) {
+				case interface{ ValidateAll() error }:
+					if err := v.ValidateAll(); err != nil {
+						errors = append(errors, ConfigValidationError{
+							field:  fmt.Sprintf("ClusterTemplates[%v]", key),
+							reason: "embedded message failed validation",
+							cause:  err,
+						})
+					}
+				case interface{ Validate() error }:
+					if err := v.Validate(); err != nil {
+						errors = append(errors, ConfigValidationError{
+							field:  fmt.Sprintf("ClusterTemplates[%v]", key),
+							reason: "embedded message failed validation",
+							cause:  err,
+						})
+					}
+				}
+			} else if v, ok := interface{}(val).(interface{ Validate() error }); ok {
+				if err := v.Validate(); err != nil {
+					return ConfigValidationError{
+						field:  fmt.Sprintf("ClusterTemplates[%v]", key),
+						reason: "embedded message failed validation",
+						cause:  err,
+					}
+				}
+			}
+
+		}
+	}
+
+	// no validation rules for HashHeader
+
 	if len(errors) > 0 {
 		return ConfigMultiError(errors)
 	}
@@ -462,6 +570,46 @@ func (m *LBConfig) validate(all bool) error {
 
 	// no validation rules for CacheRadioWeight
 
+	if _, ok := _LBConfig_TtftPredictor_InLookup[m.GetTtftPredictor()]; !ok {
+		err := LBConfigValidationError{
+			field:  "TtftPredictor",
+			reason: "value must be in list [ rls isotonic p90 load_aware]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if all {
+		switch v := interface{}(m.GetSessionAffinity()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, LBConfigValidationError{
+					field:  "SessionAffinity",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, LBConfigValidationError{
+					field:  "SessionAffinity",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetSessionAffinity()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return LBConfigValidationError{
+				field:  "SessionAffinity",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
 	if len(errors) > 0 {
 		return LBConfigMultiError(errors)
 	}
@@ -539,6 +687,138 @@ var _ interface {
 	ErrorName() string
 } = LBConfigValidationError{}
 
+var _LBConfig_TtftPredictor_InLookup = map[string]struct{}{
+	"":           {},
+	"rls":        {},
+	"isotonic":   {},
+	"p90":        {},
+	"load_aware": {},
+}
+
+// Validate checks the field values on SessionAffinity with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *SessionAffinity) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SessionAffinity with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// SessionAffinityMultiError, or nil if none found.
+func (m *SessionAffinity) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SessionAffinity) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if _, ok := SessionAffinity_Source_name[int32(m.GetSource())]; !ok {
+		err := SessionAffinityValidationError{
+			field:  "Source",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Header
+
+	if m.GetLoadFactor() < 0 {
+		err := SessionAffinityValidationError{
+			field:  "LoadFactor",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return SessionAffinityMultiError(errors)
+	}
+
+	return nil
+}
+
+// SessionAffinityMultiError is an error wrapping multiple validation errors
+// returned by SessionAffinity.ValidateAll() if the designated constraints
+// aren't met.
+type SessionAffinityMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SessionAffinityMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SessionAffinityMultiError) AllErrors() []error { return m }
+
+// SessionAffinityValidationError is the validation error returned by
+// SessionAffinity.Validate if the designated constraints aren't met.
+type SessionAffinityValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SessionAffinityValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SessionAffinityValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SessionAffinityValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SessionAffinityValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SessionAffinityValidationError) ErrorName() string { return "SessionAffinityValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SessionAffinityValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSessionAffinity.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SessionAffinityValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SessionAffinityValidationError{}
+
 // Validate checks the field values on Rule with the rules defined in the proto
 // definition for this message. If any rules are violated, the first error
 // encountered is returned, or nil if there are no violations.
@@ -674,10 +954,21 @@ func (m *Rule) validate(all bool) error {
 
 	}
 
-	if l := utf8.RuneCountInString(m.GetCluster()); l < 1 || l > 256 {
+	if utf8.RuneCountInString(m.GetCluster()) > 256 {
 		err := RuleValidationError{
 			field:  "Cluster",
-			reason: "value length must be between 1 and 256 runes, inclusive",
+			reason: "value length must be at most 256 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetBackupCluster()) > 256 {
+		err := RuleValidationError{
+			field:  "BackupCluster",
+			reason: "value length must be at most 256 runes",
 		}
 		if !all {
 			return err
@@ -685,6 +976,40 @@ func (m *Rule) validate(all bool) error {
 		errors = append(errors, err)
 	}
 
+	for idx, item := range m.GetClusters() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, RuleValidationError{
+						field:  fmt.Sprintf("Clusters[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, RuleValidationError{
+						field:  fmt.Sprintf("Clusters[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return RuleValidationError{
+					field:  fmt.Sprintf("Clusters[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return RuleMultiError(errors)
 	}
@@ -762,48 +1087,1794 @@ var _ interface {
 	ErrorName() string
 } = RuleValidationError{}
 
-// Validate checks the field values on Subset with the rules defined in the
-// proto definition for this message. If any rules are violated, the first
+// Validate checks the field values on ServingCluster with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
 // error encountered is returned, or nil if there are no violations.
-func (m *Subset) Validate() error {
+func (m *ServingCluster) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on Subset with the rules defined in the
-// proto definition for this message. If any rules are violated, the result is
-// a list of violation errors wrapped in SubsetMultiError, or nil if none found.
-func (m *Subset) ValidateAll() error {
+// ValidateAll checks the field values on ServingCluster with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ServingClusterMultiError,
+// or nil if none found.
+func (m *ServingCluster) ValidateAll() error {
 	return m.validate(true)
 }
 
-func (m *Subset) validate(all bool) error {
+func (m *ServingCluster) validate(all bool) error {
 	if m == nil {
 		return nil
 	}
 
 	var errors []error
 
-	// no validation rules for Name
-
-	// no validation rules for Labels
+	if l := utf8.RuneCountInString(m.GetName()); l < 1 || l > 256 {
+		err := ServingClusterValidationError{
+			field:  "Name",
+			reason: "value length must be between 1 and 256 runes, inclusive",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Priority
+
+	// no validation rules for Region
+
+	// no validation rules for Zone
+
+	// no validation rules for MaxRequestsPerHost
+
+	if len(errors) > 0 {
+		return ServingClusterMultiError(errors)
+	}
+
+	return nil
+}
+
+// ServingClusterMultiError is an error wrapping multiple validation errors
+// returned by ServingCluster.ValidateAll() if the designated constraints
+// aren't met.
+type ServingClusterMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ServingClusterMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ServingClusterMultiError) AllErrors() []error { return m }
+
+// ServingClusterValidationError is the validation error returned by
+// ServingCluster.Validate if the designated constraints aren't met.
+type ServingClusterValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ServingClusterValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ServingClusterValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ServingClusterValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ServingClusterValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ServingClusterValidationError) ErrorName() string { return "ServingClusterValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ServingClusterValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sServingCluster.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ServingClusterValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ServingClusterValidationError{}
+
+// Validate checks the field values on Subset with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Subset) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Subset with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in SubsetMultiError, or nil if none found.
+func (m *Subset) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Subset) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Name
+
+	// no validation rules for Labels
+
+	// no validation rules for Lora
+
+	// no validation rules for Weight
+
+	if len(errors) > 0 {
+		return SubsetMultiError(errors)
+	}
+
+	return nil
+}
+
+// SubsetMultiError is an error wrapping multiple validation errors returned by
+// Subset.ValidateAll() if the designated constraints aren't met.
+type SubsetMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SubsetMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SubsetMultiError) AllErrors() []error { return m }
+
+// SubsetValidationError is the validation error returned by Subset.Validate if
+// the designated constraints aren't met.
+type SubsetValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SubsetValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SubsetValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SubsetValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SubsetValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SubsetValidationError) ErrorName() string { return "SubsetValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SubsetValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSubset.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SubsetValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SubsetValidationError{}
+
+// Validate checks the field values on ClusterTemplate with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ClusterTemplate) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ClusterTemplate with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ClusterTemplateMultiError, or nil if none found.
+func (m *ClusterTemplate) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ClusterTemplate) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if d := m.GetConnectTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = ClusterTemplateValidationError{
+				field:  "ConnectTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := ClusterTemplateValidationError{
+					field:  "ConnectTimeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	// no validation rules for Http2
+
+	if all {
+		switch v := interface{}(m.GetHttp2Keepalive()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "Http2Keepalive",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "Http2Keepalive",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetHttp2Keepalive()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ClusterTemplateValidationError{
+				field:  "Http2Keepalive",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetTls()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "Tls",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "Tls",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetTls()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ClusterTemplateValidationError{
+				field:  "Tls",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetConnectionPool()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "ConnectionPool",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "ConnectionPool",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetConnectionPool()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ClusterTemplateValidationError{
+				field:  "ConnectionPool",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if d := m.GetPerTryTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = ClusterTemplateValidationError{
+				field:  "PerTryTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := ClusterTemplateValidationError{
+					field:  "PerTryTimeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetOutlierDetection()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "OutlierDetection",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "OutlierDetection",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetOutlierDetection()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ClusterTemplateValidationError{
+				field:  "OutlierDetection",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	for idx, item := range m.GetSubsetSelectors() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ClusterTemplateValidationError{
+						field:  fmt.Sprintf("SubsetSelectors[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ClusterTemplateValidationError{
+						field:  fmt.Sprintf("SubsetSelectors[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ClusterTemplateValidationError{
+					field:  fmt.Sprintf("SubsetSelectors[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
+	if all {
+		switch v := interface{}(m.GetSlowStart()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "SlowStart",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ClusterTemplateValidationError{
+					field:  "SlowStart",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetSlowStart()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ClusterTemplateValidationError{
+				field:  "SlowStart",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return ClusterTemplateMultiError(errors)
+	}
+
+	return nil
+}
+
+// ClusterTemplateMultiError is an error wrapping multiple validation errors
+// returned by ClusterTemplate.ValidateAll() if the designated constraints
+// aren't met.
+type ClusterTemplateMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ClusterTemplateMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ClusterTemplateMultiError) AllErrors() []error { return m }
+
+// ClusterTemplateValidationError is the validation error returned by
+// ClusterTemplate.Validate if the designated constraints aren't met.
+type ClusterTemplateValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ClusterTemplateValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ClusterTemplateValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ClusterTemplateValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ClusterTemplateValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ClusterTemplateValidationError) ErrorName() string { return "ClusterTemplateValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ClusterTemplateValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sClusterTemplate.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ClusterTemplateValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ClusterTemplateValidationError{}
+
+// Validate checks the field values on Http2Keepalive with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *Http2Keepalive) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on Http2Keepalive with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in Http2KeepaliveMultiError,
+// or nil if none found.
+func (m *Http2Keepalive) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *Http2Keepalive) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetInterval() == nil {
+		err := Http2KeepaliveValidationError{
+			field:  "Interval",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if d := m.GetInterval(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = Http2KeepaliveValidationError{
+				field:  "Interval",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := Http2KeepaliveValidationError{
+					field:  "Interval",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if m.GetTimeout() == nil {
+		err := Http2KeepaliveValidationError{
+			field:  "Timeout",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if d := m.GetTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = Http2KeepaliveValidationError{
+				field:  "Timeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := Http2KeepaliveValidationError{
+					field:  "Timeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if len(errors) > 0 {
+		return Http2KeepaliveMultiError(errors)
+	}
+
+	return nil
+}
+
+// Http2KeepaliveMultiError is an error wrapping multiple validation errors
+// returned by Http2Keepalive.ValidateAll() if the designated constraints
+// aren't met.
+type Http2KeepaliveMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m Http2KeepaliveMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m Http2KeepaliveMultiError) AllErrors() []error { return m }
+
+// Http2KeepaliveValidationError is the validation error returned by
+// Http2Keepalive.Validate if the designated constraints aren't met.
+type Http2KeepaliveValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e Http2KeepaliveValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e Http2KeepaliveValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e Http2KeepaliveValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e Http2KeepaliveValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e Http2KeepaliveValidationError) ErrorName() string { return "Http2KeepaliveValidationError" }
+
+// Error satisfies the builtin error interface
+func (e Http2KeepaliveValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sHttp2Keepalive.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = Http2KeepaliveValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = Http2KeepaliveValidationError{}
+
+// Validate checks the field values on UpstreamTLS with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *UpstreamTLS) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on UpstreamTLS with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in UpstreamTLSMultiError, or
+// nil if none found.
+func (m *UpstreamTLS) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *UpstreamTLS) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetSni()) > 255 {
+		err := UpstreamTLSValidationError{
+			field:  "Sni",
+			reason: "value length must be at most 255 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for CaFile
+
+	for idx, item := range m.GetAlpnProtocols() {
+		_, _ = idx, item
+
+		if utf8.RuneCountInString(item) < 1 {
+			err := UpstreamTLSValidationError{
+				field:  fmt.Sprintf("AlpnProtocols[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return UpstreamTLSMultiError(errors)
+	}
+
+	return nil
+}
+
+// UpstreamTLSMultiError is an error wrapping multiple validation errors
+// returned by UpstreamTLS.ValidateAll() if the designated constraints aren't met.
+type UpstreamTLSMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m UpstreamTLSMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m UpstreamTLSMultiError) AllErrors() []error { return m }
+
+// UpstreamTLSValidationError is the validation error returned by
+// UpstreamTLS.Validate if the designated constraints aren't met.
+type UpstreamTLSValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e UpstreamTLSValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e UpstreamTLSValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e UpstreamTLSValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e UpstreamTLSValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e UpstreamTLSValidationError) ErrorName() string { return "UpstreamTLSValidationError" }
+
+// Error satisfies the builtin error interface
+func (e UpstreamTLSValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sUpstreamTLS.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = UpstreamTLSValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = UpstreamTLSValidationError{}
+
+// Validate checks the field values on ConnectionPool with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *ConnectionPool) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ConnectionPool with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in ConnectionPoolMultiError,
+// or nil if none found.
+func (m *ConnectionPool) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ConnectionPool) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for MaxConnections
+
+	// no validation rules for MaxPendingRequests
+
+	// no validation rules for MaxRequests
+
+	// no validation rules for MaxRetries
+
+	if d := m.GetIdleTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = ConnectionPoolValidationError{
+				field:  "IdleTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := ConnectionPoolValidationError{
+					field:  "IdleTimeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if len(errors) > 0 {
+		return ConnectionPoolMultiError(errors)
+	}
+
+	return nil
+}
+
+// ConnectionPoolMultiError is an error wrapping multiple validation errors
+// returned by ConnectionPool.ValidateAll() if the designated constraints
+// aren't met.
+type ConnectionPoolMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ConnectionPoolMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ConnectionPoolMultiError) AllErrors() []error { return m }
+
+// ConnectionPoolValidationError is the validation error returned by
+// ConnectionPool.Validate if the designated constraints aren't met.
+type ConnectionPoolValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ConnectionPoolValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ConnectionPoolValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ConnectionPoolValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ConnectionPoolValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ConnectionPoolValidationError) ErrorName() string { return "ConnectionPoolValidationError" }
+
+// Error satisfies the builtin error interface
+func (e ConnectionPoolValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sConnectionPool.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ConnectionPoolValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ConnectionPoolValidationError{}
+
+// Validate checks the field values on OutlierDetection with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *OutlierDetection) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on OutlierDetection with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// OutlierDetectionMultiError, or nil if none found.
+func (m *OutlierDetection) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *OutlierDetection) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Consecutive_5Xx
+
+	// no validation rules for ConsecutiveGatewayFailure
+
+	if d := m.GetInterval(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = OutlierDetectionValidationError{
+				field:  "Interval",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := OutlierDetectionValidationError{
+					field:  "Interval",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if d := m.GetBaseEjectionTime(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = OutlierDetectionValidationError{
+				field:  "BaseEjectionTime",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := OutlierDetectionValidationError{
+					field:  "BaseEjectionTime",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if m.GetMaxEjectionPercent() > 100 {
+		err := OutlierDetectionValidationError{
+			field:  "MaxEjectionPercent",
+			reason: "value must be less than or equal to 100",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return OutlierDetectionMultiError(errors)
+	}
+
+	return nil
+}
+
+// OutlierDetectionMultiError is an error wrapping multiple validation errors
+// returned by OutlierDetection.ValidateAll() if the designated constraints
+// aren't met.
+type OutlierDetectionMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m OutlierDetectionMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m OutlierDetectionMultiError) AllErrors() []error { return m }
+
+// OutlierDetectionValidationError is the validation error returned by
+// OutlierDetection.Validate if the designated constraints aren't met.
+type OutlierDetectionValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e OutlierDetectionValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e OutlierDetectionValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e OutlierDetectionValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e OutlierDetectionValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e OutlierDetectionValidationError) ErrorName() string { return "OutlierDetectionValidationError" }
+
+// Error satisfies the builtin error interface
+func (e OutlierDetectionValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sOutlierDetection.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = OutlierDetectionValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = OutlierDetectionValidationError{}
+
+// Validate checks the field values on SlowStart with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *SlowStart) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SlowStart with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in SlowStartMultiError, or nil
+// if none found.
+func (m *SlowStart) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SlowStart) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetWindow() == nil {
+		err := SlowStartValidationError{
+			field:  "Window",
+			reason: "value is required",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if d := m.GetWindow(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = SlowStartValidationError{
+				field:  "Window",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := SlowStartValidationError{
+					field:  "Window",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if m.GetAggression() < 0 {
+		err := SlowStartValidationError{
+			field:  "Aggression",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetMinWeightPercent() > 100 {
+		err := SlowStartValidationError{
+			field:  "MinWeightPercent",
+			reason: "value must be less than or equal to 100",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return SlowStartMultiError(errors)
+	}
+
+	return nil
+}
+
+// SlowStartMultiError is an error wrapping multiple validation errors returned
+// by SlowStart.ValidateAll() if the designated constraints aren't met.
+type SlowStartMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SlowStartMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SlowStartMultiError) AllErrors() []error { return m }
+
+// SlowStartValidationError is the validation error returned by
+// SlowStart.Validate if the designated constraints aren't met.
+type SlowStartValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SlowStartValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SlowStartValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SlowStartValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SlowStartValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SlowStartValidationError) ErrorName() string { return "SlowStartValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SlowStartValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSlowStart.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SlowStartValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SlowStartValidationError{}
+
+// Validate checks the field values on SubsetSelector with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *SubsetSelector) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on SubsetSelector with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in SubsetSelectorMultiError,
+// or nil if none found.
+func (m *SubsetSelector) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *SubsetSelector) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if len(m.GetKeys()) < 1 {
+		err := SubsetSelectorValidationError{
+			field:  "Keys",
+			reason: "value must contain at least 1 item(s)",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	_SubsetSelector_Keys_Unique := make(map[string]struct{}, len(m.GetKeys()))
+
+	for idx, item := range m.GetKeys() {
+		_, _ = idx, item
+
+		if _, exists := _SubsetSelector_Keys_Unique[item]; exists {
+			err := SubsetSelectorValidationError{
+				field:  fmt.Sprintf("Keys[%v]", idx),
+				reason: "repeated value must contain unique items",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+			_SubsetSelector_Keys_Unique[item] = struct{}{}
+		}
+
+		if utf8.RuneCountInString(item) < 1 {
+			err := SubsetSelectorValidationError{
+				field:  fmt.Sprintf("Keys[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if len(errors) > 0 {
+		return SubsetSelectorMultiError(errors)
+	}
+
+	return nil
+}
+
+// SubsetSelectorMultiError is an error wrapping multiple validation errors
+// returned by SubsetSelector.ValidateAll() if the designated constraints
+// aren't met.
+type SubsetSelectorMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m SubsetSelectorMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m SubsetSelectorMultiError) AllErrors() []error { return m }
+
+// SubsetSelectorValidationError is the validation error returned by
+// SubsetSelector.Validate if the designated constraints aren't met.
+type SubsetSelectorValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e SubsetSelectorValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e SubsetSelectorValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e SubsetSelectorValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e SubsetSelectorValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e SubsetSelectorValidationError) ErrorName() string { return "SubsetSelectorValidationError" }
+
+// Error satisfies the builtin error interface
+func (e SubsetSelectorValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sSubsetSelector.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = SubsetSelectorValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = SubsetSelectorValidationError{}
+
+// Validate checks the field values on RetryPolicy with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *RetryPolicy) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on RetryPolicy with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in RetryPolicyMultiError, or
+// nil if none found.
+func (m *RetryPolicy) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *RetryPolicy) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetNumRetries() > 5 {
+		err := RetryPolicyValidationError{
+			field:  "NumRetries",
+			reason: "value must be less than or equal to 5",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetRetriableStatusCodes() {
+		_, _ = idx, item
+
+		if val := item; val < 500 || val > 599 {
+			err := RetryPolicyValidationError{
+				field:  fmt.Sprintf("RetriableStatusCodes[%v]", idx),
+				reason: "value must be inside range [500, 599]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	if d := m.GetPerTryTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = RetryPolicyValidationError{
+				field:  "PerTryTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := RetryPolicyValidationError{
+					field:  "PerTryTimeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if m.GetBudgetPercent() > 100 {
+		err := RetryPolicyValidationError{
+			field:  "BudgetPercent",
+			reason: "value must be less than or equal to 100",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for MinRetryConcurrency
+
+	if len(errors) > 0 {
+		return RetryPolicyMultiError(errors)
+	}
+
+	return nil
+}
+
+// RetryPolicyMultiError is an error wrapping multiple validation errors
+// returned by RetryPolicy.ValidateAll() if the designated constraints aren't met.
+type RetryPolicyMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m RetryPolicyMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m RetryPolicyMultiError) AllErrors() []error { return m }
+
+// RetryPolicyValidationError is the validation error returned by
+// RetryPolicy.Validate if the designated constraints aren't met.
+type RetryPolicyValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e RetryPolicyValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e RetryPolicyValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e RetryPolicyValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e RetryPolicyValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e RetryPolicyValidationError) ErrorName() string { return "RetryPolicyValidationError" }
+
+// Error satisfies the builtin error interface
+func (e RetryPolicyValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
 
-	// no validation rules for Lora
+	return fmt.Sprintf(
+		"invalid %sRetryPolicy.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
 
-	// no validation rules for Weight
+var _ error = RetryPolicyValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = RetryPolicyValidationError{}
+
+// Validate checks the field values on AdmissionConfig with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *AdmissionConfig) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on AdmissionConfig with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// AdmissionConfigMultiError, or nil if none found.
+func (m *AdmissionConfig) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *AdmissionConfig) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetMaxRequestsPerHost() <= 0 {
+		err := AdmissionConfigValidationError{
+			field:  "MaxRequestsPerHost",
+			reason: "value must be greater than 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for MaxQueueSize
+
+	if d := m.GetMaxWait(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = AdmissionConfigValidationError{
+				field:  "MaxWait",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := AdmissionConfigValidationError{
+					field:  "MaxWait",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if d := m.GetRetryAfter(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = AdmissionConfigValidationError{
+				field:  "RetryAfter",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gte := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur < gte {
+				err := AdmissionConfigValidationError{
+					field:  "RetryAfter",
+					reason: "value must be greater than or equal to 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if utf8.RuneCountInString(m.GetPriorityHeader()) > 128 {
+		err := AdmissionConfigValidationError{
+			field:  "PriorityHeader",
+			reason: "value length must be at most 128 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for TenantPriorities
+
+	// no validation rules for DefaultPriority
+
+	// no validation rules for FailClosedOnUnknownLoad
 
 	if len(errors) > 0 {
-		return SubsetMultiError(errors)
+		return AdmissionConfigMultiError(errors)
 	}
 
 	return nil
 }
 
-// SubsetMultiError is an error wrapping multiple validation errors returned by
-// Subset.ValidateAll() if the designated constraints aren't met.
-type SubsetMultiError []error
+// AdmissionConfigMultiError is an error wrapping multiple validation errors
+// returned by AdmissionConfig.ValidateAll() if the designated constraints
+// aren't met.
+type AdmissionConfigMultiError []error
 
 // Error returns a concatenation of all the error messages it wraps.
-func (m SubsetMultiError) Error() string {
+func (m AdmissionConfigMultiError) Error() string {
 	var msgs []string
 	for _, err := range m {
 		msgs = append(msgs, err.Error())
@@ -812,11 +2883,11 @@ func (m SubsetMultiError) Error() string {
 }
 
 // AllErrors returns a list of validation violation errors.
-func (m SubsetMultiError) AllErrors() []error { return m }
+func (m AdmissionConfigMultiError) AllErrors() []error { return m }
 
-// SubsetValidationError is the validation error returned by Subset.Validate if
-// the designated constraints aren't met.
-type SubsetValidationError struct {
+// AdmissionConfigValidationError is the validation error returned by
+// AdmissionConfig.Validate if the designated constraints aren't met.
+type AdmissionConfigValidationError struct {
 	field  string
 	reason string
 	cause  error
@@ -824,22 +2895,22 @@ type SubsetValidationError struct {
 }
 
 // Field function returns field value.
-func (e SubsetValidationError) Field() string { return e.field }
+func (e AdmissionConfigValidationError) Field() string { return e.field }
 
 // Reason function returns reason value.
-func (e SubsetValidationError) Reason() string { return e.reason }
+func (e AdmissionConfigValidationError) Reason() string { return e.reason }
 
 // Cause function returns cause value.
-func (e SubsetValidationError) Cause() error { return e.cause }
+func (e AdmissionConfigValidationError) Cause() error { return e.cause }
 
 // Key function returns key value.
-func (e SubsetValidationError) Key() bool { return e.key }
+func (e AdmissionConfigValidationError) Key() bool { return e.key }
 
 // ErrorName returns error name.
-func (e SubsetValidationError) ErrorName() string { return "SubsetValidationError" }
+func (e AdmissionConfigValidationError) ErrorName() string { return "AdmissionConfigValidationError" }
 
 // Error satisfies the builtin error interface
-func (e SubsetValidationError) Error() string {
+func (e AdmissionConfigValidationError) Error() string {
 	cause := ""
 	if e.cause != nil {
 		cause = fmt.Sprintf(" | caused by: %v", e.cause)
@@ -851,14 +2922,14 @@ func (e SubsetValidationError) Error() string {
 	}
 
 	return fmt.Sprintf(
-		"invalid %sSubset.%s: %s%s",
+		"invalid %sAdmissionConfig.%s: %s%s",
 		key,
 		e.field,
 		e.reason,
 		cause)
 }
 
-var _ error = SubsetValidationError{}
+var _ error = AdmissionConfigValidationError{}
 
 var _ interface {
 	Field() string
@@ -866,7 +2937,7 @@ var _ interface {
 	Key() bool
 	Cause() error
 	ErrorName() string
-} = SubsetValidationError{}
+} = AdmissionConfigValidationError{}
 
 // Validate checks the field values on LogConfig with the rules defined in the
 // proto definition for this message. If any rules are violated, the first
@@ -894,6 +2965,73 @@ func (m *LogConfig) validate(all bool) error {
 
 	// no validation rules for Path
 
+	// no validation rules for IncludeRequest
+
+	// no validation rules for IncludeResponse
+
+	// no validation rules for IncludeThinking
+
+	if val := m.GetSampleRate(); val < 0 || val > 1 {
+		err := LogConfigValidationError{
+			field:  "SampleRate",
+			reason: "value must be inside range [0, 1]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	for idx, item := range m.GetRedactFields() {
+		_, _ = idx, item
+
+		if utf8.RuneCountInString(item) < 1 {
+			err := LogConfigValidationError{
+				field:  fmt.Sprintf("RedactFields[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	for idx, item := range m.GetSinks() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, LogConfigValidationError{
+						field:  fmt.Sprintf("Sinks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, LogConfigValidationError{
+						field:  fmt.Sprintf("Sinks[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return LogConfigValidationError{
+					field:  fmt.Sprintf("Sinks[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if len(errors) > 0 {
 		return LogConfigMultiError(errors)
 	}
@@ -970,3 +3108,206 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = LogConfigValidationError{}
+
+// Validate checks the field values on LogSink with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *LogSink) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on LogSink with the rules defined in the
+// proto definition for this message. If any rules are violated, the result is
+// a list of violation errors wrapped in LogSinkMultiError, or nil if none found.
+func (m *LogSink) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *LogSink) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if _, ok := _LogSink_Type_InLookup[m.GetType()]; !ok {
+		err := LogSinkValidationError{
+			field:  "Type",
+			reason: "value must be in list [file stdout unix tcp otlp]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Path
+
+	// no validation rules for Address
+
+	// no validation rules for QueueSize
+
+	if _, ok := _LogSink_OverflowPolicy_InLookup[m.GetOverflowPolicy()]; !ok {
+		err := LogSinkValidationError{
+			field:  "OverflowPolicy",
+			reason: "value must be in list [ drop_newest drop_oldest block]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if d := m.GetBlockTimeout(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = LogSinkValidationError{
+				field:  "BlockTimeout",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := LogSinkValidationError{
+					field:  "BlockTimeout",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	// no validation rules for BatchSize
+
+	if d := m.GetFlushInterval(); d != nil {
+		dur, err := d.AsDuration(), d.CheckValid()
+		if err != nil {
+			err = LogSinkValidationError{
+				field:  "FlushInterval",
+				reason: "value is not a valid duration",
+				cause:  err,
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+
+			gt := time.Duration(0*time.Second + 0*time.Nanosecond)
+
+			if dur <= gt {
+				err := LogSinkValidationError{
+					field:  "FlushInterval",
+					reason: "value must be greater than 0s",
+				}
+				if !all {
+					return err
+				}
+				errors = append(errors, err)
+			}
+
+		}
+	}
+
+	if len(errors) > 0 {
+		return LogSinkMultiError(errors)
+	}
+
+	return nil
+}
+
+// LogSinkMultiError is an error wrapping multiple validation errors returned
+// by LogSink.ValidateAll() if the designated constraints aren't met.
+type LogSinkMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m LogSinkMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m LogSinkMultiError) AllErrors() []error { return m }
+
+// LogSinkValidationError is the validation error returned by LogSink.Validate
+// if the designated constraints aren't met.
+type LogSinkValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e LogSinkValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e LogSinkValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e LogSinkValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e LogSinkValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e LogSinkValidationError) ErrorName() string { return "LogSinkValidationError" }
+
+// Error satisfies the builtin error interface
+func (e LogSinkValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sLogSink.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = LogSinkValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = LogSinkValidationError{}
+
+var _LogSink_Type_InLookup = map[string]struct{}{
+	"file":   {},
+	"stdout": {},
+	"unix":   {},
+	"tcp":    {},
+	"otlp":   {},
+}
+
+var _LogSink_OverflowPolicy_InLookup = map[string]struct{}{
+	"":            {},
+	"drop_newest": {},
+	"drop_oldest": {},
+	"block":       {},
+}
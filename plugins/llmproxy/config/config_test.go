@@ -0,0 +1,54 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+)
+
+func TestParseRejectsConflictingLogSinks(t *testing.T) {
+	newConfig := func(queueSize uint32) *LLMProxyConfig {
+		return &LLMProxyConfig{Config: Config{Log: &LogConfig{
+			Enabled: true,
+			Sinks: []*LogSink{{
+				Type:          "stdout",
+				QueueSize:     queueSize,
+				FlushInterval: durationpb.New(time.Hour),
+			}},
+		}}}
+	}
+
+	c := newConfig(10)
+	assert.NoError(t, c.Parse(nil))
+	assert.NoError(t, c.Init(nil))
+	t.Cleanup(func() { assert.NoError(t, c.AsyncLogger.Close(time.Second)) })
+
+	// the same settings share the sink
+	same := newConfig(10)
+	assert.NoError(t, same.Parse(nil))
+	assert.NoError(t, same.Init(nil))
+
+	// the opened sink can't be changed
+	assert.Error(t, newConfig(20).Parse(nil))
+	// the log is disabled
+	disabled := newConfig(20)
+	disabled.Log.Enabled = false
+	assert.NoError(t, disabled.Parse(nil))
+}
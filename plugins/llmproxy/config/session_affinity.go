@@ -0,0 +1,38 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "errors"
+
+// DefaultAffinityLoadFactor allows the host of a session to take 25% more requests than the average
+const DefaultAffinityLoadFactor = 0.25
+
+func validateSessionAffinity(affinity *SessionAffinity) error {
+	if affinity == nil {
+		return nil
+	}
+	if affinity.GetSource() == SessionAffinity_HEADER && affinity.GetHeader() == "" {
+		return errors.New("header is required for the session affinity on header")
+	}
+	return nil
+}
+
+// AffinityLoadFactor returns the load factor of the session affinity, 0 means the default
+func AffinityLoadFactor(affinity *SessionAffinity) float64 {
+	if f := affinity.GetLoadFactor(); f > 0 {
+		return f
+	}
+	return DefaultAffinityLoadFactor
+}
@@ -21,19 +21,23 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bytedance/sonic"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"mosn.io/htnn/api/pkg/filtermanager/api"
 
 	"github.com/aigw-project/aigw/pkg/aigateway"
+	"github.com/aigw-project/aigw/pkg/aigateway/admission"
 	"github.com/aigw-project/aigw/pkg/aigateway/discovery/common"
-	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer"
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/locality"
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/types"
 	"github.com/aigw-project/aigw/pkg/errcode"
+	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
 	"github.com/aigw-project/aigw/pkg/request"
-	"github.com/aigw-project/aigw/pkg/trace"
 	cfg "github.com/aigw-project/aigw/plugins/llmproxy/config"
 	"github.com/aigw-project/aigw/plugins/llmproxy/transcoder"
 	_ "github.com/aigw-project/aigw/plugins/llmproxy/transcoder/openai"
@@ -43,6 +47,8 @@ const (
 	LLMProxyFilterName = "llm-proxy"
 	AIModelName        = "ai_model_name"
 	TargetModelName    = "target_model_name"
+	// LLMLogItemsKey is the plugin state key of *log.LLMLogItems, which carries the token usage of the response
+	LLMLogItemsKey = "llm_log_items"
 )
 
 var (
@@ -60,6 +66,7 @@ type filter struct {
 	serverIp        string
 	backendProtocol string
 	modelName       string
+	sceneName       string
 	traceId         string
 	// drop response body
 	dropRespData bool
@@ -75,6 +82,7 @@ type filter struct {
 	lastRtTimestamp     int64
 	// metadata center
 	cluster               string
+	servingClusters       []locality.Cluster
 	promptLength          int
 	promptHash            []uint64
 	hitRadio              int
@@ -85,6 +93,39 @@ type filter struct {
 
 	// generated unique ID per request
 	uniqueId string
+	// the key hashed by the consistent hash and the session affinity
+	hashKey string
+
+	// retry
+	hostAddress   string
+	backupCluster string
+	// the host chosen by the gateway and the host which served the retried request, nil when the retry is disabled
+	attemptedHosts []string
+	retryCount     int
+	// the host the request is counted on, so the removed host is drained after the request finishes
+	trackedHost types.RequestTracker
+
+	// admission controller which admitted the request
+	admissionController *admission.Controller
+	// the stream may end while the request is waiting for the admission in DecodeRequest
+	admissionLock   sync.Mutex
+	admissionCancel context.CancelFunc
+	streamEnded     bool
+
+	// outlier detection
+	upstreamStatus  int
+	firstChunkError bool
+	isLocalError    bool
+
+	// tracing
+	traceCtx     context.Context
+	requestSpan  oteltrace.Span
+	upstreamSpan oteltrace.Span
+
+	// explanation of the load balancing decision, only set when requested
+	lbExplanation *inferencelb.Explanation
+	// load of the chosen host, filled by the load balancer when the hosts are ranked by load
+	chosenStats *mctypes.EndpointStats
 }
 
 func (f *filter) badRequest(err error) api.ResultAction {
@@ -102,17 +143,56 @@ func (f *filter) failedToConvertRequest(err error) api.ResultAction {
 	return aigateway.NewGatewayErrorResponseWithMsg(f.traceId, http.Header{}, 400, &errcode.BadRequestError, err.Error())
 }
 
-func (f *filter) initLoadBalanceContext() context.Context {
-	ctx := context.Background()
-	ctx = context.WithValue(ctx, inferencelb.KeyTraceId, f.traceId)
+func (f *filter) initLoadBalanceContext(parent context.Context) context.Context {
+	ctx := context.WithValue(parent, inferencelb.KeyTraceId, f.traceId)
 	ctx = context.WithValue(ctx, inferencelb.KeyModelName, f.modelName)
 	ctx = context.WithValue(ctx, inferencelb.KeyFilterCallback, f.callbacks)
+	f.chosenStats = &mctypes.EndpointStats{}
+	ctx = context.WithValue(ctx, inferencelb.KeyChosenStats, f.chosenStats)
 
 	ctx = f.setLoadBalanceConfig(ctx, f.modelName)
 	ctx = f.setPromptsContext(ctx)
+	ctx = f.setHashKey(ctx)
 	return ctx
 }
 
+// setHashKey sets the key hashed by the ConsistentHash algorithm, and enables the session affinity when it's configured
+func (f *filter) setHashKey(ctx context.Context) context.Context {
+	if f.hashKey == "" {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, inferencelb.KeyHashKey, f.hashKey)
+	if affinity := f.sessionAffinity(); affinity != nil {
+		ctx = context.WithValue(ctx, inferencelb.KeyAffinityLoadFactor, cfg.AffinityLoadFactor(affinity))
+	}
+	return ctx
+}
+
+func (f *filter) sessionAffinity() *cfg.SessionAffinity {
+	return f.config.FindLbMappingRule(f.modelName).GetSessionAffinity()
+}
+
+// requestHashKey returns the session key of the request when the session affinity of the model is configured,
+// otherwise the value of the hash header
+func (f *filter) requestHashKey(headers api.RequestHeaderMap, reqData *transcoder.RequestData) string {
+	name := f.config.GetHashHeader()
+	if affinity := f.sessionAffinity(); affinity != nil {
+		switch affinity.GetSource() {
+		case cfg.SessionAffinity_USER:
+			return reqData.User
+		case cfg.SessionAffinity_CONVERSATION:
+			return reqData.ConversationID
+		default:
+			name = affinity.GetHeader()
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	key, _ := headers.Get(name)
+	return key
+}
+
 func (f *filter) DecodeHeaders(header api.RequestHeaderMap, endStream bool) api.ResultAction {
 	if endStream {
 		return f.badRequest(fmt.Errorf("no data"))
@@ -122,8 +202,8 @@ func (f *filter) DecodeHeaders(header api.RequestHeaderMap, endStream bool) api.
 }
 
 func (f *filter) DecodeRequest(headers api.RequestHeaderMap, buffer api.BufferInstance, trailers api.RequestTrailerMap) api.ResultAction {
-	traceId := trace.GetTraceID(f.callbacks, headers)
-	f.traceId = traceId
+	f.startTrace(headers)
+	traceId := f.traceId
 	f.reqHdr = headers
 	inputProtocol := f.config.Protocol
 	transcoderFactory := transcoder.GetTranscoderFactory(inputProtocol)
@@ -131,8 +211,11 @@ func (f *filter) DecodeRequest(headers api.RequestHeaderMap, buffer api.BufferIn
 		return f.badRequest(fmt.Errorf("transcoder not found for protocol %s", inputProtocol))
 	}
 	f.transcoder = transcoderFactory(f.callbacks, f.config)
+	f.callbacks.PluginState().Set(LLMProxyFilterName, LLMLogItemsKey, f.transcoder.GetLLMLogItems())
 
+	_, span := f.startSpan(spanTranscodeReq)
 	reqData, err := f.transcoder.GetRequestData(headers, buffer.Bytes())
+	endSpan(span, err)
 	if err != nil {
 		if errors.Is(err, aigateway.ModelNotExistError) {
 			return aigateway.NewGatewayErrorResponseWithMsg(f.traceId, http.Header{}, 404, &errcode.NotFoundError, err.Error())
@@ -143,6 +226,7 @@ func (f *filter) DecodeRequest(headers api.RequestHeaderMap, buffer api.BufferIn
 	modelName := reqData.ModelName
 	f.modelName = modelName
 	sceneName := reqData.SceneName
+	f.sceneName = sceneName
 	backendProtocol := reqData.BackendProtocol
 	f.backendProtocol = backendProtocol
 	env := reqData.Env
@@ -166,11 +250,24 @@ func (f *filter) DecodeRequest(headers api.RequestHeaderMap, buffer api.BufferIn
 	// Will be used in access_log
 	request.SetLogField(f.callbacks, TargetModelName, sceneName)
 
-	f.cluster = reqData.Cluster
+	f.servingClusters = servingClusters(reqData)
+	// the preferred cluster, updated to the chosen one
+	f.cluster = f.servingClusters[0].Name
+	f.backupCluster = reqData.BackupCluster
+	f.hashKey = f.requestHashKey(headers, reqData)
+
+	if res := f.admit(headers); res != nil {
+		return res
+	}
 
-	ctx := f.initLoadBalanceContext()
-	algorithm := f.config.GetAlgorithm()
-	host, err := loadbalancer.ChooseServer(ctx, f.callbacks, headers, f.cluster, types.LoadBalancerType(algorithm))
+	lbCtx, span := f.startSpan(spanLoadBalance, oteltrace.WithAttributes(attribute.String(attrCluster, f.cluster)))
+	ctx := f.initLoadBalanceContext(lbCtx)
+	ctx = f.withLBExplain(ctx, headers)
+	host, err := f.chooseServer(ctx, headers)
+	if err == nil {
+		span.SetAttributes(attribute.String(attrCluster, f.cluster), attribute.String(attrHost, host.Address()))
+	}
+	endSpan(span, err)
 	if err != nil {
 		api.LogErrorf("choose server address error, err: %v", err)
 		return f.noUpstream(err)
@@ -178,6 +275,8 @@ func (f *filter) DecodeRequest(headers api.RequestHeaderMap, buffer api.BufferIn
 	api.LogDebugf("server address: %s, err: %v", host.Ip(), err)
 
 	f.serverIp = host.Ip()
+	f.hostAddress = host.Address()
+	f.trackHost(host)
 	request.SetLogField(f.callbacks, "ai_backend_protocol", backendProtocol)
 
 	proxyModelName := common.DefaultModelName
@@ -185,7 +284,9 @@ func (f *filter) DecodeRequest(headers api.RequestHeaderMap, buffer api.BufferIn
 		proxyModelName = lbOptions.GetLoraID()
 	}
 
+	_, span = f.startSpan(spanEncodeReq)
 	reqCtx, err := f.transcoder.EncodeRequest(proxyModelName, backendProtocol, headers, buffer)
+	endSpan(span, err)
 	if err != nil {
 		return f.failedToConvertRequest(err)
 	}
@@ -195,8 +296,11 @@ func (f *filter) DecodeRequest(headers api.RequestHeaderMap, buffer api.BufferIn
 	// must set before AddRequest, since it will be used in AddRequest
 	f.isStream = reqCtx.IsStream
 	f.AddRequest()
+	f.startUpstreamSpan(headers)
 	f.setSendFinishTimestamp()
 
+	f.setRetryPolicy(headers)
+
 	return api.Continue
 }
 
@@ -220,12 +324,16 @@ func (f *filter) badResponseInternal(err error, status int, header http.Header)
 
 func (f *filter) addCommonResponseHeaders(headers api.ResponseHeaderMap) {
 	headers.Add("x-aigw-via", HOSTNAME)
+	f.addLBExplainHeader(headers)
 }
 
 func (f *filter) EncodeHeaders(header api.ResponseHeaderMap, endStream bool) api.ResultAction {
 	f.addCommonResponseHeaders(header)
 
+	f.reconcileRetries()
+
 	status, _ := header.Status()
+	f.upstreamStatus = status
 	if status >= http.StatusBadRequest {
 		api.LogInfof("ai proxy decode headers for error response, status=%d", status)
 		return api.WaitAllData
@@ -283,6 +391,7 @@ func (f *filter) EncodeResponse(headers api.ResponseHeaderMap, buffer api.Buffer
 		if f.isLocalReplay() { // error response from plugin with whole replay by WaitAllData returned in EncodeHeaders
 			code = errcode.ConvertStatusToErrorCode(status)
 			request.SetLogField(f.callbacks, "is_local_error", 1)
+			f.isLocalError = true
 			f.setLlmErrorMessage(code.Msg)
 			return aigateway.NewGatewayErrorResponse(f.traceId, headers.GetAllHeaders(), status, code)
 		} else { // error response from inference server
@@ -330,6 +439,7 @@ func (f *filter) doRespData(headers api.ResponseHeaderMap, buffer api.BufferInst
 	// overwrite the status to 400 when got first chunk error message, in stream response
 	if f.isStream && err != nil {
 		headers.Set(":status", "400")
+		f.firstChunkError = true
 		f.isStream = false
 		// drop the remaining response data
 		f.dropRespData = true
@@ -363,6 +473,10 @@ func (f *filter) OnLog(reqHeaders api.RequestHeaderMap, reqTrailers api.RequestT
 	if f.isIncreaseRecorded {
 		f.DecreaseMetaDataCenter()
 	}
+	f.recordOutlierResult()
+	// OnLog is also called when the downstream resets
+	f.endAdmission()
+	f.trackHost(nil)
 
 	request.SetLogField(f.callbacks, "ttft", f.getTtft().Milliseconds())
 	if f.fistRtTimestamp != 0 {
@@ -379,7 +493,21 @@ func (f *filter) OnLog(reqHeaders api.RequestHeaderMap, reqTrailers api.RequestT
 		f.callbacks.StreamInfo().DynamicMetadata().Set("htnn", "access_log", logField)
 	}
 
-	// TODO: wirte llm log
+	f.writeLLMLog(respHeaders)
+	f.recordMetrics(respHeaders)
+	f.finishTrace(respHeaders)
+}
+
+// trackHost counts the request in flight on the host, and stops counting it on the previous host
+func (f *filter) trackHost(host types.Host) {
+	if f.trackedHost != nil {
+		f.trackedHost.RequestFinished()
+		f.trackedHost = nil
+	}
+	if tracker, ok := host.(types.RequestTracker); ok {
+		tracker.RequestStarted()
+		f.trackedHost = tracker
+	}
 }
 
 func (f *filter) setLlmErrorMessage(msg string) {
@@ -398,6 +526,7 @@ func (f *filter) setSendFinishTimestamp() {
 func (f *filter) setTokenTimestamp() {
 	if f.fistRtTimestamp == 0 {
 		f.fistRtTimestamp = time.Now().UnixMicro()
+		f.traceFirstToken()
 		// only decrease prompt length when fist token is received
 		f.DeletePromptLength()
 	}
@@ -0,0 +1,47 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"context"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
+)
+
+const LBExplainHeader = "x-aigw-lb-explain"
+
+// withLBExplain asks the load balancer to explain the decision when it's enabled and requested
+func (f *filter) withLBExplain(ctx context.Context, headers api.RequestHeaderMap) context.Context {
+	if !f.config.GetEnableLbExplain() {
+		return ctx
+	}
+	if _, ok := headers.Get(LBExplainHeader); !ok {
+		return ctx
+	}
+	// don't forward the debug header to upstream
+	headers.Del(LBExplainHeader)
+	f.lbExplanation = &inferencelb.Explanation{}
+	return context.WithValue(ctx, inferencelb.KeyExplain, f.lbExplanation)
+}
+
+func (f *filter) addLBExplainHeader(headers api.ResponseHeaderMap) {
+	// the explanation is only filled by the inference load balancer
+	if f.lbExplanation == nil || f.lbExplanation.Chosen == "" {
+		return
+	}
+	headers.Set(LBExplainHeader, f.lbExplanation.String())
+}
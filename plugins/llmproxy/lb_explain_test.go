@@ -0,0 +1,75 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
+)
+
+func TestLBExplainHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		requested bool
+		// the explanation is only filled by the inference load balancer
+		notFilled bool
+		want      bool
+	}{
+		{name: "enabled and requested", enabled: true, requested: true, want: true},
+		{name: "not requested", enabled: true},
+		{name: "not enabled", requested: true},
+		{name: "not filled", enabled: true, requested: true, notFilled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := newRetryFilter(nil)
+			f.config.EnableLbExplain = tt.enabled
+			hdr := http.Header{}
+			if tt.requested {
+				hdr.Set(LBExplainHeader, "1")
+			}
+			headers := envoy.NewRequestHeaderMap(hdr)
+
+			ctx := f.withLBExplain(context.Background(), headers)
+			explanation, ok := ctx.Value(inferencelb.KeyExplain).(*inferencelb.Explanation)
+			assert.Equal(t, tt.enabled && tt.requested, ok)
+			if ok && !tt.notFilled {
+				explanation.Cluster = "qwen"
+				explanation.Strategy = inferencelb.StrategyScore
+				explanation.Chosen = "10.0.0.1:8000"
+			}
+			if tt.enabled {
+				// the debug header is not forwarded to upstream
+				_, found := headers.Get(LBExplainHeader)
+				assert.False(t, found)
+			}
+
+			respHeaders := envoy.NewResponseHeaderMap(http.Header{})
+			f.addLBExplainHeader(respHeaders)
+			value, found := respHeaders.Get(LBExplainHeader)
+			assert.Equal(t, tt.want, found)
+			if tt.want {
+				assert.JSONEq(t, `{"cluster":"qwen","strategy":"score","hosts":0,"cand_num":0,"chosen":"10.0.0.1:8000"}`, value)
+			}
+		})
+	}
+}
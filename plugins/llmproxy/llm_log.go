@@ -0,0 +1,104 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	llmlog "github.com/aigw-project/aigw/plugins/llmproxy/log"
+)
+
+const llmLogTimeFormat = "2006-01-02 15:04:05.999999"
+
+func formatMicroTimestamp(ts int64) string {
+	if ts == 0 {
+		return ""
+	}
+	return time.UnixMicro(ts).Format(llmLogTimeFormat)
+}
+
+// shouldWriteLLMLog checks the sample rate, the failed requests are always logged
+func (f *filter) shouldWriteLLMLog(status int, logItems *llmlog.LLMLogItems) bool {
+	if f.config.AsyncLogger == nil {
+		return false
+	}
+	if status >= http.StatusBadRequest || logItems.GetErrorMessage() != "" {
+		return true
+	}
+	rate := f.config.GetLog().GetSampleRate()
+	return rate == 0 || rate >= 1 || rand.Float64() < rate
+}
+
+// writeLLMLog writes the LLM log record of the request
+func (f *filter) writeLLMLog(respHeaders api.ResponseHeaderMap) {
+	if f.transcoder == nil {
+		// rejected before the request is parsed
+		return
+	}
+	logItems := f.transcoder.GetLLMLogItems()
+	if logItems == nil {
+		return
+	}
+
+	status := f.upstreamStatus
+	if respHeaders != nil {
+		if s, ok := respHeaders.Status(); ok {
+			status = s
+		}
+	}
+	if !f.shouldWriteLLMLog(status, logItems) {
+		return
+	}
+
+	logConfig := f.config.GetLog()
+	record := &llmlog.LLMLogRecord{
+		Timestamp:      time.Now().Format(llmLogTimeFormat),
+		TraceId:        f.traceId,
+		Model:          f.modelName,
+		Scene:          f.sceneName,
+		Env:            logItems.GetEnv(),
+		Cluster:        f.cluster,
+		Host:           f.hostAddress,
+		Stream:         f.isStream,
+		Status:         status,
+		TTFTMs:         f.getTtft().Milliseconds(),
+		FirstTokenTime: formatMicroTimestamp(f.fistRtTimestamp),
+		LastTokenTime:  formatMicroTimestamp(f.lastRtTimestamp),
+		CacheHitRatio:  f.hitRadio,
+		RetryCount:     f.retryCount,
+		ErrorMessage:   logItems.GetErrorMessage(),
+	}
+	record.SetUsage(logItems.GetUsage())
+	if logConfig.GetIncludeRequest() {
+		record.SetRequest(logItems.RawRequest)
+	}
+	if logConfig.GetIncludeResponse() {
+		record.Response = logItems.GetResponse()
+	}
+	if logConfig.GetIncludeThinking() {
+		record.Thinking = logItems.GetThinkingResponse()
+	}
+
+	data, err := record.Marshal(f.config.RedactFields)
+	if err != nil {
+		api.LogErrorf("failed to marshal llm log, trace_id: %s, err: %v", f.traceId, err)
+		return
+	}
+	f.config.AsyncLogger.Write(data)
+}
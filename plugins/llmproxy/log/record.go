@@ -0,0 +1,118 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"encoding/json"
+)
+
+const RedactedValue = "***"
+
+type RecordUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+	TotalTokens      int64 `json:"total_tokens"`
+	CachedTokens     int64 `json:"cached_tokens"`
+}
+
+// LLMLogRecord is a line in the LLM log
+type LLMLogRecord struct {
+	Timestamp string `json:"timestamp"`
+	TraceId   string `json:"trace_id"`
+	Model     string `json:"model"`
+	Scene     string `json:"scene,omitempty"`
+	Env       string `json:"env,omitempty"`
+	Cluster   string `json:"cluster,omitempty"`
+	Host      string `json:"host,omitempty"`
+	Stream    bool   `json:"stream"`
+	Status    int    `json:"status"`
+
+	TTFTMs         int64  `json:"ttft_ms"`
+	FirstTokenTime string `json:"first_token_time,omitempty"`
+	LastTokenTime  string `json:"last_token_time,omitempty"`
+
+	Usage RecordUsage `json:"usage"`
+	// percentage of the prompt hit in KV cache, reported by metadata center
+	CacheHitRatio int `json:"cache_hit_ratio"`
+	RetryCount    int `json:"retry_count,omitempty"`
+
+	ErrorMessage string `json:"error_message,omitempty"`
+
+	// the request is logged as JSON object when it's valid JSON, otherwise as string
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response string          `json:"response,omitempty"`
+	Thinking string          `json:"thinking,omitempty"`
+}
+
+// SetUsage fills the usage from the token usage of log items
+func (r *LLMLogRecord) SetUsage(usage TokenUsage) {
+	r.Usage = RecordUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CachedTokens:     usage.CachedTokens,
+	}
+}
+
+// SetRequest sets the raw request body
+func (r *LLMLogRecord) SetRequest(req []byte) {
+	if len(req) == 0 {
+		return
+	}
+	if json.Valid(req) {
+		r.Request = req
+		return
+	}
+	r.Request, _ = json.Marshal(string(req))
+}
+
+// Marshal serializes the record into a JSON line, the values of redactFields are replaced in any level
+func (r *LLMLogRecord) Marshal(redactFields map[string]struct{}) ([]byte, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(redactFields) > 0 {
+		var m map[string]any
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		redact(m, redactFields)
+		data, err = json.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(data, '\n'), nil
+}
+
+func redact(v any, fields map[string]struct{}) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if _, ok := fields[k]; ok {
+				val[k] = RedactedValue
+				continue
+			}
+			redact(child, fields)
+		}
+	case []any:
+		for _, child := range val {
+			redact(child, fields)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLLMLogRecordMarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		request string
+		redact  map[string]struct{}
+		want    string
+	}{
+		{
+			name:    "json request",
+			request: `{"model":"qwen3","messages":[{"role":"user","content":"hi"}]}`,
+			want:    `{"timestamp":"","trace_id":"t","model":"qwen3","host":"1.1.1.1:80","stream":false,"status":200,"ttft_ms":0,"usage":{"prompt_tokens":1,"completion_tokens":2,"total_tokens":3,"cached_tokens":0},"cache_hit_ratio":0,"request":{"model":"qwen3","messages":[{"role":"user","content":"hi"}]}}` + "\n",
+		},
+		{
+			name:    "invalid json request",
+			request: `not json`,
+			want:    `{"timestamp":"","trace_id":"t","model":"qwen3","host":"1.1.1.1:80","stream":false,"status":200,"ttft_ms":0,"usage":{"prompt_tokens":1,"completion_tokens":2,"total_tokens":3,"cached_tokens":0},"cache_hit_ratio":0,"request":"not json"}` + "\n",
+		},
+		{
+			name:    "redact",
+			request: `{"model":"qwen3","messages":[{"role":"user","content":"hi"}]}`,
+			redact: map[string]struct{}{
+				"host":    {},
+				"content": {},
+			},
+			want: `{"cache_hit_ratio":0,"host":"***","model":"qwen3","request":{"messages":[{"content":"***","role":"user"}],"model":"qwen3"},"status":200,"stream":false,"timestamp":"","trace_id":"t","ttft_ms":0,"usage":{"cached_tokens":0,"completion_tokens":2,"prompt_tokens":1,"total_tokens":3}}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &LLMLogRecord{
+				TraceId: "t",
+				Model:   "qwen3",
+				Host:    "1.1.1.1:80",
+				Status:  200,
+			}
+			r.SetUsage(TokenUsage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3})
+			r.SetRequest([]byte(tt.request))
+			data, err := r.Marshal(tt.redact)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/inferencelb"
 	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
 	"github.com/aigw-project/aigw/pkg/metrics_stats"
+	"github.com/aigw-project/aigw/pkg/prediction"
 	"github.com/aigw-project/aigw/pkg/request"
 	"github.com/aigw-project/aigw/plugins/llmproxy/transcoder"
 )
@@ -53,7 +54,7 @@ func (f *filter) AddRequest() {
 	f.isIncreaseRecorded = true
 
 	if !f.isStream {
-		ttft := metrics_stats.MatchTTFT(f.modelName, f.promptLength)
+		ttft := f.predictTTFT()
 		ms := time.Duration(ttft*12/10) * time.Millisecond
 
 		api.LogDebugf("non-stream request, start prompt decrease timer, model name: %s, trace id=%s, predict ttft=%dms", f.modelName, f.traceId, ttft)
@@ -63,6 +64,23 @@ func (f *filter) AddRequest() {
 	}
 }
 
+// predictTTFT predicts the TTFT on the chosen host. The predictors are trained with the prompt tokens in the usage,
+// while the prompt is not tokenized yet, so the tokens are estimated by the prompt length
+func (f *filter) predictTTFT() int64 {
+	return metrics_stats.MatchTTFTWithLoad(f.modelName, metrics_stats.EstimateTokens(f.promptLength), f.hostLoad())
+}
+
+// hostLoad returns the load of the chosen host when it's chosen, zero if the hosts are not ranked by load
+func (f *filter) hostLoad() prediction.Load {
+	if f.chosenStats == nil {
+		return prediction.Load{}
+	}
+	return prediction.Load{
+		QueueDepth: f.chosenStats.PrefillReqs,
+		BatchSize:  f.chosenStats.TotalReqs,
+	}
+}
+
 func (f *filter) DecreaseMetaDataCenter() {
 	if !f.isModelLoadAwareEnable() {
 		api.LogDebugf("metadata center load aware is not enable, model name: %s", f.modelName)
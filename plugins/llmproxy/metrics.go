@@ -0,0 +1,96 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"net/http"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/metrics_stats"
+	"github.com/aigw-project/aigw/pkg/prom"
+)
+
+const (
+	errorTypeGateway       = "gateway"
+	errorTypeLocal         = "local"
+	errorTypeUpstreamReset = "upstream_reset"
+	errorTypeUpstream5xx   = "upstream_5xx"
+	errorTypeUpstream4xx   = "upstream_4xx"
+	errorTypeFirstChunk    = "first_chunk"
+)
+
+// errorType classifies the failure of the request, returns empty string if the request succeeded
+func (f *filter) errorType(status int) string {
+	switch {
+	case f.isLocalError:
+		return errorTypeLocal
+	case f.hostAddress == "":
+		// rejected before choosing the upstream, e.g. no upstream or admission rejected
+		if status >= http.StatusBadRequest {
+			return errorTypeGateway
+		}
+		return ""
+	case f.upstreamStatus == 0:
+		return errorTypeUpstreamReset
+	case f.upstreamStatus >= http.StatusInternalServerError:
+		return errorTypeUpstream5xx
+	case f.upstreamStatus >= http.StatusBadRequest:
+		return errorTypeUpstream4xx
+	case f.firstChunkError:
+		return errorTypeFirstChunk
+	}
+	return ""
+}
+
+// recordMetrics records the LLM metrics of the request and feeds the TTFT predictor, it's called in OnLog
+func (f *filter) recordMetrics(respHeaders api.ResponseHeaderMap) {
+	if f.transcoder == nil || f.modelName == "" {
+		// rejected before the request is parsed
+		return
+	}
+
+	status := f.upstreamStatus
+	if respHeaders != nil {
+		if s, ok := respHeaders.Status(); ok {
+			status = s
+		}
+	}
+
+	r := &prom.LLMRequest{
+		Model:               f.modelName,
+		Cluster:             f.cluster,
+		Backend:             f.backendProtocol,
+		Stream:              f.isStream,
+		ErrorType:           f.errorType(status),
+		SendFinishTimestamp: f.sendFinishTimestamp,
+		FirstTokenTimestamp: f.fistRtTimestamp,
+		LastTokenTimestamp:  f.lastRtTimestamp,
+	}
+	if logItems := f.transcoder.GetLLMLogItems(); logItems != nil {
+		usage := logItems.GetUsage()
+		r.PromptTokens = usage.PromptTokens
+		r.CompletionTokens = usage.CompletionTokens
+		r.CachedTokens = usage.CachedTokens
+	}
+	prom.ObserveLLMRequest(r)
+
+	// only the streaming responses are used to train the TTFT predictor,
+	// since the first chunk of the non-streaming response arrives after the whole completion is decoded.
+	// The retried requests are skipped, since their TTFT includes the failed attempts
+	if r.ErrorType == "" && f.isStream && f.retryCount == 0 {
+		metrics_stats.RecordTTFTAsync(f.modelName, int(r.PromptTokens), int(r.CachedTokens), f.hostLoad(), f.getTtft().Milliseconds())
+	}
+}
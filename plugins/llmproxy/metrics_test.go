@@ -0,0 +1,79 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"testing"
+	"time"
+
+	openaigo "github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aigw-project/aigw/plugins/llmproxy/log"
+	"github.com/aigw-project/aigw/plugins/llmproxy/transcoder"
+)
+
+type usageTranscoder struct {
+	transcoder.Transcoder
+	logItems *log.LLMLogItems
+}
+
+func (t *usageTranscoder) GetLLMLogItems() *log.LLMLogItems {
+	return t.logItems
+}
+
+// TestTTFTTrainAndPredict trains the TTFT predictor by the usage of the responses,
+// and predicts by the prompt length of the requests, both through the filter
+func TestTTFTTrainAndPredict(t *testing.T) {
+	const (
+		model = "ttft-train-and-predict"
+		// a prompt of 16000 bytes is about 4000 tokens
+		promptBytes  = 16000
+		promptTokens = 4000
+		ttft         = 2000
+	)
+
+	newFilter := func() *filter {
+		f, _ := newRetryFilter(nil)
+		f.modelName = model
+		f.promptLength = promptBytes
+		return f
+	}
+
+	for range 200 {
+		f := newFilter()
+		logItems := &log.LLMLogItems{}
+		logItems.AppendOpenAIResponse(&openaigo.Completion{
+			Usage: openaigo.CompletionUsage{
+				PromptTokens:        promptTokens,
+				CompletionTokens:    1,
+				TotalTokens:         promptTokens + 1,
+				PromptTokensDetails: &openaigo.CompletionUsagePromptTokensDetails{},
+			},
+		})
+		f.transcoder = &usageTranscoder{logItems: logItems}
+		f.upstreamStatus = 200
+		now := time.Now()
+		f.sendFinishTimestamp = now.UnixMicro()
+		f.fistRtTimestamp = now.Add(ttft * time.Millisecond).UnixMicro()
+		f.recordMetrics(nil)
+	}
+
+	// the samples are trained asynchronously
+	assert.Eventually(t, func() bool {
+		predicted := newFilter().predictTTFT()
+		return predicted > ttft*9/10 && predicted < ttft*11/10
+	}, 5*time.Second, 10*time.Millisecond)
+}
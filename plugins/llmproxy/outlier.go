@@ -0,0 +1,79 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/outlier"
+)
+
+// recordOutlierError reports the failure of the current host to the outlier detector
+func (f *filter) recordOutlierError(kind outlier.ErrorKind) {
+	if f.hostAddress == "" {
+		return
+	}
+	outlier.GetDetector().RecordError(f.cluster, f.hostAddress, kind)
+}
+
+// upstreamFailureDetails are the prefixes of the response code details of Envoy,
+// when the upstream times out or resets the stream
+var upstreamFailureDetails = []string{
+	"upstream_response_timeout",
+	"upstream_per_try_timeout",
+	"upstream_per_try_idle_timeout",
+	"upstream_max_stream_duration_reached",
+	"upstream_reset_before_response_started",
+	"upstream_reset_after_response_started",
+}
+
+func isUpstreamFailure(details string) bool {
+	for _, prefix := range upstreamFailureDetails {
+		if strings.HasPrefix(details, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordOutlierResult reports the final result of the current host to the outlier detector.
+// The downstream aborts and the local replies are not the failures of the host, so they are skipped.
+func (f *filter) recordOutlierResult() {
+	if f.hostAddress == "" || f.isLocalError {
+		return
+	}
+
+	details, _ := f.callbacks.StreamInfo().ResponseCodeDetails()
+	switch {
+	case isUpstreamFailure(details):
+		f.recordOutlierError(outlier.ErrorKindTimeout)
+	case f.upstreamStatus == 0:
+		// no response from upstream, e.g. the downstream aborts
+	case details != "" && details != "via_upstream":
+		// the local reply of Envoy, e.g. no healthy upstream
+	case f.upstreamStatus >= http.StatusInternalServerError:
+		f.recordOutlierError(outlier.ErrorKindStatus)
+	case f.firstChunkError:
+		f.recordOutlierError(outlier.ErrorKindFirstChunk)
+	case f.upstreamStatus < http.StatusBadRequest:
+		detector := outlier.GetDetector()
+		detector.RecordSuccess(f.cluster, f.hostAddress)
+		if f.retryCount == 0 {
+			// the TTFT of the retried request includes the failed attempts
+			detector.RecordTTFT(f.cluster, f.hostAddress, f.getTtft())
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/outlier"
+)
+
+type detailsStreamInfo struct {
+	*envoy.StreamInfo
+	details string
+}
+
+func (i *detailsStreamInfo) ResponseCodeDetails() (string, bool) {
+	return i.details, i.details != ""
+}
+
+func TestRecordOutlierResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		details    string
+		localError bool
+		firstChunk bool
+		// -1: not recorded, 0: success, 1: error
+		want int
+	}{
+		{name: "success", status: 200, details: "via_upstream", want: 0},
+		{name: "upstream 5xx", status: 503, details: "via_upstream", want: 1},
+		{name: "status without details", status: 502, want: 1},
+		{name: "first chunk error", status: 200, details: "via_upstream", firstChunk: true, want: 1},
+		{name: "upstream timeout", status: 504, details: "upstream_response_timeout", want: 1},
+		{name: "per try timeout", status: 504, details: "upstream_per_try_timeout", want: 1},
+		{name: "reset before response", status: 503, details: "upstream_reset_before_response_started{connection_termination}", want: 1},
+		{name: "reset while streaming", status: 200, details: "upstream_reset_after_response_started{remote_reset}", want: 1},
+		{name: "downstream abort", details: "downstream_remote_disconnect", want: -1},
+		{name: "no response", want: -1},
+		{name: "no healthy upstream", status: 503, details: "no_healthy_upstream", want: -1},
+		{name: "local reply of the plugin", status: 429, details: "via_upstream", localError: true, want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := newRetryFilter(nil)
+			f.cluster = "outlier-" + tt.name
+			f.upstreamStatus = tt.status
+			f.isLocalError = tt.localError
+			f.firstChunkError = tt.firstChunk
+			f.callbacks.(interface{ SetStreamInfo(api.StreamInfo) }).SetStreamInfo(&detailsStreamInfo{
+				StreamInfo: &envoy.StreamInfo{},
+				details:    tt.details,
+			})
+
+			f.recordOutlierResult()
+
+			var status *outlier.HostStatus
+			for _, s := range outlier.GetDetector().Status() {
+				if s.Cluster == f.cluster {
+					status = &s
+				}
+			}
+			if tt.want < 0 {
+				assert.Nil(t, status)
+				return
+			}
+			if assert.NotNil(t, status) {
+				assert.Equal(t, 1, status.WindowRequests)
+				assert.Equal(t, tt.want, status.WindowErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/outlier"
+	"github.com/aigw-project/aigw/pkg/request"
+	cfg "github.com/aigw-project/aigw/plugins/llmproxy/config"
+)
+
+// the request headers which configure the retries of the Envoy router
+const (
+	headerRetryOn              = "x-envoy-retry-on"
+	headerRetriableStatusCodes = "x-envoy-retriable-status-codes"
+	headerMaxRetries           = "x-envoy-max-retries"
+	headerPerTryTimeout        = "x-envoy-upstream-rq-per-try-timeout-ms"
+)
+
+const (
+	defaultPerTryTimeout = 60 * time.Second
+
+	// retryOnNotProcessed are the failures which mean the request is not processed by the upstream
+	retryOnNotProcessed = "connect-failure,refused-stream,retriable-status-codes"
+	// retryOnIdempotent retries the reset too, which may happen after the request is processed
+	retryOnIdempotent = retryOnNotProcessed + ",reset"
+)
+
+var defaultRetriableStatusCodes = []uint32{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+func (f *filter) retryPolicy() *cfg.RetryPolicy {
+	return f.config.GetRetryPolicy()
+}
+
+func retriableStatusCodes(policy *cfg.RetryPolicy) string {
+	codes := policy.GetRetriableStatusCodes()
+	if len(codes) == 0 {
+		codes = defaultRetriableStatusCodes
+	}
+	s := make([]string, 0, len(codes))
+	for _, code := range codes {
+		s = append(s, strconv.Itoa(int(code)))
+	}
+	return strings.Join(s, ",")
+}
+
+func perTryTimeout(policy *cfg.RetryPolicy) time.Duration {
+	if d := policy.GetPerTryTimeout(); d != nil {
+		return d.AsDuration()
+	}
+	return defaultPerTryTimeout
+}
+
+// retryOn returns the retry conditions of the method. The non-idempotent requests, like the completions,
+// are only retried when the upstream doesn't process them, so the prompt won't be inferred twice.
+func retryOn(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return retryOnIdempotent
+	default:
+		return retryOnNotProcessed
+	}
+}
+
+// setRetryPolicy asks the Envoy router to retry the request. The host chosen by the gateway is only used by the
+// first attempt, the retries are load balanced by Envoy, and reconciled by reconcileRetries when the response arrives.
+// The concurrent retries are limited by the retry budget of the cluster. Envoy doesn't expose the hosts of the
+// intermediate attempts, so only the first host and the host which served the response are known.
+func (f *filter) setRetryPolicy(headers api.RequestHeaderMap) {
+	policy := f.retryPolicy()
+	if policy.GetNumRetries() == 0 {
+		return
+	}
+	headers.Set(headerRetryOn, retryOn(headers.Method()))
+	headers.Set(headerRetriableStatusCodes, retriableStatusCodes(policy))
+	headers.Set(headerMaxRetries, strconv.Itoa(int(policy.GetNumRetries())))
+	headers.Set(headerPerTryTimeout, strconv.FormatInt(perTryTimeout(policy).Milliseconds(), 10))
+	f.attemptedHosts = []string{f.hostAddress}
+}
+
+// reconcileRetries moves the accounting of the request to the host which served the response,
+// when the request is retried by the Envoy router on another host
+func (f *filter) reconcileRetries() {
+	if f.attemptedHosts == nil {
+		return
+	}
+	info := f.callbacks.StreamInfo()
+	attempts := int(info.AttemptCount())
+	if attempts <= 1 {
+		return
+	}
+	f.retryCount = attempts - 1
+	request.SetLogField(f.callbacks, "retry_count", f.retryCount)
+
+	address, ok := info.UpstreamRemoteAddress()
+	switch {
+	case !ok || address == "":
+	case address == f.hostAddress:
+		api.LogWarnf("request is retried on the same host %s in cluster %s, the route may miss the previous_hosts "+
+			"retry host predicate, trace_id: %s", address, f.cluster, f.traceId)
+	default:
+		api.LogInfof("request is retried from %s to %s in cluster %s, attempts: %d, trace_id: %s",
+			f.hostAddress, address, f.cluster, attempts, f.traceId)
+		// the first attempt failed, either with a retriable status or without a response, Envoy doesn't tell which
+		f.recordOutlierError(outlier.ErrorKindRetried)
+		f.switchHost(address)
+	}
+	request.SetLogField(f.callbacks, "retry_hosts", strings.Join(f.attemptedHosts, ","))
+}
+
+// switchHost releases the metadata center accounting of the previous host, and records it for the new one
+func (f *filter) switchHost(address string) {
+	if f.isIncreaseRecorded {
+		f.DecreaseMetaDataCenter()
+	}
+	f.isIncreaseRecorded = false
+	f.isPromptLengthDeleted = false
+	// use a new request id in metadata center for the new host
+	f.uniqueId = ""
+	// the load of the chosen host doesn't apply to the new host
+	f.chosenStats = nil
+
+	ip, _, err := net.SplitHostPort(address)
+	if err != nil {
+		ip = address
+	}
+	f.serverIp = ip
+	f.hostAddress = address
+	// the host chosen by Envoy is not drained by the gateway
+	f.trackHost(nil)
+	f.attemptedHosts = append(f.attemptedHosts, address)
+	f.traceRetry(f.cluster, address)
+
+	f.AddRequest()
+}
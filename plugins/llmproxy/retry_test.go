@@ -0,0 +1,233 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/outlier"
+	mctypes "github.com/aigw-project/aigw/pkg/metadata_center/types"
+	"github.com/aigw-project/aigw/pkg/request"
+	cfg "github.com/aigw-project/aigw/plugins/llmproxy/config"
+)
+
+type fakeMetadataCenter struct {
+	mctypes.MetadataCenter
+	requests map[string]string
+}
+
+func (m *fakeMetadataCenter) AddRequest(_ context.Context, requestId, _, ip string, _ int) error {
+	m.requests[requestId] = ip
+	return nil
+}
+
+func (m *fakeMetadataCenter) DeleteRequest(_ context.Context, requestId string) error {
+	delete(m.requests, requestId)
+	return nil
+}
+
+type retryStreamInfo struct {
+	*envoy.StreamInfo
+	attempts uint32
+	upstream string
+}
+
+func (i *retryStreamInfo) AttemptCount() uint32 {
+	return i.attempts
+}
+
+func (i *retryStreamInfo) UpstreamRemoteAddress() (string, bool) {
+	return i.upstream, i.upstream != ""
+}
+
+func newRetryFilter(policy *cfg.RetryPolicy) (*filter, *fakeMetadataCenter) {
+	mc := &fakeMetadataCenter{requests: map[string]string{}}
+	f := &filter{
+		callbacks: envoy.NewFilterCallbackHandler(),
+		config: &cfg.LLMProxyConfig{
+			Config: cfg.Config{RetryPolicy: policy},
+			MC:     mc,
+		},
+		// no prompt decrease timer for the streaming request
+		isStream:    true,
+		cluster:     "qwen",
+		serverIp:    "10.0.0.1",
+		hostAddress: "10.0.0.1:8000",
+	}
+	return f, mc
+}
+
+func TestSetRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *cfg.RetryPolicy
+		method string
+		want   map[string]string
+	}{
+		{
+			name:   "disabled",
+			policy: &cfg.RetryPolicy{},
+			method: http.MethodPost,
+		},
+		{
+			name:   "completions are retried when not processed",
+			policy: &cfg.RetryPolicy{NumRetries: 2},
+			method: http.MethodPost,
+			want: map[string]string{
+				headerRetryOn:              "connect-failure,refused-stream,retriable-status-codes",
+				headerRetriableStatusCodes: "502,503,504",
+				headerMaxRetries:           "2",
+				headerPerTryTimeout:        "60000",
+			},
+		},
+		{
+			name: "idempotent",
+			policy: &cfg.RetryPolicy{
+				NumRetries:           1,
+				RetriableStatusCodes: []uint32{500},
+				PerTryTimeout:        durationpb.New(5 * time.Second),
+			},
+			method: http.MethodGet,
+			want: map[string]string{
+				headerRetryOn:              "connect-failure,refused-stream,retriable-status-codes,reset",
+				headerRetriableStatusCodes: "500",
+				headerMaxRetries:           "1",
+				headerPerTryTimeout:        "5000",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, _ := newRetryFilter(tt.policy)
+			headers := envoy.NewRequestHeaderMap(http.Header{})
+			headers.SetMethod(tt.method)
+			f.setRetryPolicy(headers)
+
+			for _, key := range []string{headerRetryOn, headerRetriableStatusCodes, headerMaxRetries, headerPerTryTimeout} {
+				value, _ := headers.Get(key)
+				assert.Equal(t, tt.want[key], value, key)
+			}
+			if tt.want == nil {
+				assert.Nil(t, f.attemptedHosts)
+			} else {
+				assert.Equal(t, []string{"10.0.0.1:8000"}, f.attemptedHosts)
+			}
+		})
+	}
+}
+
+func TestReconcileRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		disabled  bool
+		attempts  uint32
+		upstream  string
+		wantHosts []string
+		wantIP    string
+		wantCount int
+	}{
+		{
+			name:      "retry disabled",
+			disabled:  true,
+			attempts:  2,
+			upstream:  "10.0.0.2:8000",
+			wantIP:    "10.0.0.1",
+			wantCount: 0,
+		},
+		{
+			name:      "not retried",
+			attempts:  1,
+			upstream:  "10.0.0.1:8000",
+			wantHosts: []string{"10.0.0.1:8000"},
+			wantIP:    "10.0.0.1",
+		},
+		{
+			name:      "retried on the same host",
+			attempts:  2,
+			upstream:  "10.0.0.1:8000",
+			wantHosts: []string{"10.0.0.1:8000"},
+			wantIP:    "10.0.0.1",
+			wantCount: 1,
+		},
+		{
+			name:      "retried on another host",
+			attempts:  3,
+			upstream:  "10.0.0.3:8000",
+			wantHosts: []string{"10.0.0.1:8000", "10.0.0.3:8000"},
+			wantIP:    "10.0.0.3",
+			wantCount: 2,
+		},
+		{
+			name:      "no upstream address",
+			attempts:  2,
+			wantHosts: []string{"10.0.0.1:8000"},
+			wantIP:    "10.0.0.1",
+			wantCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &cfg.RetryPolicy{NumRetries: 2}
+			if tt.disabled {
+				policy = nil
+			}
+			f, mc := newRetryFilter(policy)
+			// isolate the outlier detection of the cases
+			f.cluster = t.Name()
+			f.callbacks.(interface{ SetStreamInfo(api.StreamInfo) }).SetStreamInfo(&retryStreamInfo{
+				StreamInfo: &envoy.StreamInfo{},
+				attempts:   tt.attempts,
+				upstream:   tt.upstream,
+			})
+			f.setRetryPolicy(envoy.NewRequestHeaderMap(http.Header{}))
+			f.AddRequest()
+			firstID := f.UniqueId()
+
+			f.reconcileRetries()
+
+			assert.Equal(t, tt.wantHosts, f.attemptedHosts)
+			assert.Equal(t, tt.wantCount, f.retryCount)
+			assert.Equal(t, tt.wantIP, f.serverIp)
+			assert.Equal(t, map[string]string{f.UniqueId(): tt.wantIP}, mc.requests)
+			// the request is moved to the new host with a new id in metadata center
+			assert.Equal(t, tt.wantIP != "10.0.0.1", f.UniqueId() != firstID)
+			assert.True(t, f.isIncreaseRecorded)
+			if tt.wantCount > 0 {
+				assert.Equal(t, tt.wantCount, request.GetLogField(f.callbacks)["retry_count"])
+			}
+
+			// only the first host is known to be failed
+			failed := map[string]int{}
+			for _, s := range outlier.GetDetector().Status() {
+				if s.Cluster == f.cluster {
+					failed[s.Address] = s.WindowErrors
+				}
+			}
+			want := map[string]int{}
+			if tt.wantIP != "10.0.0.1" {
+				want["10.0.0.1:8000"] = 1
+			}
+			assert.Equal(t, want, failed)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmproxy
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/trace"
+)
+
+const (
+	spanRequest          = "llmproxy"
+	spanTranscodeReq     = "llmproxy.transcode_request"
+	spanEncodeReq        = "llmproxy.encode_request"
+	spanLoadBalance      = "llmproxy.choose_host"
+	spanUpstream         = "llmproxy.upstream"
+	eventFirstToken      = "first_token"
+	eventRetry           = "retry"
+	attrModel            = "llm.model"
+	attrCluster          = "aigw.cluster"
+	attrHost             = "server.address"
+	attrStream           = "llm.stream"
+	attrStatus           = "http.response.status_code"
+	attrPromptTokens     = "llm.usage.prompt_tokens"
+	attrCompletionTokens = "llm.usage.completion_tokens"
+	attrTTFT             = "llm.ttft_ms"
+)
+
+// startTrace starts the span of the whole request, and uses the trace id of it as the request trace id
+func (f *filter) startTrace(headers api.RequestHeaderMap) {
+	f.traceCtx, f.requestSpan = trace.StartRequest(f.callbacks, headers, spanRequest)
+	f.traceId = f.requestSpan.SpanContext().TraceID().String()
+}
+
+// startSpan starts a child span of the request span
+func (f *filter) startSpan(name string, opts ...oteltrace.SpanStartOption) (context.Context, oteltrace.Span) {
+	parent := f.traceCtx
+	if parent == nil {
+		parent = context.Background()
+	}
+	return trace.StartSpan(parent, name, opts...)
+}
+
+func endSpan(span oteltrace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startUpstreamSpan starts the span of the upstream request, and propagates its context to the inference engine
+func (f *filter) startUpstreamSpan(headers api.RequestHeaderMap) {
+	var ctx context.Context
+	ctx, f.upstreamSpan = f.startSpan(spanUpstream,
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(
+			attribute.String(attrModel, f.modelName),
+			attribute.String(attrCluster, f.cluster),
+			attribute.String(attrHost, f.hostAddress),
+			attribute.Bool(attrStream, f.isStream),
+		))
+	trace.Inject(ctx, headers)
+}
+
+func (f *filter) traceFirstToken() {
+	if f.upstreamSpan != nil {
+		f.upstreamSpan.AddEvent(eventFirstToken)
+	}
+}
+
+func (f *filter) traceRetry(cluster, host string) {
+	if f.upstreamSpan != nil {
+		f.upstreamSpan.AddEvent(eventRetry, oteltrace.WithAttributes(
+			attribute.String(attrCluster, cluster),
+			attribute.String(attrHost, host),
+		))
+	}
+}
+
+// finishTrace ends the spans of the request, it's called in OnLog
+func (f *filter) finishTrace(respHeaders api.ResponseHeaderMap) {
+	if f.requestSpan == nil {
+		return
+	}
+
+	status := 0
+	if respHeaders != nil {
+		status, _ = respHeaders.Status()
+	}
+	if f.upstreamSpan != nil {
+		attrs := []attribute.KeyValue{
+			attribute.Int(attrStatus, f.upstreamStatus),
+			attribute.String(attrHost, f.hostAddress),
+			attribute.Int64(attrTTFT, f.getTtft().Milliseconds()),
+		}
+		if f.transcoder != nil {
+			if logItems := f.transcoder.GetLLMLogItems(); logItems != nil {
+				usage := logItems.GetUsage()
+				attrs = append(attrs,
+					attribute.Int64(attrPromptTokens, usage.PromptTokens),
+					attribute.Int64(attrCompletionTokens, usage.CompletionTokens),
+				)
+			}
+		}
+		f.upstreamSpan.SetAttributes(attrs...)
+		if f.upstreamStatus >= 500 || f.upstreamStatus == 0 {
+			f.upstreamSpan.SetStatus(codes.Error, "upstream failed")
+		}
+		f.upstreamSpan.End()
+	}
+
+	f.requestSpan.SetAttributes(
+		attribute.Int(attrStatus, status),
+		attribute.String(attrModel, f.modelName),
+		attribute.String(attrCluster, f.cluster),
+	)
+	if status >= 500 {
+		f.requestSpan.SetStatus(codes.Error, "request failed")
+	}
+	f.requestSpan.End()
+}
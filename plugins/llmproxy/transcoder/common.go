@@ -15,24 +15,11 @@
 package transcoder
 
 import (
-	"os"
-	"sync"
-)
-
-const (
-	splitReasoningEnv = "AIGW_AI_PROXY_SPLIT_REASONING"
-)
-
-var (
-	splitReasoningOnce sync.Once
-	splitReasoning     bool
+	"github.com/aigw-project/aigw/pkg/runtimeconfig"
 )
 
+// IsSplitReasoningEnabled returns whether to return the reasoning content in a separate field,
+// it can be changed via the runtime config
 func IsSplitReasoningEnabled() bool {
-	splitReasoningOnce.Do(func() {
-		if os.Getenv(splitReasoningEnv) == "true" {
-			splitReasoning = true
-		}
-	})
-	return splitReasoning
+	return runtimeconfig.Get().SplitReasoning
 }
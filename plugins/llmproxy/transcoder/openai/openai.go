@@ -34,6 +34,7 @@ import (
 	"github.com/aigw-project/aigw/pkg/aigateway/loadbalancer/lboptions"
 	"github.com/aigw-project/aigw/pkg/aigateway/openai"
 	"github.com/aigw-project/aigw/pkg/simplejson"
+	"github.com/aigw-project/aigw/pkg/trace"
 	cfg "github.com/aigw-project/aigw/plugins/llmproxy/config"
 	"github.com/aigw-project/aigw/plugins/llmproxy/log"
 	"github.com/aigw-project/aigw/plugins/llmproxy/transcoder"
@@ -45,6 +46,9 @@ const (
 	FirstChunkError       = "First chunk error"
 
 	DefaultHashSpaceLength = 4096
+
+	// ConversationIDKey is the key of the conversation ID in the metadata of the request
+	ConversationIDKey = "conversation_id"
 )
 
 var (
@@ -123,12 +127,16 @@ func (t *openAiChatCompletionTranscoder) GetRequestData(headers api.RequestHeade
 		reqData.SceneName = targetModel.SceneName
 		reqData.BackendProtocol = targetModel.Backend
 		reqData.Cluster = targetModel.Cluster
+		reqData.BackupCluster = targetModel.BackupCluster
+		reqData.Clusters = targetModel.Clusters
 
 		// support lora and multi version
 		reqData.LbOptions = lboptions.NewLoadBalancerOptions(targetModel.RouteName, targetModel.Headers, targetModel.Subset)
 	}
 
 	reqData.PromptContext = t.getPromptMessageContent()
+	reqData.User = t.openAiChatMessage.User.Value
+	reqData.ConversationID = t.openAiChatMessage.Metadata[ConversationIDKey]
 	t.logItems.ModelName = reqData.ModelName
 	api.LogDebugf("reqData: %+v", reqData)
 	return
@@ -249,9 +257,7 @@ func (t *openAiChatCompletionTranscoder) extractReasoningContentFromChunk(chunk
 
 // onverwrite model name & split reasoning content
 func (t *openAiChatCompletionTranscoder) convertOpenAIChatCompletion(originalModelResult *openai.OpenAIChatCompletion) {
-	traceId := "TODO"
-	// api.LogInfof("traceId: %s", traceId)
-	// api.LogInfof("model: %s", t.openAiChatMessage.Model)
+	traceId := trace.GetTraceID(t.callbacks, nil)
 	originalModelResult.Id = fmt.Sprintf("%s-%s", t.openAiChatMessage.Model, traceId)
 	originalModelResult.Model = t.openAiChatMessage.Model
 
@@ -263,9 +269,7 @@ func (t *openAiChatCompletionTranscoder) convertOpenAIChatCompletion(originalMod
 }
 
 func (t *openAiChatCompletionTranscoder) convertOpenAIChatCompletionChunk(originalModelChunkResult *openai.OpenAIChatCompletionChunk) {
-	traceId := "TODO"
-	// api.LogInfof("traceId: %s", traceId)
-	// api.LogInfof("model: %s", t.openAiChatMessage.Model)
+	traceId := trace.GetTraceID(t.callbacks, nil)
 	originalModelChunkResult.Id = fmt.Sprintf("%s-%s", t.openAiChatMessage.Model, traceId)
 	originalModelChunkResult.Model = t.openAiChatMessage.Model
 
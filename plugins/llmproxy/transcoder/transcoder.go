@@ -27,9 +27,16 @@ type RequestData struct {
 	SceneName       string
 	Env             string
 	Cluster         string
+	BackupCluster   string
 	BackendProtocol string
 	LbOptions       *lboptions.LoadBalancerOptions
 	PromptContext   *PromptMessageContext
+	// Clusters serving the model instead of the single Cluster, not ordered
+	Clusters []*cfg.ServingCluster
+	// User is the end user of the request, used as the session affinity key
+	User string
+	// ConversationID identifies the multi-turn chat, used as the session affinity key
+	ConversationID string
 }
 
 // PromptMessageContext is used to store the context of request message
@@ -15,5 +15,7 @@
 package plugins
 
 import (
+	_ "github.com/aigw-project/aigw/plugins/aigwruntime"
 	_ "github.com/aigw-project/aigw/plugins/llmproxy"
+	_ "github.com/aigw-project/aigw/plugins/tokenratelimit"
 )
@@ -0,0 +1,103 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenratelimit
+
+import (
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/pkg/plugins"
+
+	"github.com/aigw-project/aigw/pkg/ratelimit"
+	cfg "github.com/aigw-project/aigw/plugins/tokenratelimit/config"
+)
+
+const (
+	Name = "tokenratelimit"
+)
+
+func init() {
+	plugins.RegisterPlugin(Name, &plugin{})
+}
+
+type plugin struct {
+	plugins.PluginMethodDefaultImpl
+}
+
+func (p *plugin) Type() plugins.PluginType {
+	return plugins.TypeTraffic
+}
+
+// Order runs before llmproxy, so that the rejected requests won't be scheduled to the inference servers
+func (p *plugin) Order() plugins.PluginOrder {
+	return plugins.PluginOrder{
+		Position:  plugins.OrderPositionAccess,
+		Operation: plugins.OrderOperationInsertFirst,
+	}
+}
+
+func (p *plugin) Config() api.PluginConfig {
+	return &config{}
+}
+
+func filterFactory(c interface{}, callbacks api.FilterCallbackHandler) api.Filter {
+	return &filter{
+		callbacks: callbacks,
+		config:    c.(*config),
+	}
+}
+
+func (p *plugin) Factory() api.FilterFactory {
+	return filterFactory
+}
+
+type config struct {
+	cfg.Config
+
+	backend ratelimit.Backend
+}
+
+func (c *config) Init(cb api.ConfigCallbackHandler) error {
+	backend, err := ratelimit.GetBackend(c.GetBackend())
+	if err != nil {
+		return err
+	}
+	c.backend = backend
+	return nil
+}
+
+// findLimit returns the limit of the most specific rule: consumer and model > consumer > model > default
+func (c *config) findLimit(consumer, model string) *cfg.Limit {
+	var res *cfg.Limit
+	best := -1
+	for _, rule := range c.GetRules() {
+		if rule.Consumer != "" && rule.Consumer != consumer {
+			continue
+		}
+		if rule.Model != "" && rule.Model != model {
+			continue
+		}
+		score := 0
+		if rule.Consumer != "" {
+			score += 2
+		}
+		if rule.Model != "" {
+			score++
+		}
+		if score > best {
+			best = score
+			res = rule.Limit
+		}
+	}
+	return res
+}
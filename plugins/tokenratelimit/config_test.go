@@ -0,0 +1,95 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenratelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	cfg "github.com/aigw-project/aigw/plugins/tokenratelimit/config"
+)
+
+func TestFindLimit(t *testing.T) {
+	def := &cfg.Limit{RequestsPerMinute: 1}
+	model := &cfg.Limit{RequestsPerMinute: 2}
+	consumer := &cfg.Limit{RequestsPerMinute: 3}
+	both := &cfg.Limit{RequestsPerMinute: 4}
+	c := &config{Config: cfg.Config{Rules: []*cfg.Rule{
+		{Consumer: "alice", Model: "qwen", Limit: both},
+		{Model: "qwen", Limit: model},
+		{Limit: def},
+		{Consumer: "alice", Limit: consumer},
+	}}}
+
+	tests := []struct {
+		consumer string
+		model    string
+		want     *cfg.Limit
+	}{
+		{consumer: "alice", model: "qwen", want: both},
+		{consumer: "alice", model: "deepseek", want: consumer},
+		{consumer: "bob", model: "qwen", want: model},
+		{consumer: "bob", model: "deepseek", want: def},
+		{consumer: anonymousConsumer, model: "qwen", want: model},
+	}
+	for _, tt := range tests {
+		assert.Same(t, tt.want, c.findLimit(tt.consumer, tt.model), "%s/%s", tt.consumer, tt.model)
+	}
+
+	// no default rule
+	c = &config{Config: cfg.Config{Rules: []*cfg.Rule{{Model: "qwen", Limit: model}}}}
+	assert.Nil(t, c.findLimit("alice", "deepseek"))
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		conf *cfg.Config
+		err  bool
+	}{
+		{
+			name: "ok",
+			conf: &cfg.Config{Rules: []*cfg.Rule{{Limit: &cfg.Limit{TokensPerMinute: 100}}}},
+		},
+		{
+			name: "no rules",
+			conf: &cfg.Config{},
+			err:  true,
+		},
+		{
+			name: "no limit",
+			conf: &cfg.Config{Rules: []*cfg.Rule{{Model: "qwen"}}},
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.conf.Validate()
+			if tt.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	c := &config{Config: cfg.Config{Backend: "unknown"}}
+	assert.Error(t, c.Init(nil))
+	c = &config{}
+	assert.NoError(t, c.Init(nil))
+	assert.NotNil(t, c.backend)
+}
@@ -0,0 +1,158 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+
+	"github.com/aigw-project/aigw/pkg/aigateway"
+	"github.com/aigw-project/aigw/pkg/errcode"
+	"github.com/aigw-project/aigw/pkg/prom"
+	"github.com/aigw-project/aigw/pkg/ratelimit"
+	"github.com/aigw-project/aigw/pkg/request"
+	"github.com/aigw-project/aigw/pkg/trace"
+	"github.com/aigw-project/aigw/plugins/llmproxy"
+	llmlog "github.com/aigw-project/aigw/plugins/llmproxy/log"
+)
+
+const anonymousConsumer = "anonymous"
+
+type filter struct {
+	api.PassThroughFilter
+	callbacks api.FilterCallbackHandler
+	config    *config
+
+	traceId string
+	model   string
+	tpmKey  string
+	tpm     uint64
+	// the estimated tokens taken from the tokens-per-minute bucket
+	reservedTokens uint64
+}
+
+func (f *filter) DecodeHeaders(headers api.RequestHeaderMap, endStream bool) api.ResultAction {
+	if endStream {
+		// llmproxy will reject the request without body
+		return api.Continue
+	}
+	return api.WaitAllData
+}
+
+// consumerName returns the consumer authenticated by HTNN, the requests can't choose their own quotas
+func (f *filter) consumerName() string {
+	if consumer := f.callbacks.GetConsumer(); consumer != nil {
+		return consumer.Name()
+	}
+	return anonymousConsumer
+}
+
+func (f *filter) rateLimited(limitType string, retryAfter time.Duration) api.ResultAction {
+	api.LogInfof("token rate limit rejects request, model: %s, type: %s, retry after: %s, trace_id: %s",
+		f.model, limitType, retryAfter, f.traceId)
+	prom.TokenRateLimitRejectedTotal.WithLabelValues(limitType).Inc()
+	request.SetLogField(f.callbacks, "ratelimit_type", limitType)
+
+	header := http.Header{}
+	header.Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds())))))
+	msg := fmt.Sprintf("Rate limit reached for %s", limitType)
+	return aigateway.NewGatewayErrorResponseWithMsg(f.traceId, header, http.StatusTooManyRequests, &errcode.RateLimitError, msg)
+}
+
+func (f *filter) DecodeRequest(headers api.RequestHeaderMap, buffer api.BufferInstance, trailers api.RequestTrailerMap) api.ResultAction {
+	f.traceId = trace.GetTraceID(f.callbacks, headers)
+
+	model, tokens, err := ratelimit.EstimateRequest(buffer.Bytes())
+	if err != nil {
+		// leave the invalid request to llmproxy
+		api.LogDebugf("token rate limit fails to parse request: %v, trace_id: %s", err, f.traceId)
+		return api.Continue
+	}
+	f.model = model
+
+	consumer := f.consumerName()
+	limit := f.config.findLimit(consumer, model)
+	if limit == nil {
+		return api.Continue
+	}
+
+	ctx := context.Background()
+	backend := f.config.backend
+	key := consumer + "/" + model
+	rpmKey := "rpm:" + key
+	rpm := uint64(limit.GetRequestsPerMinute())
+	if rpm > 0 {
+		res, err := backend.Take(ctx, rpmKey, rpm, 1)
+		if err != nil {
+			// fail open, the rate limit backend should not break the traffic
+			api.LogErrorf("token rate limit backend error: %v, trace_id: %s", err, f.traceId)
+			return api.Continue
+		}
+		if !res.Allowed {
+			return f.rateLimited("requests", res.RetryAfter)
+		}
+	}
+
+	tpm := limit.GetTokensPerMinute()
+	if tpm > 0 {
+		f.tpmKey = "tpm:" + key
+		res, err := backend.Take(ctx, f.tpmKey, tpm, tokens)
+		if err != nil {
+			api.LogErrorf("token rate limit backend error: %v, trace_id: %s", err, f.traceId)
+			return api.Continue
+		}
+		if !res.Allowed {
+			if rpm > 0 {
+				// the request is not sent, give back the request quota
+				_ = backend.Adjust(ctx, rpmKey, rpm, -1)
+			}
+			return f.rateLimited("tokens", res.RetryAfter)
+		}
+		f.tpm = tpm
+		f.reservedTokens = tokens
+	}
+
+	request.SetLogField(f.callbacks, "estimated_prompt_tokens", tokens)
+	return api.Continue
+}
+
+// OnLog reconciles the estimated tokens with the actual usage reported by the inference server
+func (f *filter) OnLog(reqHeaders api.RequestHeaderMap, reqTrailers api.RequestTrailerMap,
+	respHeaders api.ResponseHeaderMap, respTrailers api.ResponseTrailerMap) {
+	if f.tpm == 0 {
+		return
+	}
+
+	items, ok := f.callbacks.PluginState().Get(llmproxy.LLMProxyFilterName, llmproxy.LLMLogItemsKey).(*llmlog.LLMLogItems)
+	if !ok || items == nil {
+		return
+	}
+	usage := items.GetUsage()
+	if usage.TotalTokens <= 0 {
+		// no usage in response, e.g. error response, keep the estimated tokens
+		return
+	}
+
+	delta := usage.TotalTokens - int64(f.reservedTokens)
+	if err := f.config.backend.Adjust(context.Background(), f.tpmKey, f.tpm, delta); err != nil {
+		api.LogErrorf("token rate limit backend error: %v, trace_id: %s", err, f.traceId)
+	}
+}
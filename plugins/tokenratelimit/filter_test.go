@@ -0,0 +1,191 @@
+// Copyright The AIGW Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenratelimit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	openaigo "github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"mosn.io/htnn/api/pkg/filtermanager/api"
+	"mosn.io/htnn/api/plugins/tests/pkg/envoy"
+
+	"github.com/aigw-project/aigw/pkg/ratelimit"
+	"github.com/aigw-project/aigw/plugins/llmproxy"
+	llmlog "github.com/aigw-project/aigw/plugins/llmproxy/log"
+	cfg "github.com/aigw-project/aigw/plugins/tokenratelimit/config"
+)
+
+type testConsumer struct {
+	name string
+}
+
+func (c *testConsumer) Name() string {
+	return c.name
+}
+
+func (c *testConsumer) PluginConfig(string) api.PluginConsumerConfig {
+	return nil
+}
+
+const requestBody = `{"model":"qwen","messages":[{"role":"user","content":"hello, how are you today?"}]}`
+
+// requestTokens is the estimated tokens of requestBody
+func requestTokens(t *testing.T) uint64 {
+	_, tokens, err := ratelimit.EstimateRequest([]byte(requestBody))
+	assert.NoError(t, err)
+	assert.Greater(t, tokens, uint64(0))
+	return tokens
+}
+
+func newTestConfig(rules ...*cfg.Rule) *config {
+	return &config{
+		Config:  cfg.Config{Rules: rules},
+		backend: ratelimit.NewLocalBackend(),
+	}
+}
+
+// decode runs the request through a new filter, and returns the filter and the result
+func decode(c *config, consumer string) (*filter, api.ResultAction) {
+	callbacks := envoy.NewFilterCallbackHandler()
+	if consumer != "" {
+		callbacks.SetConsumer(&testConsumer{name: consumer})
+	}
+	f := filterFactory(c, callbacks).(*filter)
+	headers := envoy.NewRequestHeaderMap(http.Header{})
+	res := f.DecodeRequest(headers, envoy.NewBufferInstance([]byte(requestBody)), nil)
+	return f, res
+}
+
+func assertRateLimited(t *testing.T, res api.ResultAction, limitType string) {
+	resp, ok := res.(*api.LocalResponse)
+	if !assert.True(t, ok, "%v", res) {
+		return
+	}
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Contains(t, resp.Msg, limitType)
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+}
+
+func TestRequestsPerMinute(t *testing.T) {
+	c := newTestConfig(&cfg.Rule{Consumer: "alice", Limit: &cfg.Limit{RequestsPerMinute: 2}})
+
+	for range 2 {
+		_, res := decode(c, "alice")
+		assert.Equal(t, api.Continue, res)
+	}
+	_, res := decode(c, "alice")
+	assertRateLimited(t, res, "requests")
+
+	// the quotas are counted per consumer, and the other consumers are not limited
+	_, res = decode(c, "bob")
+	assert.Equal(t, api.Continue, res)
+}
+
+func TestTenantHeaderIgnored(t *testing.T) {
+	c := newTestConfig(
+		&cfg.Rule{Consumer: "vip", Limit: &cfg.Limit{RequestsPerMinute: 100}},
+		&cfg.Rule{Limit: &cfg.Limit{RequestsPerMinute: 1}},
+	)
+
+	callbacks := envoy.NewFilterCallbackHandler()
+	f := filterFactory(c, callbacks).(*filter)
+	headers := envoy.NewRequestHeaderMap(http.Header{"X-Tenant": []string{"vip"}})
+	assert.Equal(t, api.Continue, f.DecodeRequest(headers, envoy.NewBufferInstance([]byte(requestBody)), nil))
+	assert.Equal(t, anonymousConsumer, f.consumerName())
+
+	// the anonymous requests share the default quota
+	_, res := decode(c, "")
+	assertRateLimited(t, res, "requests")
+}
+
+func TestRequestQuotaRefundedWhenTokensRejected(t *testing.T) {
+	tokens := requestTokens(t)
+	c := newTestConfig(&cfg.Rule{Limit: &cfg.Limit{RequestsPerMinute: 2, TokensPerMinute: tokens}})
+
+	// the first request takes all the tokens, it doesn't finish so the estimation is kept
+	_, res := decode(c, "alice")
+	assert.Equal(t, api.Continue, res)
+	_, res = decode(c, "alice")
+	assertRateLimited(t, res, "tokens")
+
+	// the rejected request is not sent, so its request quota is given back
+	rpm, err := c.backend.Take(context.Background(), "rpm:alice/qwen", 2, 1)
+	assert.NoError(t, err)
+	assert.True(t, rpm.Allowed)
+}
+
+func TestOnLogReconcileTokens(t *testing.T) {
+	tokens := requestTokens(t)
+
+	finish := func(f *filter, totalTokens int64) {
+		items := &llmlog.LLMLogItems{}
+		items.AppendOpenAIResponse(&openaigo.Completion{
+			Usage: openaigo.CompletionUsage{
+				PromptTokens:        totalTokens,
+				TotalTokens:         totalTokens,
+				PromptTokensDetails: &openaigo.CompletionUsagePromptTokensDetails{},
+			},
+		})
+		f.callbacks.PluginState().Set(llmproxy.LLMProxyFilterName, llmproxy.LLMLogItemsKey, items)
+		f.OnLog(nil, nil, nil, nil)
+	}
+
+	tests := []struct {
+		name        string
+		totalTokens int64
+		// the tokens left in the bucket after the request
+		available uint64
+	}{
+		{
+			// the response uses more tokens than estimated, which are consumed from the bucket
+			name:        "more than estimated",
+			totalTokens: int64(tokens) * 3,
+			available:   0,
+		},
+		{
+			// the unused estimation is given back
+			name:        "less than estimated",
+			totalTokens: 1,
+			available:   tokens*2 - 1,
+		},
+		{
+			// no usage, e.g. the error response, the estimation is kept
+			name:      "no usage",
+			available: tokens,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestConfig(&cfg.Rule{Limit: &cfg.Limit{TokensPerMinute: tokens * 2}})
+			f, res := decode(c, "alice")
+			assert.Equal(t, api.Continue, res)
+			assert.Equal(t, tokens, f.reservedTokens)
+			finish(f, tt.totalTokens)
+
+			ctx := context.Background()
+			res2, err := c.backend.Take(ctx, f.tpmKey, tokens*2, tt.available+1)
+			assert.NoError(t, err)
+			assert.False(t, res2.Allowed)
+			if tt.available > 0 {
+				res2, err = c.backend.Take(ctx, f.tpmKey, tokens*2, tt.available)
+				assert.NoError(t, err)
+				assert.True(t, res2.Allowed)
+			}
+		})
+	}
+}